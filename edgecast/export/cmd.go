@@ -0,0 +1,266 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package export
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"terraform-provider-edgecast/edgecast"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// Commands holds the subcommands of the provider binary, keyed by name. Each
+// is run with the arguments that follow its name and returns an exit code.
+// The provider is configured from the usual EDGECAST_* environment variables.
+var Commands = map[string]func(args []string, stdout, stderr io.Writer) int{
+	"export":   runExport,
+	"snapshot": runSnapshot,
+	"diff":     runDiff,
+}
+
+const usageAccount = "Configure the account with EDGECAST_* environment " +
+	"variables.\n"
+
+func runExport(args []string, stdout io.Writer, stderr io.Writer) int {
+	var out string
+
+	flags, opts := newFlagSet("export", "[options]", stderr,
+		"Writes Terraform configuration with import blocks for the objects "+
+			"in an\nEdgecast account. "+usageAccount)
+	flags.StringVar(&out, "out", "",
+		"file to write the configuration to, instead of standard output")
+
+	if code, ok := parse(flags, args); !ok {
+		return code
+	}
+
+	ctx := context.Background()
+	p, ok := configure(ctx, stderr)
+	if !ok {
+		return 1
+	}
+
+	w := stdout
+	if len(out) > 0 {
+		f, err := os.Create(out)
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			return 1
+		}
+		defer f.Close()
+
+		w = f
+	}
+
+	if err := Export(ctx, p, w, opts()); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	warnUncovered(stderr, p, opts())
+
+	return 0
+}
+
+func runSnapshot(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags, opts := newFlagSet("snapshot", "[options] DIR", stderr,
+		"Writes the configuration of the objects in an Edgecast account to "+
+			"DIR, as\nnormalized JSON with one file per object. DIR must not "+
+			"exist or be empty.\n"+usageAccount)
+
+	if code, ok := parse(flags, args); !ok {
+		return code
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return 2
+	}
+
+	ctx := context.Background()
+	p, ok := configure(ctx, stderr)
+	if !ok {
+		return 1
+	}
+
+	s, err := TakeSnapshot(ctx, p, opts())
+	if err == nil {
+		err = WriteSnapshot(flags.Arg(0), s)
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	warnUncovered(stderr, p, opts())
+
+	return 0
+}
+
+// runDiff exits with 0 if there are no changes and 1 if there are, like
+// diff(1). Errors exit with 2.
+func runDiff(args []string, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("diff", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprint(stderr,
+			"Usage: terraform-provider-edgecast diff FROM [TO]\n\n"+
+				"Reports the objects added, removed and changed between two "+
+				"snapshots. If TO\nis omitted, FROM is compared with the "+
+				"account's live configuration.\n"+usageAccount+
+				"\nExits with 0 if there are no changes, 1 if there are and "+
+				"2 on errors.\n")
+	}
+
+	if err := flags.Parse(args); errors.Is(err, flag.ErrHelp) {
+		return 0
+	} else if err != nil {
+		return 2
+	}
+
+	if flags.NArg() < 1 || flags.NArg() > 2 {
+		flags.Usage()
+		return 2
+	}
+
+	from, err := ReadSnapshot(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	var to Snapshot
+	if flags.NArg() == 2 {
+		to, err = ReadSnapshot(flags.Arg(1))
+	} else {
+		ctx := context.Background()
+		p, ok := configure(ctx, stderr)
+		if !ok {
+			return 2
+		}
+
+		opts := LiveOptions(from)
+		to, err = TakeSnapshot(ctx, p, opts)
+		if err == nil {
+			warnUncovered(stderr, p, opts)
+		}
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	changes := Diff(from, to)
+	if err := WriteChanges(stdout, changes); err != nil {
+		fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 2
+	}
+
+	if len(changes) > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// newFlagSet returns a flag set with the options that select objects, and a
+// function that returns those options once the flags are parsed.
+func newFlagSet(
+	name string,
+	arguments string,
+	stderr io.Writer,
+	description string,
+) (*flag.FlagSet, func() Options) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr,
+			"Usage: terraform-provider-edgecast %s %s\n\n%s\nOptions:\n",
+			name,
+			arguments,
+			description)
+		flags.PrintDefaults()
+		fmt.Fprintf(stderr, "\nResource types that can be listed:\n  %s\n",
+			strings.Join(ListedResources(), "\n  "))
+	}
+
+	var resources string
+	opts := Options{IDs: make(map[string][]string)}
+
+	flags.StringVar(&resources, "resources", "",
+		"comma-separated resource types to list, instead of all that can be")
+	flags.Func("id",
+		"select an object by import ID, as resource_type=import_id, e.g. "+
+			"edgecast_dns_zone=ACCOUNT:1234; may be repeated",
+		func(v string) error {
+			name, id, ok := strings.Cut(v, "=")
+			if !ok || len(name) == 0 || len(id) == 0 {
+				return fmt.Errorf("expected resource_type=import_id, got %q", v)
+			}
+
+			opts.IDs[name] = append(opts.IDs[name], id)
+			return nil
+		})
+
+	return flags, func() Options {
+		if len(resources) > 0 {
+			opts.Resources = strings.Split(resources, ",")
+		}
+
+		return opts
+	}
+}
+
+// warnUncovered writes the resource types whose objects were not read to
+// stderr, so that the output is not mistaken for the whole account.
+func warnUncovered(stderr io.Writer, p *schema.Provider, opts Options) {
+	uncovered := UncoveredResources(p, opts)
+	if len(uncovered) == 0 {
+		return
+	}
+
+	fmt.Fprintf(stderr,
+		"Warning: objects of these resource types were not read, since "+
+			"they were neither\nlisted nor selected by import ID:\n  %s\n",
+		strings.Join(uncovered, "\n  "))
+}
+
+// parse parses args, and returns false with the exit code if the command
+// should not run, e.g. because help was requested.
+func parse(flags *flag.FlagSet, args []string) (int, bool) {
+	err := flags.Parse(args)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0, false
+	}
+
+	if err != nil {
+		return 2, false
+	}
+
+	return 0, true
+}
+
+// configure returns the provider configured from the environment. Errors are
+// written to stderr.
+func configure(ctx context.Context, stderr io.Writer) (*schema.Provider, bool) {
+	p := edgecast.Provider()
+
+	diags := p.Configure(ctx, terraform.NewResourceConfigRaw(map[string]any{}))
+	if diags.HasError() {
+		fmt.Fprintf(stderr, "Error configuring provider: %v\n", diags)
+		return nil, false
+	}
+
+	return p, true
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package export
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind describes how an object changed between two snapshots.
+type ChangeKind string
+
+const (
+	Added   ChangeKind = "added"
+	Removed ChangeKind = "removed"
+	Changed ChangeKind = "changed"
+)
+
+// Change is a difference between two snapshots.
+type Change struct {
+	ResourceType string
+	ImportID     string
+	Name         string
+	Kind         ChangeKind
+
+	// Attributes holds the paths of the attributes that changed, in order,
+	// e.g. ip.0.blacklist. It is only set for changed objects.
+	Attributes []string
+}
+
+// Diff returns the objects that were added to, removed from or changed in
+// to since from, in order of resource type and import ID.
+func Diff(from Snapshot, to Snapshot) []Change {
+	names := make(map[string]bool)
+	for name := range from {
+		names[name] = true
+	}
+
+	for name := range to {
+		names[name] = true
+	}
+
+	var changes []Change
+	for _, name := range sortedKeys(names) {
+		ids := make(map[string]bool)
+		for id := range from[name] {
+			ids[id] = true
+		}
+
+		for id := range to[name] {
+			ids[id] = true
+		}
+
+		for _, id := range sortedKeys(ids) {
+			prev, inFrom := from[name][id]
+			curr, inTo := to[name][id]
+
+			change := Change{ResourceType: name, ImportID: id}
+			switch {
+			case !inTo:
+				change.Kind = Removed
+				change.Name = prev.Name
+			case !inFrom:
+				change.Kind = Added
+				change.Name = curr.Name
+			default:
+				change.Kind = Changed
+				change.Name = curr.Name
+				diffValues(
+					"",
+					prev.Attributes,
+					curr.Attributes,
+					&change.Attributes)
+
+				if len(change.Attributes) == 0 {
+					continue
+				}
+			}
+
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
+
+// diffValues adds the paths of the values that differ between a and b to
+// paths. Maps are compared by key and lists of the same length by element.
+func diffValues(path string, a any, b any, paths *[]string) {
+	if reflect.DeepEqual(a, b) {
+		return
+	}
+
+	switch a := a.(type) {
+	case map[string]any:
+		if b, ok := b.(map[string]any); ok {
+			keys := make(map[string]bool)
+			for key := range a {
+				keys[key] = true
+			}
+
+			for key := range b {
+				keys[key] = true
+			}
+
+			for _, key := range sortedKeys(keys) {
+				diffValues(joinPath(path, key), a[key], b[key], paths)
+			}
+
+			return
+		}
+	case []any:
+		if b, ok := b.([]any); ok && len(a) == len(b) {
+			for i := range a {
+				diffValues(joinPath(path, strconv.Itoa(i)), a[i], b[i], paths)
+			}
+
+			return
+		}
+	}
+
+	*paths = append(*paths, path)
+}
+
+func joinPath(path string, key string) string {
+	if len(path) == 0 {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// WriteChanges writes a line for each change to w, prefixed with + for added,
+// - for removed and ~ for changed objects.
+func WriteChanges(w io.Writer, changes []Change) error {
+	prefixes := map[ChangeKind]string{Added: "+", Removed: "-", Changed: "~"}
+
+	for _, c := range changes {
+		line := fmt.Sprintf(
+			"%s %s %s",
+			prefixes[c.Kind],
+			c.ResourceType,
+			c.ImportID)
+		if len(c.Name) > 0 {
+			line += fmt.Sprintf(" (%s)", c.Name)
+		}
+
+		if len(c.Attributes) > 0 {
+			line += ": " + strings.Join(c.Attributes, ", ")
+		}
+
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
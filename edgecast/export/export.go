@@ -0,0 +1,286 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+// Package export generates Terraform configuration for the objects in an
+// Edgecast account, so that an existing account can be brought under
+// Terraform's management.
+//
+// Each object is read through its resource's importer and then its read
+// function, the same way that terraform import reads it, and written as a resource block together with a
+// Terraform 1.5 import block.
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Options controls what is exported.
+type Options struct {
+	// Resources limits listing to the given resource types. If empty, all
+	// types that can be listed are.
+	Resources []string
+
+	// IDs holds the import IDs of objects to export in addition to the
+	// listed ones, keyed by resource type. Objects of types that the APIs
+	// cannot list, e.g. DNS zones, can only be exported this way.
+	IDs map[string][]string
+}
+
+// listed returns the resource types whose objects opts lists.
+func (opts Options) listed() []string {
+	if len(opts.Resources) == 0 && len(opts.IDs) == 0 {
+		return ListedResources()
+	}
+
+	return opts.Resources
+}
+
+// UncoveredResources returns the resource types of p that opts neither lists
+// nor selects objects of by import ID, in order, e.g. DNS zones when no import
+// IDs are given for them, since the APIs cannot list them.
+func UncoveredResources(p *schema.Provider, opts Options) []string {
+	covered := make(map[string]bool)
+	for _, name := range opts.listed() {
+		covered[name] = true
+	}
+
+	for name := range opts.IDs {
+		covered[name] = true
+	}
+
+	var uncovered []string
+	for _, name := range sortedKeys(p.ResourcesMap) {
+		if !covered[name] {
+			uncovered = append(uncovered, name)
+		}
+	}
+
+	return uncovered
+}
+
+// ListedResources returns the resource types whose objects can be listed, in
+// order.
+func ListedResources() []string {
+	names := make([]string, 0, len(listers))
+	for name := range listers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Export writes configuration for the objects selected by opts to w. The
+// provider must be configured. Objects that no longer exist when they are
+// read are skipped.
+func Export(
+	ctx context.Context,
+	p *schema.Provider,
+	w io.Writer,
+	opts Options,
+) error {
+	f := newFile()
+	labels := make(map[string]int)
+
+	add := func(
+		name string,
+		r *schema.Resource,
+		obj object,
+		d *schema.ResourceData,
+	) {
+		label := uniqueLabel(labels, name, obj.name)
+		f.addImport(name, label, obj.importID)
+		f.addResource(r, d, name, label)
+	}
+
+	err := readObjects(ctx, p, opts, add)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(f.bytes())
+
+	return err
+}
+
+// readFunc receives an object read into d by the importer of resource r.
+type readFunc func(
+	name string,
+	r *schema.Resource,
+	obj object,
+	d *schema.ResourceData,
+)
+
+// readObjects reads the objects selected by opts through their resources'
+// importers and passes each to fn, in order of resource type. Objects that no
+// longer exist are skipped.
+func readObjects(
+	ctx context.Context,
+	p *schema.Provider,
+	opts Options,
+	fn readFunc,
+) error {
+	config, ok := p.Meta().(internal.ProviderConfig)
+	if !ok {
+		return fmt.Errorf("provider is not configured")
+	}
+
+	objects, err := findObjects(config, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range sortedKeys(objects) {
+		r, ok := p.ResourcesMap[name]
+		if !ok {
+			return fmt.Errorf("unknown resource type %s", name)
+		}
+
+		if r.Importer == nil {
+			return fmt.Errorf("%s does not support import", name)
+		}
+
+		for _, obj := range objects[name] {
+			d := r.Data(nil)
+			d.SetId(obj.importID)
+
+			imported, err := r.Importer.StateContext(ctx, d, config)
+			if err != nil {
+				return fmt.Errorf("reading %s %s: %w", name, obj.importID, err)
+			}
+
+			for _, d := range imported {
+				// Importers set the ID again after reading the object, so it
+				// is read once more to find out whether it still exists.
+				diags := r.ReadContext(ctx, d, config)
+				if err := diagnosticsError(diags); err != nil {
+					return fmt.Errorf(
+						"reading %s %s: %w",
+						name,
+						obj.importID,
+						err)
+				}
+
+				if len(d.Id()) == 0 {
+					tflog.Warn(ctx, "Skipping object that no longer exists",
+						map[string]any{
+							"resource_type": name,
+							"import_id":     obj.importID,
+						})
+					continue
+				}
+
+				fn(name, r, obj, d)
+			}
+		}
+	}
+
+	return nil
+}
+
+// diagnosticsError returns the first error in diags, if any.
+func diagnosticsError(diags diag.Diagnostics) error {
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			return fmt.Errorf("%s\n%s", d.Summary, d.Detail)
+		}
+	}
+
+	return nil
+}
+
+// findObjects lists the objects selected by opts, keyed by resource type.
+func findObjects(
+	config internal.ProviderConfig,
+	opts Options,
+) (map[string][]object, error) {
+	objects := make(map[string][]object)
+
+	for _, name := range opts.listed() {
+		list, ok := listers[name]
+		if !ok {
+			return nil, fmt.Errorf(
+				"%s cannot be listed, export its objects by import ID",
+				name)
+		}
+
+		found, err := list(config)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s: %w", name, err)
+		}
+
+		objects[name] = append(objects[name], found...)
+	}
+
+	for name, ids := range opts.IDs {
+		for _, id := range ids {
+			objects[name] = append(objects[name], object{importID: id})
+		}
+	}
+
+	return objects, nil
+}
+
+// uniqueLabel returns a resource label derived from an object's name that is
+// not yet used by another object of the same type.
+func uniqueLabel(labels map[string]int, resourceName, name string) string {
+	label := toIdentifier(name)
+	if len(label) == 0 {
+		label = strings.TrimPrefix(resourceName, "edgecast_")
+	}
+
+	key := resourceName + "." + label
+	labels[key]++
+
+	if n := labels[key]; n > 1 {
+		return fmt.Sprintf("%s_%d", label, n)
+	}
+
+	return label
+}
+
+// toIdentifier converts a name to a valid HCL identifier, e.g.
+// "My Rule #1" -> "my_rule_1".
+func toIdentifier(name string) string {
+	var b strings.Builder
+
+	underscore := false
+	for _, c := range strings.ToLower(name) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			b.WriteRune(c)
+			underscore = false
+		} else if !underscore && b.Len() > 0 {
+			b.WriteRune('_')
+			underscore = true
+		}
+	}
+
+	id := strings.TrimSuffix(b.String(), "_")
+	if len(id) > 0 && id[0] >= '0' && id[0] <= '9' {
+		id = "_" + id
+	}
+
+	return id
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
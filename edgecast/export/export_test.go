@@ -0,0 +1,346 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package export
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast"
+	"terraform-provider-edgecast/test/fakeapi"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccount = "A1B2"
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	ctx := context.Background()
+	p := newTestProvider(t, s)
+
+	id := create(t, p, "edgecast_waf_access_rule", map[string]any{
+		"account_number":                testAccount,
+		"name":                          "Access Rule #1",
+		"response_header_name":          "x-rule",
+		"allowed_http_methods":          []any{"GET", "POST"},
+		"allowed_request_content_types": []any{"application/json"},
+		"disallowed_extensions":         []any{".bat"},
+		"disallowed_headers":            []any{"x-reserved"},
+		"ip": []any{
+			map[string]any{"blacklist": []any{"10.10.10.114"}},
+		},
+	})
+
+	var out bytes.Buffer
+	err := Export(ctx, p, &out, Options{
+		Resources: []string{"edgecast_waf_access_rule"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		"import {\n  to = edgecast_waf_access_rule.access_rule_1\n" +
+			"  id = \"" + testAccount + ":" + id + "\"\n}",
+		`resource "edgecast_waf_access_rule" "access_rule_1" {`,
+		`name                          = "Access Rule #1"`,
+		`allowed_http_methods          = ["GET", "POST"]`,
+		"\n\n  ip {\n    blacklist = [\"10.10.10.114\"]\n  }",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", want, got)
+		}
+	}
+}
+
+func TestExport_ByName(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	p := newTestProvider(t, s)
+	create(t, p, "edgecast_waf_access_rule", map[string]any{
+		"account_number":       testAccount,
+		"name":                 "Access Rule #1",
+		"response_header_name": "x-rule",
+	})
+
+	importID := "account_number=" + testAccount + ":name=Access Rule #1"
+
+	var out bytes.Buffer
+	err := Export(context.Background(), p, &out, Options{
+		IDs: map[string][]string{"edgecast_waf_access_rule": {importID}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := `response_header_name = "x-rule"`
+	if got := out.String(); !strings.Contains(got, want) {
+		t.Errorf("expected output to contain:\n%s\ngot:\n%s", want, got)
+	}
+
+	err = Export(context.Background(), p, &out, Options{
+		IDs: map[string][]string{
+			"edgecast_waf_access_rule": {testAccount + ":1:2"},
+		},
+	})
+	if err == nil ||
+		!strings.Contains(err.Error(), "Expected ACCOUNT_NUMBER:ID") {
+		t.Fatalf("expected an invalid import ID error, got %v", err)
+	}
+}
+
+func TestExport_Sensitive(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	p := newTestProvider(t, s)
+	const secret = "HFNASHDJJKQWHKJ1234"
+	id := create(t, p, "edgecast_dns_tsig", map[string]any{
+		"account_number": testAccount,
+		"alias":          "TSIG #1",
+		"key_name":       "key1",
+		"key_value":      secret,
+		"algorithm_name": "HMAC-SHA256",
+	})
+
+	var out bytes.Buffer
+	err := Export(context.Background(), p, &out, Options{
+		IDs: map[string][]string{
+			"edgecast_dns_tsig": {testAccount + ":" + id},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	if strings.Contains(got, secret) {
+		t.Errorf("expected output not to contain the key value, got:\n%s", got)
+	}
+
+	for _, want := range []string{
+		"key_value      = var.edgecast_dns_tsig_dns_tsig_key_value",
+		"variable \"edgecast_dns_tsig_dns_tsig_key_value\" {\n" +
+			"  type      = string\n  sensitive = true\n}",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", want, got)
+		}
+	}
+}
+
+func TestExport_Deleted(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	p := newTestProvider(t, s)
+
+	ids := make([]string, 0, 2)
+	for _, keyName := range []string{"kept", "deleted"} {
+		id := create(t, p, "edgecast_dns_tsig", map[string]any{
+			"account_number": testAccount,
+			"alias":          keyName,
+			"key_name":       keyName,
+			"key_value":      "secret",
+			"algorithm_name": "HMAC-SHA256",
+		})
+		ids = append(ids, testAccount+":"+id)
+	}
+
+	remove(t, p, "edgecast_dns_tsig", ids[1])
+
+	var out bytes.Buffer
+	err := Export(context.Background(), p, &out, Options{
+		IDs: map[string][]string{"edgecast_dns_tsig": ids},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	if !strings.Contains(got, `id = "`+ids[0]+`"`) {
+		t.Errorf("expected output to import %s, got:\n%s", ids[0], got)
+	}
+
+	if strings.Contains(got, `id = "`+ids[1]+`"`) {
+		t.Errorf("expected deleted %s to be skipped, got:\n%s", ids[1], got)
+	}
+}
+
+func TestExport_NotListable(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	p := newTestProvider(t, s)
+
+	var out bytes.Buffer
+	err := Export(context.Background(), p, &out, Options{
+		Resources: []string{"edgecast_dns_zone"},
+	})
+	if err == nil || !strings.Contains(err.Error(), "cannot be listed") {
+		t.Fatalf("expected a cannot be listed error, got %v", err)
+	}
+}
+
+func TestExport_RulesEnginePolicy(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	p := newTestProvider(t, s)
+	id := create(t, p, "edgecast_rules_engine_policy", map[string]any{
+		"account_number": testAccount,
+		"deploy_to":      "staging",
+		"policy": `{"platform": "http_large", "rules": [{"name": "r", ` +
+			`"matches": [{"type": "match.always", "features": ` +
+			`[{"type": "feature.comment", "value": "c"}]}]}]}`,
+	})
+
+	var out bytes.Buffer
+	err := Export(context.Background(), p, &out, Options{
+		Resources: []string{"edgecast_rules_engine_policy"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := out.String()
+	for _, want := range []string{
+		`id = "account_number=` + testAccount + `:id=` + id +
+			`:deploy_to=staging"`,
+		`deploy_to      = "staging"`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected output to contain:\n%s\ngot:\n%s", want, got)
+		}
+	}
+}
+
+func TestUncoveredResources(t *testing.T) {
+	t.Parallel()
+
+	p := edgecast.Provider()
+
+	uncovered := UncoveredResources(p, Options{})
+	for _, name := range uncovered {
+		if _, ok := listers[name]; ok {
+			t.Errorf("expected listed %s to be covered", name)
+		}
+	}
+
+	if !slices.Contains(uncovered, "edgecast_dns_zone") {
+		t.Errorf("expected edgecast_dns_zone to be uncovered, got %v", uncovered)
+	}
+
+	uncovered = UncoveredResources(p, Options{
+		Resources: []string{"edgecast_origin"},
+		IDs:       map[string][]string{"edgecast_dns_zone": {"A1B2:1"}},
+	})
+	if slices.Contains(uncovered, "edgecast_origin") ||
+		slices.Contains(uncovered, "edgecast_dns_zone") ||
+		!slices.Contains(uncovered, "edgecast_waf_scopes") {
+		t.Errorf("unexpected uncovered resource types %v", uncovered)
+	}
+}
+
+func TestUniqueLabel(t *testing.T) {
+	t.Parallel()
+
+	labels := make(map[string]int)
+	cases := []struct {
+		resource string
+		name     string
+		want     string
+	}{
+		{"edgecast_origin", "My Origin", "my_origin"},
+		{"edgecast_origin", "my-origin", "my_origin_2"},
+		{"edgecast_edgecname", "my origin", "my_origin"},
+		{"edgecast_waf_scopes", "", "waf_scopes"},
+		{"edgecast_origin", "1st Origin!", "_1st_origin"},
+		{"edgecast_origin", "--", "origin"},
+	}
+
+	for _, c := range cases {
+		got := uniqueLabel(labels, c.resource, c.name)
+		if got != c.want {
+			t.Errorf(
+				"uniqueLabel(%q, %q) = %q, expected %q",
+				c.resource,
+				c.name,
+				got,
+				c.want)
+		}
+	}
+}
+
+// newTestProvider returns a provider configured to use s.
+func newTestProvider(t *testing.T, s *fakeapi.Server) *schema.Provider {
+	settings := s.Settings()
+	settings["account_number"] = testAccount
+
+	p := edgecast.Provider()
+	diags := p.Configure(
+		context.Background(),
+		terraform.NewResourceConfigRaw(settings))
+	if diags.HasError() {
+		t.Fatalf("unexpected error configuring provider: %v", diags)
+	}
+
+	return p
+}
+
+// create creates an object through its resource and returns its ID.
+func create(
+	t *testing.T,
+	p *schema.Provider,
+	name string,
+	raw map[string]any,
+) string {
+	r := p.ResourcesMap[name]
+	d := schema.TestResourceDataRaw(t, r.Schema, raw)
+
+	diags := r.CreateContext(context.Background(), d, p.Meta())
+	if diags.HasError() {
+		t.Fatalf("unexpected error creating %s: %v", name, diags)
+	}
+
+	return d.Id()
+}
+
+// remove deletes the object with the given import ID through its resource.
+func remove(t *testing.T, p *schema.Provider, name string, importID string) {
+	r := p.ResourcesMap[name]
+	d := r.Data(nil)
+	d.SetId(importID)
+
+	imported, err := r.Importer.StateContext(context.Background(), d, p.Meta())
+	if err != nil {
+		t.Fatalf("unexpected error importing %s: %v", name, err)
+	}
+
+	diags := r.DeleteContext(context.Background(), imported[0], p.Meta())
+	if diags.HasError() {
+		t.Fatalf("unexpected error deleting %s: %v", name, diags)
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package export
+
+import (
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// file is a configuration file being generated.
+type file struct {
+	f *hclwrite.File
+
+	// variables holds the variables that sensitive values are read from.
+	variables []variable
+}
+
+// variable is a variable that a sensitive value is read from, since
+// sensitive values are not written to the configuration.
+type variable struct {
+	name    string
+	varType string
+}
+
+func newFile() *file {
+	f := hclwrite.NewEmptyFile()
+	f.Body().AppendUnstructuredTokens(hclwrite.Tokens{{
+		Type: hclsyntax.TokenComment,
+		Bytes: []byte(
+			"# Generated by terraform-provider-edgecast export. Review it\n" +
+				"# before running terraform plan.\n"),
+	}})
+
+	return &file{f: f}
+}
+
+// addImport adds an import block for an object.
+func (f *file) addImport(resourceName, label, importID string) {
+	body := f.f.Body()
+	body.AppendNewline()
+
+	block := body.AppendNewBlock("import", nil).Body()
+	block.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceName},
+		hcl.TraverseAttr{Name: label},
+	})
+	block.SetAttributeValue("id", cty.StringVal(importID))
+}
+
+// addResource adds a resource block with the arguments of the object read
+// into d.
+func (f *file) addResource(
+	r *schema.Resource,
+	d *schema.ResourceData,
+	resourceName string,
+	label string,
+) {
+	values := make(map[string]any, len(r.Schema))
+	for key := range r.Schema {
+		values[key] = d.Get(key)
+	}
+
+	body := f.f.Body()
+	body.AppendNewline()
+
+	block := body.AppendNewBlock("resource", []string{resourceName, label})
+	f.writeArguments(block.Body(), r.Schema, values, resourceName+"_"+label)
+}
+
+// writeArguments writes the values of the arguments in s to body. Computed
+// attributes, deprecated arguments and arguments left at their defaults are
+// omitted, and nested blocks follow the attributes. Sensitive values are read
+// from variables named after path.
+func (f *file) writeArguments(
+	body *hclwrite.Body,
+	s map[string]*schema.Schema,
+	values map[string]any,
+	path string,
+) {
+	keys := make([]string, 0, len(s))
+	for key := range s {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	// Nested blocks are written after the attributes.
+	var blocks []string
+
+	for _, key := range keys {
+		attr := s[key]
+		if !attr.Optional && !attr.Required {
+			continue
+		}
+
+		if len(attr.Deprecated) > 0 {
+			continue
+		}
+
+		value := values[key]
+		if isZero(value) && !attr.Required && isZero(attr.Default) {
+			continue
+		}
+
+		if _, ok := attr.Elem.(*schema.Resource); ok &&
+			attr.ConfigMode != schema.SchemaConfigModeAttr {
+			blocks = append(blocks, key)
+			continue
+		}
+
+		if attr.Sensitive {
+			name := path + "_" + key
+			f.variables = append(f.variables, variable{
+				name:    name,
+				varType: variableType(attr),
+			})
+
+			body.SetAttributeTraversal(key, hcl.Traversal{
+				hcl.TraverseRoot{Name: "var"},
+				hcl.TraverseAttr{Name: name},
+			})
+
+			continue
+		}
+
+		body.SetAttributeValue(key, toValue(value))
+	}
+
+	for _, key := range blocks {
+		elem := s[key].Elem.(*schema.Resource)
+		for _, item := range listItems(values[key]) {
+			if len(body.Attributes()) > 0 || len(body.Blocks()) > 0 {
+				body.AppendNewline()
+			}
+
+			child := body.AppendNewBlock(key, nil).Body()
+			m, _ := item.(map[string]any)
+			f.writeArguments(child, elem.Schema, m, path+"_"+key)
+		}
+	}
+}
+
+// bytes returns the formatted configuration, with the variables for sensitive
+// values declared at the end.
+func (f *file) bytes() []byte {
+	body := f.f.Body()
+
+	for _, v := range f.variables {
+		body.AppendNewline()
+
+		block := body.AppendNewBlock("variable", []string{v.name}).Body()
+		block.SetAttributeTraversal("type", hcl.Traversal{
+			hcl.TraverseRoot{Name: v.varType},
+		})
+		block.SetAttributeValue("sensitive", cty.True)
+	}
+
+	return hclwrite.Format(f.f.Bytes())
+}
+
+// toValue converts a value read from resource data to a cty value. Lists and
+// sets become tuples, and nested objects become objects, so that elements of
+// mixed types can be written.
+func toValue(v any) cty.Value {
+	switch v := v.(type) {
+	case string:
+		return cty.StringVal(v)
+	case int:
+		return cty.NumberIntVal(int64(v))
+	case float64:
+		return cty.NumberFloatVal(v)
+	case bool:
+		return cty.BoolVal(v)
+	case *schema.Set, []any:
+		items := listItems(v)
+		if len(items) == 0 {
+			return cty.EmptyTupleVal
+		}
+
+		values := make([]cty.Value, 0, len(items))
+		for _, item := range items {
+			values = append(values, toValue(item))
+		}
+
+		return cty.TupleVal(values)
+	case map[string]any:
+		if len(v) == 0 {
+			return cty.EmptyObjectVal
+		}
+
+		values := make(map[string]cty.Value, len(v))
+		for key, item := range v {
+			values[key] = toValue(item)
+		}
+
+		return cty.ObjectVal(values)
+	default:
+		return cty.NullVal(cty.DynamicPseudoType)
+	}
+}
+
+// listItems returns the elements of a list or set value.
+func listItems(v any) []any {
+	switch v := v.(type) {
+	case *schema.Set:
+		return v.List()
+	case []any:
+		return v
+	default:
+		return nil
+	}
+}
+
+// isZero reports whether v is nil, the zero value of its type, or an empty
+// collection.
+func isZero(v any) bool {
+	switch v := v.(type) {
+	case nil:
+		return true
+	case string:
+		return len(v) == 0
+	case int:
+		return v == 0
+	case float64:
+		return v == 0
+	case bool:
+		return !v
+	case *schema.Set:
+		return v.Len() == 0
+	case []any:
+		return len(v) == 0
+	case map[string]any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
+
+// variableType returns the Terraform type of a variable holding a value of
+// the given schema.
+func variableType(s *schema.Schema) string {
+	switch s.Type {
+	case schema.TypeString:
+		return "string"
+	case schema.TypeInt, schema.TypeFloat:
+		return "number"
+	case schema.TypeBool:
+		return "bool"
+	default:
+		return "any"
+	}
+}
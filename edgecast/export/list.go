@@ -0,0 +1,422 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package export
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"terraform-provider-edgecast/edgecast/internal"
+	"terraform-provider-edgecast/edgecast/resources/rulesengine"
+
+	sdkcps "github.com/EdgeCast/ec-sdk-go/edgecast/cps"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/cps/certificate"
+	sdkedgecname "github.com/EdgeCast/ec-sdk-go/edgecast/edgecname"
+	sdkorigin "github.com/EdgeCast/ec-sdk-go/edgecast/origin"
+	sdkoriginv3 "github.com/EdgeCast/ec-sdk-go/edgecast/originv3"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/routedns"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/shared/enums"
+	sdkwaf "github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/access"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/bot"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/custom"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/managed"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/rate"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/scopes"
+	sdkbotmanager "github.com/EdgeCast/ec-sdk-go/edgecast/waf_bot_manager"
+)
+
+// object is an object found in an account.
+type object struct {
+	// importID identifies the object in the format that the resource's
+	// importer expects, e.g. account_number:id.
+	importID string
+
+	// name is used to label the object's resource block.
+	name string
+}
+
+// lister lists the objects of one resource type in an account.
+type lister func(config internal.ProviderConfig) ([]object, error)
+
+// listers holds a lister for each resource type whose objects the APIs can
+// list. Other resource types can only be exported by import ID.
+var listers = map[string]lister{
+	"edgecast_waf_access_rule":       listAccessRules,
+	"edgecast_waf_rate_rule":         listRateRules,
+	"edgecast_waf_managed_rule":      listManagedRules,
+	"edgecast_waf_custom_rule_set":   listCustomRuleSets,
+	"edgecast_waf_bot_rule_set":      listBotRuleSets,
+	"edgecast_waf_scopes":            listScopes,
+	"edgecast_waf_botmanager":        listBotManagers,
+	"edgecast_edgecname":             listEdgeCnames,
+	"edgecast_origin":                listOrigins,
+	"edgecast_originv3_httplarge":    listOriginGroups,
+	"edgecast_cps_certificate":       listCertificates,
+	"edgecast_dns_masterservergroup": listMasterServerGroups,
+	"edgecast_rules_engine_policy":   listRulesEnginePolicies,
+}
+
+// errNoAccount is returned when listing objects of an account without an
+// account number.
+var errNoAccount = errors.New("account_number is not set")
+
+// listedPlatforms are the delivery platforms whose edge CNAMEs and origins
+// are listed.
+var listedPlatforms = []enums.Platform{
+	enums.HttpLarge,
+	enums.HttpSmall,
+	enums.ADN,
+}
+
+// accountObject returns an object identified by account number and ID.
+func accountObject(account string, id string, name string) object {
+	return object{importID: account + ":" + id, name: name}
+}
+
+func buildWAFService(
+	config internal.ProviderConfig,
+) (*sdkwaf.WafService, error) {
+	if len(config.AccountNumber) == 0 {
+		return nil, errNoAccount
+	}
+
+	return internal.GetService(config, "waf", sdkwaf.New)
+}
+
+func listAccessRules(config internal.ProviderConfig) ([]object, error) {
+	svc, err := buildWAFService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.Access.GetAllAccessRules(
+		access.GetAllAccessRulesParams{AccountNumber: config.AccountNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(*resp))
+	for _, rule := range *resp {
+		objects = append(objects,
+			accountObject(config.AccountNumber, rule.ID, rule.Name))
+	}
+
+	return objects, nil
+}
+
+func listRateRules(config internal.ProviderConfig) ([]object, error) {
+	svc, err := buildWAFService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.Rate.GetAllRateRules(
+		rate.GetAllRateRulesParams{AccountNumber: config.AccountNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(*resp))
+	for _, rule := range *resp {
+		objects = append(objects,
+			accountObject(config.AccountNumber, rule.ID, rule.Name))
+	}
+
+	return objects, nil
+}
+
+func listManagedRules(config internal.ProviderConfig) ([]object, error) {
+	svc, err := buildWAFService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.Managed.GetAllManagedRules(
+		managed.GetAllManagedRulesParams{AccountNumber: config.AccountNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(*resp))
+	for _, rule := range *resp {
+		objects = append(objects,
+			accountObject(config.AccountNumber, rule.ID, rule.Name))
+	}
+
+	return objects, nil
+}
+
+func listCustomRuleSets(config internal.ProviderConfig) ([]object, error) {
+	svc, err := buildWAFService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.Custom.GetAllCustomRuleSets(
+		custom.GetAllCustomRuleSetsParams{AccountNumber: config.AccountNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(*resp))
+	for _, rule := range *resp {
+		objects = append(objects,
+			accountObject(config.AccountNumber, rule.ID, rule.Name))
+	}
+
+	return objects, nil
+}
+
+func listBotRuleSets(config internal.ProviderConfig) ([]object, error) {
+	svc, err := buildWAFService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.Bot.GetAllBotRuleSets(
+		bot.GetAllBotRuleSetsParams{AccountNumber: config.AccountNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(*resp))
+	for _, rule := range *resp {
+		objects = append(objects,
+			accountObject(config.AccountNumber, rule.ID, rule.Name))
+	}
+
+	return objects, nil
+}
+
+// listScopes returns the account's scopes, which are managed as a single
+// object.
+func listScopes(config internal.ProviderConfig) ([]object, error) {
+	svc, err := buildWAFService(config)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := svc.Scopes.GetAllScopes(
+		scopes.GetAllScopesParams{AccountNumber: config.AccountNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Scopes) == 0 {
+		return nil, nil
+	}
+
+	return []object{
+		accountObject(config.AccountNumber, resp.ID, "scopes"),
+	}, nil
+}
+
+func listBotManagers(config internal.ProviderConfig) ([]object, error) {
+	if len(config.AccountNumber) == 0 {
+		return nil, errNoAccount
+	}
+
+	svc, err := internal.GetService(
+		config,
+		"waf_bot_manager",
+		sdkbotmanager.New)
+	if err != nil {
+		return nil, err
+	}
+
+	params := sdkbotmanager.NewGetBotManagersParams()
+	params.CustId = config.AccountNumber
+
+	resp, err := svc.BotManagers.GetBotManagers(params)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(resp))
+	for _, bm := range resp {
+		objects = append(objects,
+			accountObject(config.AccountNumber, bm.Id, bm.Name))
+	}
+
+	return objects, nil
+}
+
+func listEdgeCnames(config internal.ProviderConfig) ([]object, error) {
+	if len(config.AccountNumber) == 0 {
+		return nil, errNoAccount
+	}
+
+	svc, err := internal.GetService(config, "edgecname", sdkedgecname.New)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []object
+	for _, platform := range listedPlatforms {
+		params := sdkedgecname.NewGetAllEdgeCnameParams()
+		params.AccountNumber = config.AccountNumber
+		params.Platform = platform
+
+		resp, err := svc.GetAllEdgeCnames(*params)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s edge CNAMEs: %w", platform, err)
+		}
+
+		for _, cname := range *resp {
+			objects = append(objects, accountObject(
+				config.AccountNumber,
+				strconv.Itoa(cname.ID),
+				cname.Name))
+		}
+	}
+
+	return objects, nil
+}
+
+func listOrigins(config internal.ProviderConfig) ([]object, error) {
+	if len(config.AccountNumber) == 0 {
+		return nil, errNoAccount
+	}
+
+	svc, err := internal.GetService(config, "origin", sdkorigin.New)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []object
+	for _, platform := range listedPlatforms {
+		params := sdkorigin.NewGetAllOriginsParams()
+		params.AccountNumber = config.AccountNumber
+		params.MediaTypeID = platform
+
+		resp, err := svc.GetAllOrigins(*params)
+		if err != nil {
+			return nil, fmt.Errorf("listing %s origins: %w", platform, err)
+		}
+
+		// Origins are imported as account_number:id:media_type_id.
+		for _, o := range *resp {
+			objects = append(objects, object{
+				importID: fmt.Sprintf(
+					"%s:%d:%d",
+					config.AccountNumber,
+					o.ID,
+					int(platform)),
+				name: o.DirectoryName,
+			})
+		}
+	}
+
+	return objects, nil
+}
+
+func listOriginGroups(config internal.ProviderConfig) ([]object, error) {
+	svc, err := internal.GetService(config, "originv3", sdkoriginv3.New)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := svc.HttpLargeOnly.GetAllHttpLargeGroups()
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(groups))
+	for _, g := range groups {
+		objects = append(objects, object{
+			importID: strconv.Itoa(int(g.GetId())),
+			name:     g.GetName(),
+		})
+	}
+
+	return objects, nil
+}
+
+func listCertificates(config internal.ProviderConfig) ([]object, error) {
+	svc, err := internal.GetService(config, "cps", sdkcps.New)
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []object
+	for page := int32(1); ; page++ {
+		params := certificate.NewCertificateFindParams()
+		params.Page = &page
+
+		resp, err := svc.Certificate.CertificateFind(params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cert := range resp.Items {
+			objects = append(objects, object{
+				importID: strconv.FormatInt(cert.ID, 10),
+				name:     cert.CertificateLabel,
+			})
+		}
+
+		if len(resp.Items) == 0 || len(objects) >= int(resp.TotalItems) {
+			return objects, nil
+		}
+	}
+}
+
+func listMasterServerGroups(config internal.ProviderConfig) ([]object, error) {
+	if len(config.AccountNumber) == 0 {
+		return nil, errNoAccount
+	}
+
+	svc, err := internal.GetService(config, "routedns", routedns.New)
+	if err != nil {
+		return nil, err
+	}
+
+	params := routedns.NewGetAllMasterServerGroupsParams()
+	params.AccountNumber = config.AccountNumber
+
+	resp, err := svc.GetAllMasterServerGroups(*params)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(*resp))
+	for _, g := range *resp {
+		objects = append(objects, accountObject(
+			config.AccountNumber,
+			strconv.Itoa(g.MasterGroupID),
+			g.Name))
+	}
+
+	return objects, nil
+}
+
+// listRulesEnginePolicies lists the policies in effect, one object per
+// environment and platform. Their import IDs name deploy_to, which the API
+// does not return with the policy.
+func listRulesEnginePolicies(config internal.ProviderConfig) ([]object, error) {
+	if len(config.AccountNumber) == 0 {
+		return nil, errNoAccount
+	}
+
+	policies, err := rulesengine.DeployedPolicies(config, config.AccountNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]object, 0, len(policies))
+	for _, p := range policies {
+		objects = append(objects, object{
+			importID: fmt.Sprintf(
+				"account_number=%s:id=%s:deploy_to=%s",
+				config.AccountNumber,
+				p.ID,
+				p.Environment),
+			name: p.Name,
+		})
+	}
+
+	return objects, nil
+}
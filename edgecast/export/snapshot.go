@@ -0,0 +1,316 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package export
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Snapshot holds the normalized configuration of the objects in an account,
+// keyed by resource type and import ID.
+type Snapshot map[string]map[string]Entry
+
+// Entry is the configuration of one object in a snapshot.
+type Entry struct {
+	ImportID   string         `json:"import_id"`
+	Name       string         `json:"name,omitempty"`
+	Attributes map[string]any `json:"attributes"`
+}
+
+// TakeSnapshot reads the objects selected by opts. The provider must be
+// configured. Objects that no longer exist when they are read are skipped.
+func TakeSnapshot(
+	ctx context.Context,
+	p *schema.Provider,
+	opts Options,
+) (Snapshot, error) {
+	s := make(Snapshot)
+
+	var errs []string
+	add := func(
+		name string,
+		r *schema.Resource,
+		obj object,
+		d *schema.ResourceData,
+	) {
+		entry, err := newEntry(r, obj, d)
+		if err != nil {
+			errs = append(errs,
+				fmt.Sprintf("%s %s: %v", name, obj.importID, err))
+			return
+		}
+
+		if s[name] == nil {
+			s[name] = make(map[string]Entry)
+		}
+
+		s[name][obj.importID] = entry
+	}
+
+	if err := readObjects(ctx, p, opts, add); err != nil {
+		return nil, err
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.New(strings.Join(errs, "; "))
+	}
+
+	return s, nil
+}
+
+// newEntry returns the normalized configuration of an object read into d.
+// Sensitive values are redacted, both those of attributes marked sensitive,
+// which Export reads from variables, and those of fields that are never
+// logged, so that snapshots can be committed. Redacted values are never
+// reported as changed.
+func newEntry(
+	r *schema.Resource,
+	obj object,
+	d *schema.ResourceData,
+) (Entry, error) {
+	values := make(map[string]any, len(r.Schema))
+	for key := range r.Schema {
+		values[key] = d.Get(key)
+	}
+
+	// Round trip through JSON so that values compare equal to those read from
+	// a snapshot file, e.g. numbers become float64.
+	b, err := json.Marshal(normalizeArguments(r.Schema, values))
+	if err != nil {
+		return Entry{}, err
+	}
+
+	var attributes map[string]any
+	if err := json.Unmarshal(b, &attributes); err != nil {
+		return Entry{}, err
+	}
+
+	attributes, _ = internal.Redact(attributes).(map[string]any)
+
+	name := obj.name
+	if n, ok := attributes["name"].(string); ok && len(name) == 0 {
+		name = n
+	}
+
+	return Entry{
+		ImportID:   obj.importID,
+		Name:       name,
+		Attributes: attributes,
+	}, nil
+}
+
+// normalizeArguments returns the values of the arguments in s. Computed
+// attributes, such as modification dates, are left out since the API changes
+// them without the configuration changing. Values are passed through their
+// attribute's StateFunc, e.g. the one that removes metadata from Rules Engine
+// policies, and sensitive values are redacted.
+func normalizeArguments(
+	s map[string]*schema.Schema,
+	values map[string]any,
+) map[string]any {
+	arguments := make(map[string]any, len(values))
+
+	for key, attr := range s {
+		if !attr.Optional && !attr.Required {
+			continue
+		}
+
+		value, ok := values[key]
+		if !ok {
+			continue
+		}
+
+		if attr.Sensitive && !isZero(value) {
+			arguments[key] = internal.RedactedValue
+			continue
+		}
+
+		if elem, ok := attr.Elem.(*schema.Resource); ok {
+			items := make([]any, 0)
+			for _, item := range listItems(value) {
+				m, _ := item.(map[string]any)
+				items = append(items, normalizeArguments(elem.Schema, m))
+			}
+
+			arguments[key] = items
+			continue
+		}
+
+		if str, ok := value.(string); ok && attr.StateFunc != nil &&
+			len(str) > 0 {
+			value = attr.StateFunc(str)
+		}
+
+		arguments[key] = normalize(value)
+	}
+
+	return arguments
+}
+
+// normalize converts sets to lists, and decodes strings holding JSON objects,
+// such as Rules Engine policies, so that they are compared by content.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case *schema.Set:
+		return normalize(v.List())
+	case []any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, normalize(item))
+		}
+
+		return items
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for key, item := range v {
+			m[key] = normalize(item)
+		}
+
+		return m
+	case string:
+		if !strings.HasPrefix(strings.TrimSpace(v), "{") {
+			return v
+		}
+
+		var obj map[string]any
+		if err := json.Unmarshal([]byte(v), &obj); err != nil {
+			return v
+		}
+
+		return normalize(obj)
+	default:
+		return v
+	}
+}
+
+// WriteSnapshot writes s to dir, with one file per object in a directory per
+// resource type. dir must not exist or be empty, so that files of objects
+// that were deleted since an earlier snapshot are not left behind.
+func WriteSnapshot(dir string, s Snapshot) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	if len(entries) > 0 {
+		return fmt.Errorf("snapshot directory %s is not empty", dir)
+	}
+
+	for _, name := range sortedKeys(s) {
+		typeDir := filepath.Join(dir, name)
+		if err := os.MkdirAll(typeDir, 0o755); err != nil {
+			return err
+		}
+
+		files := make(map[string]string)
+		for _, importID := range sortedKeys(s[name]) {
+			file := toFileName(importID) + ".json"
+			if other, ok := files[file]; ok {
+				return fmt.Errorf(
+					"%s objects %s and %s have the same file name %s",
+					name,
+					other,
+					importID,
+					file)
+			}
+
+			files[file] = importID
+
+			b, err := json.MarshalIndent(s[name][importID], "", "  ")
+			if err != nil {
+				return err
+			}
+
+			b = append(b, '\n')
+			err = os.WriteFile(filepath.Join(typeDir, file), b, 0o644)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ReadSnapshot reads a snapshot written by WriteSnapshot.
+func ReadSnapshot(dir string) (Snapshot, error) {
+	types, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	s := make(Snapshot)
+	for _, t := range types {
+		if !t.IsDir() {
+			continue
+		}
+
+		name := t.Name()
+		files, err := filepath.Glob(filepath.Join(dir, name, "*.json"))
+		if err != nil {
+			return nil, err
+		}
+
+		s[name] = make(map[string]Entry, len(files))
+		for _, file := range files {
+			b, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+
+			var entry Entry
+			if err := json.Unmarshal(b, &entry); err != nil {
+				return nil, fmt.Errorf("reading %s: %w", file, err)
+			}
+
+			s[name][entry.ImportID] = entry
+		}
+	}
+
+	return s, nil
+}
+
+// LiveOptions returns options that read the objects of the resource types in
+// s from the account. Types that cannot be listed are read by the import IDs
+// in s, so objects created since the snapshot are not found for them.
+func LiveOptions(s Snapshot) Options {
+	opts := Options{IDs: make(map[string][]string)}
+
+	for _, name := range sortedKeys(s) {
+		if _, ok := listers[name]; ok {
+			opts.Resources = append(opts.Resources, name)
+			continue
+		}
+
+		opts.IDs[name] = sortedKeys(s[name])
+	}
+
+	return opts
+}
+
+// toFileName converts an import ID to a file name, e.g. "A1B2:1234" ->
+// "A1B2_1234".
+func toFileName(importID string) string {
+	return strings.Map(func(c rune) rune {
+		if (c >= 'a' && c <= 'z') ||
+			(c >= 'A' && c <= 'Z') ||
+			(c >= '0' && c <= '9') ||
+			c == '-' ||
+			c == '.' {
+			return c
+		}
+
+		return '_'
+	}, importID)
+}
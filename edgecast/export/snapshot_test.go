@@ -0,0 +1,268 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package export
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal"
+	"terraform-provider-edgecast/test/fakeapi"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	ctx := context.Background()
+	p := newTestProvider(t, s)
+
+	rule := func(name string) map[string]any {
+		return map[string]any{
+			"account_number":                testAccount,
+			"name":                          name,
+			"response_header_name":          "x-rule",
+			"allowed_http_methods":          []any{"GET"},
+			"allowed_request_content_types": []any{"application/json"},
+			"disallowed_extensions":         []any{".bat"},
+			"disallowed_headers":            []any{"x-reserved"},
+		}
+	}
+
+	id := create(t, p, "edgecast_waf_access_rule", rule("Rule 1"))
+	importID := testAccount + ":" + id
+
+	opts := Options{Resources: []string{"edgecast_waf_access_rule"}}
+	snapshot, err := TakeSnapshot(ctx, p, opts)
+	if err != nil {
+		t.Fatalf("unexpected error taking snapshot: %v", err)
+	}
+
+	entry := snapshot["edgecast_waf_access_rule"][importID]
+	if entry.Name != "Rule 1" ||
+		entry.Attributes["response_header_name"] != "x-rule" {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+
+	dir := filepath.Join(t.TempDir(), "snapshot")
+	if err := WriteSnapshot(dir, snapshot); err != nil {
+		t.Fatalf("unexpected error writing snapshot: %v", err)
+	}
+
+	file := filepath.Join(
+		dir,
+		"edgecast_waf_access_rule",
+		toFileName(importID)+".json")
+	if _, err := os.Stat(file); err != nil {
+		t.Fatalf("expected snapshot file: %v", err)
+	}
+
+	if err := WriteSnapshot(dir, snapshot); err == nil {
+		t.Fatal("expected an error writing to a non-empty directory")
+	}
+
+	read, err := ReadSnapshot(dir)
+	if err != nil {
+		t.Fatalf("unexpected error reading snapshot: %v", err)
+	}
+
+	if !reflect.DeepEqual(read, snapshot) {
+		t.Fatalf("expected %+v, got %+v", snapshot, read)
+	}
+
+	added := create(t, p, "edgecast_waf_access_rule", rule("Rule 2"))
+
+	live, err := TakeSnapshot(ctx, p, LiveOptions(read))
+	if err != nil {
+		t.Fatalf("unexpected error taking snapshot: %v", err)
+	}
+
+	expected := []Change{{
+		ResourceType: "edgecast_waf_access_rule",
+		ImportID:     testAccount + ":" + added,
+		Name:         "Rule 2",
+		Kind:         Added,
+	}}
+
+	if changes := Diff(read, live); !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, changes)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	t.Parallel()
+
+	entry := func(id string, attributes map[string]any) map[string]Entry {
+		return map[string]Entry{
+			id: {ImportID: id, Name: id, Attributes: attributes},
+		}
+	}
+
+	from := Snapshot{
+		"edgecast_origin": entry("A:1:3", map[string]any{"a": "x"}),
+		"edgecast_waf_access_rule": entry("A:2", map[string]any{
+			"name": "rule",
+			"ip": []any{
+				map[string]any{"blacklist": []any{"10.0.0.1"}},
+			},
+			"allowed_http_methods": []any{"GET"},
+		}),
+	}
+
+	to := Snapshot{
+		"edgecast_edgecname": entry("A:5", map[string]any{}),
+		"edgecast_waf_access_rule": entry("A:2", map[string]any{
+			"name": "rule",
+			"ip": []any{
+				map[string]any{"blacklist": []any{"10.0.0.2"}},
+			},
+			"allowed_http_methods": []any{"GET", "POST"},
+		}),
+	}
+
+	expected := []Change{
+		{
+			ResourceType: "edgecast_edgecname",
+			ImportID:     "A:5",
+			Name:         "A:5",
+			Kind:         Added,
+		},
+		{
+			ResourceType: "edgecast_origin",
+			ImportID:     "A:1:3",
+			Name:         "A:1:3",
+			Kind:         Removed,
+		},
+		{
+			ResourceType: "edgecast_waf_access_rule",
+			ImportID:     "A:2",
+			Name:         "A:2",
+			Kind:         Changed,
+			Attributes:   []string{"allowed_http_methods", "ip.0.blacklist.0"},
+		},
+	}
+
+	if changes := Diff(from, to); !reflect.DeepEqual(changes, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, changes)
+	}
+
+	if changes := Diff(from, from); len(changes) != 0 {
+		t.Fatalf("expected no changes, got %+v", changes)
+	}
+}
+
+func TestNormalizeArguments(t *testing.T) {
+	t.Parallel()
+
+	s := map[string]*schema.Schema{
+		"name": {Type: schema.TypeString, Required: true},
+		"last_modified_date": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"policy": {
+			Type:     schema.TypeString,
+			Optional: true,
+			StateFunc: func(v any) string {
+				return strings.ReplaceAll(v.(string), `"created_at": "x"`, "")
+			},
+		},
+		"description": {Type: schema.TypeString, Optional: true},
+		"scope": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"secret_key": {
+						Type:      schema.TypeString,
+						Optional:  true,
+						Sensitive: true,
+					},
+					"id": {Type: schema.TypeString, Computed: true},
+				},
+			},
+		},
+	}
+
+	got := normalizeArguments(s, map[string]any{
+		"name":               "rule",
+		"last_modified_date": "2023-01-01",
+		"policy":             `{"name": "p", "rules": [{"created_at": "x"}]}`,
+		"description":        "{not json",
+		"scope": []any{
+			map[string]any{"secret_key": "s3cr3t", "id": "1"},
+		},
+	})
+
+	expected := map[string]any{
+		"name": "rule",
+		"policy": map[string]any{
+			"name":  "p",
+			"rules": []any{map[string]any{}},
+		},
+		"description": "{not json",
+		"scope": []any{
+			map[string]any{"secret_key": internal.RedactedValue},
+		},
+	}
+
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, got)
+	}
+}
+
+func TestSnapshot_Sensitive(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	p := newTestProvider(t, s)
+	const secret = "HFNASHDJJKQWHKJ1234"
+	id := create(t, p, "edgecast_dns_tsig", map[string]any{
+		"account_number": testAccount,
+		"alias":          "TSIG #1",
+		"key_name":       "key1",
+		"key_value":      secret,
+		"algorithm_name": "HMAC-SHA256",
+	})
+
+	snapshot, err := TakeSnapshot(context.Background(), p, Options{
+		IDs: map[string][]string{
+			"edgecast_dns_tsig": {testAccount + ":" + id},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error taking snapshot: %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "snapshot")
+	if err := WriteSnapshot(dir, snapshot); err != nil {
+		t.Fatalf("unexpected error writing snapshot: %v", err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(
+		dir,
+		"edgecast_dns_tsig",
+		toFileName(testAccount+":"+id)+".json"))
+	if err != nil {
+		t.Fatalf("unexpected error reading snapshot file: %v", err)
+	}
+
+	if strings.Contains(string(b), secret) {
+		t.Errorf("expected snapshot not to contain the key value, got:\n%s", b)
+	}
+
+	if !strings.Contains(string(b), `"key_name": "key1"`) {
+		t.Errorf("expected snapshot to contain the key name, got:\n%s", b)
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+// Package framework holds the part of the provider that is built on the
+// Terraform Plugin Framework. It is served alongside the SDKv2 provider
+// through a mux server, so that resources can be added to or migrated to the
+// framework one at a time.
+//
+// Both providers share the provider block and the internal.ProviderConfig
+// built from it. The SDKv2 provider remains the source of truth for the
+// provider block: its schema is converted for the framework, and the mux
+// server configures it first so that the framework provider can reuse its
+// configuration.
+package framework
+
+import (
+	"context"
+	"fmt"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	sdkschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// resources holds the constructors of the resources implemented with the
+// framework. A resource must not also be in the SDKv2 provider's
+// ResourcesMap.
+var resources = []func() resource.Resource{}
+
+// dataSources holds the constructors of the data sources implemented with the
+// framework. A data source must not also be in the SDKv2 provider's
+// DataSourcesMap.
+var dataSources = []func() datasource.DataSource{}
+
+// edgecastProvider is the framework provider.
+type edgecastProvider struct {
+	version string
+
+	// sdkProvider is the SDKv2 provider whose provider block and
+	// configuration are shared.
+	sdkProvider *sdkschema.Provider
+}
+
+var _ provider.Provider = &edgecastProvider{}
+
+// New returns a function that creates the framework provider. sdkProvider is
+// the SDKv2 provider it is served with, which must be configured before it.
+func New(
+	version string,
+	sdkProvider *sdkschema.Provider,
+) func() provider.Provider {
+	return func() provider.Provider {
+		return &edgecastProvider{version: version, sdkProvider: sdkProvider}
+	}
+}
+
+func (p *edgecastProvider) Metadata(
+	_ context.Context,
+	_ provider.MetadataRequest,
+	resp *provider.MetadataResponse,
+) {
+	resp.TypeName = "edgecast"
+	resp.Version = p.version
+}
+
+func (p *edgecastProvider) Schema(
+	_ context.Context,
+	_ provider.SchemaRequest,
+	resp *provider.SchemaResponse,
+) {
+	s, err := convertSchema(p.sdkProvider.Schema)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid provider schema", err.Error())
+		return
+	}
+
+	resp.Schema = s
+}
+
+// Configure passes the configuration of the SDKv2 provider to the framework's
+// resources and data sources. The provider block was already read and
+// validated when the SDKv2 provider was configured.
+func (p *edgecastProvider) Configure(
+	_ context.Context,
+	_ provider.ConfigureRequest,
+	resp *provider.ConfigureResponse,
+) {
+	config, ok := p.sdkProvider.Meta().(internal.ProviderConfig)
+	if !ok {
+		resp.Diagnostics.AddError(
+			"Provider not configured",
+			"The provider configuration was not available to the framework "+
+				"provider. The SDKv2 provider must be configured first.")
+		return
+	}
+
+	resp.ResourceData = config
+	resp.DataSourceData = config
+}
+
+func (p *edgecastProvider) Resources(
+	_ context.Context,
+) []func() resource.Resource {
+	return resources
+}
+
+func (p *edgecastProvider) DataSources(
+	_ context.Context,
+) []func() datasource.DataSource {
+	return dataSources
+}
+
+// ProviderConfig returns the provider configuration passed to a framework
+// resource or data source's Configure method. data is nil before the
+// provider is configured, e.g. during validation, in which case ok is false
+// and no diagnostic is added.
+func ProviderConfig(
+	data any,
+	diags *diag.Diagnostics,
+) (config internal.ProviderConfig, ok bool) {
+	if data == nil {
+		return internal.ProviderConfig{}, false
+	}
+
+	config, ok = data.(internal.ProviderConfig)
+	if !ok {
+		diags.AddError(
+			"Unexpected provider data",
+			fmt.Sprintf(
+				"Expected internal.ProviderConfig, got %T. Please report this "+
+					"issue to the provider developers.",
+				data))
+	}
+
+	return config, ok
+}
+
+// convertSchema converts the SDKv2 provider block schema to the framework's.
+// The result must be identical on the wire, since the mux server rejects
+// providers whose provider blocks differ. Validation is left to the SDKv2
+// provider.
+func convertSchema(s map[string]*sdkschema.Schema) (schema.Schema, error) {
+	attributes := make(map[string]schema.Attribute, len(s))
+
+	for name, attr := range s {
+		if attr.Required || attr.Computed || !attr.Optional {
+			return schema.Schema{}, fmt.Errorf(
+				"%s: only optional provider arguments are supported",
+				name)
+		}
+
+		switch attr.Type {
+		case sdkschema.TypeString:
+			attributes[name] = schema.StringAttribute{
+				Optional:           true,
+				Sensitive:          attr.Sensitive,
+				Description:        attr.Description,
+				DeprecationMessage: attr.Deprecated,
+			}
+		case sdkschema.TypeInt:
+			attributes[name] = schema.Int64Attribute{
+				Optional:           true,
+				Sensitive:          attr.Sensitive,
+				Description:        attr.Description,
+				DeprecationMessage: attr.Deprecated,
+			}
+		case sdkschema.TypeBool:
+			attributes[name] = schema.BoolAttribute{
+				Optional:           true,
+				Sensitive:          attr.Sensitive,
+				Description:        attr.Description,
+				DeprecationMessage: attr.Deprecated,
+			}
+		default:
+			return schema.Schema{}, fmt.Errorf(
+				"%s: provider arguments of type %s are not supported",
+				name,
+				attr.Type)
+		}
+	}
+
+	return schema.Schema{Attributes: attributes}, nil
+}
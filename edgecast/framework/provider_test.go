@@ -0,0 +1,120 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package framework
+
+import (
+	"context"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	sdkschema "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestConfigure(t *testing.T) {
+	t.Parallel()
+
+	config := internal.ProviderConfig{AccountNumber: "A1B2"}
+	cases := []struct {
+		name      string
+		meta      any
+		expectErr bool
+	}{
+		{name: "SDKv2 provider configured", meta: config},
+		{name: "SDKv2 provider not configured", expectErr: true},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			sdkProvider := &sdkschema.Provider{}
+			sdkProvider.SetMeta(c.meta)
+
+			p := New("1.0.0", sdkProvider)()
+			resp := &provider.ConfigureResponse{}
+			p.Configure(context.Background(), provider.ConfigureRequest{}, resp)
+
+			if resp.Diagnostics.HasError() != c.expectErr {
+				t.Fatalf("unexpected diagnostics: %v", resp.Diagnostics)
+			}
+
+			if c.expectErr {
+				return
+			}
+
+			got, ok := ProviderConfig(resp.ResourceData, &resp.Diagnostics)
+			if !ok || got.AccountNumber != config.AccountNumber {
+				t.Fatalf("expected %+v, got %+v", config, got)
+			}
+		})
+	}
+}
+
+func TestProviderConfig(t *testing.T) {
+	t.Parallel()
+
+	var diags diag.Diagnostics
+	if _, ok := ProviderConfig(nil, &diags); ok || diags.HasError() {
+		t.Fatalf("expected no configuration and no error, got %v", diags)
+	}
+
+	if _, ok := ProviderConfig("config", &diags); ok || !diags.HasError() {
+		t.Fatal("expected an error for unexpected provider data")
+	}
+}
+
+func TestConvertSchema(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		schema    map[string]*sdkschema.Schema
+		expectErr bool
+	}{
+		{
+			name: "Supported",
+			schema: map[string]*sdkschema.Schema{
+				"a": {Type: sdkschema.TypeString, Optional: true},
+				"b": {Type: sdkschema.TypeInt, Optional: true},
+				"c": {Type: sdkschema.TypeBool, Optional: true},
+			},
+		},
+		{
+			name: "Unsupported type",
+			schema: map[string]*sdkschema.Schema{
+				"a": {Type: sdkschema.TypeFloat, Optional: true},
+			},
+			expectErr: true,
+		},
+		{
+			name: "Required",
+			schema: map[string]*sdkschema.Schema{
+				"a": {Type: sdkschema.TypeString, Required: true},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			s, err := convertSchema(c.schema)
+			if (err != nil) != c.expectErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if err == nil && len(s.Attributes) != len(c.schema) {
+				t.Fatalf("expected %d attributes, got %d",
+					len(c.schema),
+					len(s.Attributes))
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package helper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// apiErrorPattern matches errors returned by the SDK for failed API calls e.g.
+// "GetOrigin: sendRequest failed (HTTP StatusCode:404): {...}". The SDK does
+// not return typed errors, and it wraps some errors with %v, so the message
+// is the only reliable source of the status code and response body.
+var apiErrorPattern = regexp.MustCompile(
+	`(?s)^(.*?):?\s*sendRequest failed \(HTTP StatusCode:\s*(\d{3})\):\s*(.*)$`)
+
+// APIError describes a failed API call reported by the SDK.
+type APIError struct {
+	// Operation is the text the SDK and provider put in front of the API's
+	// response e.g. "GetOrigin".
+	Operation string
+
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// RequestID identifies the request to Edgecast support, if the API
+	// returned one.
+	RequestID string
+
+	// Messages are the API's error messages that do not refer to a field.
+	Messages []string
+
+	// Fields are the API's validation messages for specific fields.
+	Fields []FieldError
+
+	// Body is the raw response body.
+	Body string
+}
+
+// FieldError is a validation message that the API returned for a field of
+// the request.
+type FieldError struct {
+	// Field is the field as named by the API e.g. "DirectoryName" or
+	// "/domains/0/name".
+	Field   string
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf(
+		"%s: HTTP %d: %s",
+		e.Operation,
+		e.StatusCode,
+		strings.TrimSpace(e.Body))
+}
+
+// Message returns the API's messages as a single string, falling back to the
+// response body if the API returned none.
+func (e *APIError) Message() string {
+	if len(e.Messages) > 0 {
+		return strings.Join(e.Messages, "\n")
+	}
+
+	if len(e.Fields) > 0 {
+		return ""
+	}
+
+	return strings.TrimSpace(e.Body)
+}
+
+// ParseAPIError extracts an APIError from an error returned by the SDK. It
+// returns false if err was not caused by an error response.
+func ParseAPIError(err error) (*APIError, bool) {
+	if err == nil {
+		return nil, false
+	}
+
+	return ParseAPIErrorMessage(err.Error())
+}
+
+// ParseAPIErrorMessage is like ParseAPIError, but parses the text of an error
+// e.g. the summary of a diagnostic.
+func ParseAPIErrorMessage(msg string) (*APIError, bool) {
+	match := apiErrorPattern.FindStringSubmatch(msg)
+	if match == nil {
+		return nil, false
+	}
+
+	code, _ := strconv.Atoi(match[2])
+	apiErr := &APIError{
+		Operation:  strings.TrimSpace(match[1]),
+		StatusCode: code,
+		Body:       match[3],
+	}
+
+	var body any
+	if err := json.Unmarshal([]byte(apiErr.Body), &body); err == nil {
+		apiErr.parseBody(body)
+	}
+
+	return apiErr, true
+}
+
+// parseBody collects the messages, field errors and request ID from the
+// response body. The Edgecast APIs use several error formats, e.g.
+// {"Message": ..., "ModelState": {field: [...]}} for the legacy APIs,
+// {"title": ..., "traceId": ..., "errors": {field: [...]}} for newer APIs, and
+// {"errors": [{"code": ..., "message": ...}]} for WAF.
+func (e *APIError) parseBody(body any) {
+	obj, ok := body.(map[string]any)
+	if !ok {
+		return
+	}
+
+	for key, value := range obj {
+		switch normalizeKey(key) {
+		case "traceid", "requestid", "correlationid":
+			if s, ok := value.(string); ok && len(e.RequestID) == 0 {
+				e.RequestID = s
+			}
+		case "message", "title", "errordescription", "error":
+			if s, ok := value.(string); ok && len(s) > 0 {
+				e.Messages = append(e.Messages, s)
+			}
+		case "modelstate", "errors", "status", "details":
+			e.parseDetails(value)
+		}
+	}
+
+	sort.Strings(e.Messages)
+	sort.SliceStable(e.Fields, func(i, j int) bool {
+		return e.Fields[i].Field < e.Fields[j].Field
+	})
+}
+
+// parseDetails collects messages from either a map of field names to
+// messages, or a list of error objects.
+func (e *APIError) parseDetails(value any) {
+	switch v := value.(type) {
+	case map[string]any:
+		for field, messages := range v {
+			for _, msg := range toStrings(messages) {
+				e.addMessage(field, msg)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			switch detail := item.(type) {
+			case string:
+				e.addMessage("", detail)
+			case map[string]any:
+				e.addMessage(detailField(detail), detailMessage(detail))
+			}
+		}
+	}
+}
+
+func (e *APIError) addMessage(field string, msg string) {
+	if len(msg) == 0 {
+		return
+	}
+
+	// ASP.NET reports errors for the request as a whole under an empty key
+	// or the name of the request parameter.
+	if len(field) == 0 || field == "request" || field == "$" {
+		e.Messages = append(e.Messages, msg)
+		return
+	}
+
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: msg})
+}
+
+// detailField returns the field an error object refers to, if any.
+func detailField(detail map[string]any) string {
+	for key, value := range detail {
+		switch normalizeKey(key) {
+		case "field", "property", "propertyname", "param", "path":
+			if s, ok := value.(string); ok {
+				return s
+			}
+		case "source":
+			if source, ok := value.(map[string]any); ok {
+				for _, k := range []string{"pointer", "parameter"} {
+					if s, ok := source[k].(string); ok {
+						return s
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// detailMessage returns the message of an error object.
+func detailMessage(detail map[string]any) string {
+	messages := make([]string, 0)
+	for key, value := range detail {
+		switch normalizeKey(key) {
+		case "message", "detail", "errormessage", "description":
+			if s, ok := value.(string); ok && len(s) > 0 {
+				messages = append(messages, s)
+			}
+		}
+	}
+
+	if len(messages) == 0 {
+		if s, ok := detail["title"].(string); ok {
+			return s
+		}
+
+		return ""
+	}
+
+	sort.Strings(messages)
+
+	return messages[0]
+}
+
+// normalizeKey lowercases a JSON key and removes separators so that e.g.
+// "traceId", "trace_id" and "Trace-Id" compare equal.
+func normalizeKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
+
+func toStrings(v any) []string {
+	switch s := v.(type) {
+	case string:
+		return []string{s}
+	case []any:
+		values := make([]string, 0, len(s))
+		for _, item := range s {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+
+		return values
+	default:
+		return nil
+	}
+}
+
+// StatusCode returns the HTTP status code of a failed API call, or 0 if err
+// was not caused by an error response.
+func StatusCode(err error) int {
+	if apiErr, ok := ParseAPIError(err); ok {
+		return apiErr.StatusCode
+	}
+
+	return 0
+}
+
+// IsNotFound reports whether err was caused by the API responding that the
+// requested object does not exist.
+func IsNotFound(err error) bool {
+	switch StatusCode(err) {
+	case http.StatusNotFound, http.StatusGone:
+		return true
+	default:
+		return false
+	}
+}
+
+// ReadError is a helper function for errors encountered while reading a
+// resource. If the resource no longer exists, e.g. because it was deleted
+// outside of Terraform, it is removed from the state so that Terraform plans
+// to recreate it. Any other error is returned.
+func ReadError(
+	ctx context.Context,
+	d *schema.ResourceData,
+	err error,
+) diag.Diagnostics {
+	if IsNotFound(err) {
+		tflog.Warn(ctx, "Resource no longer exists, removing it from state",
+			map[string]any{"id": d.Id()})
+		d.SetId("")
+
+		return diag.Diagnostics{}
+	}
+
+	return diag.FromErr(err)
+}
@@ -0,0 +1,209 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package helper_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/helper"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestIsNotFound(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "nil",
+			err:      nil,
+			expected: false,
+		},
+		{
+			name: "not found",
+			err: errors.New(
+				"GetOrigin: sendRequest failed (HTTP StatusCode:404): {}"),
+			expected: true,
+		},
+		{
+			name: "gone",
+			err: errors.New(
+				"sendRequest failed (HTTP StatusCode:410): "),
+			expected: true,
+		},
+		{
+			name: "wrapped with %v",
+			err: fmt.Errorf(
+				"GetZone: %v",
+				errors.New("sendRequest failed (HTTP StatusCode:404): ")),
+			expected: true,
+		},
+		{
+			name: "bad request",
+			err: errors.New(
+				"sendRequest failed (HTTP StatusCode:400): not found"),
+			expected: false,
+		},
+		{
+			name:     "connection error",
+			err:      errors.New("sendRequest: dial tcp: connection refused"),
+			expected: false,
+		},
+	}
+
+	for _, v := range cases {
+		if actual := helper.IsNotFound(v.err); actual != v.expected {
+			t.Errorf("Case '%s': expected %t but got %t", v.name, v.expected, actual)
+		}
+	}
+}
+
+func TestReadError(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		err         error
+		expectedID  string
+		expectError bool
+	}{
+		{
+			name:       "not found",
+			err:        errors.New("sendRequest failed (HTTP StatusCode:404): "),
+			expectedID: "",
+		},
+		{
+			name:        "server error",
+			err:         errors.New("sendRequest failed (HTTP StatusCode:500): "),
+			expectedID:  "123",
+			expectError: true,
+		},
+	}
+
+	for _, v := range cases {
+		d := schema.TestResourceDataRaw(t, map[string]*schema.Schema{}, nil)
+		d.SetId("123")
+
+		diags := helper.ReadError(context.Background(), d, v.err)
+		if diags.HasError() != v.expectError {
+			t.Errorf("Case '%s': unexpected diagnostics %v", v.name, diags)
+		}
+
+		if d.Id() != v.expectedID {
+			t.Errorf(
+				"Case '%s': expected ID %q but got %q",
+				v.name,
+				v.expectedID,
+				d.Id())
+		}
+	}
+}
+
+func TestParseAPIError(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name     string
+		err      error
+		expected *helper.APIError
+	}{
+		{
+			name: "legacy API with model state",
+			err: errors.New(
+				`AddOrigin: sendRequest failed (HTTP StatusCode:400): ` +
+					`{"Message":"The request is invalid.",` +
+					`"ModelState":{"request.DirectoryName":["Required."],` +
+					`"":["Malformed body."]}}`),
+			expected: &helper.APIError{
+				Operation:  "AddOrigin",
+				StatusCode: 400,
+				Messages:   []string{"Malformed body.", "The request is invalid."},
+				Fields: []helper.FieldError{
+					{Field: "request.DirectoryName", Message: "Required."},
+				},
+			},
+		},
+		{
+			name: "problem details",
+			err: errors.New(
+				`sendRequest failed (HTTP StatusCode:400): ` +
+					`{"title":"One or more validation errors occurred.",` +
+					`"status":400,"traceId":"00-abc-01",` +
+					`"errors":{"Name":["The Name field is required."]}}`),
+			expected: &helper.APIError{
+				StatusCode: 400,
+				RequestID:  "00-abc-01",
+				Messages:   []string{"One or more validation errors occurred."},
+				Fields: []helper.FieldError{
+					{Field: "Name", Message: "The Name field is required."},
+				},
+			},
+		},
+		{
+			name: "list of errors",
+			err: fmt.Errorf(
+				"CertificatePost: %v",
+				errors.New(`sendRequest failed (HTTP StatusCode:422): `+
+					`{"errors":[{"code":"invalid","detail":"Bad domain.",`+
+					`"source":{"pointer":"/domains/0/name"}},`+
+					`{"code":"400","message":"Invalid certificate."}]}`)),
+			expected: &helper.APIError{
+				Operation:  "CertificatePost",
+				StatusCode: 422,
+				Messages:   []string{"Invalid certificate."},
+				Fields: []helper.FieldError{
+					{Field: "/domains/0/name", Message: "Bad domain."},
+				},
+			},
+		},
+		{
+			name: "plain text body",
+			err: errors.New(
+				"GetZone: sendRequest failed (HTTP StatusCode:503): unavailable"),
+			expected: &helper.APIError{
+				Operation:  "GetZone",
+				StatusCode: 503,
+			},
+		},
+	}
+
+	for _, v := range cases {
+		actual, ok := helper.ParseAPIError(v.err)
+		if !ok {
+			t.Fatalf("Case '%s': expected an API error", v.name)
+		}
+
+		// The body is checked through Message() instead.
+		actual.Body = ""
+		if !reflect.DeepEqual(v.expected, actual) {
+			t.Errorf(
+				"Case '%s': expected %+v but got %+v",
+				v.name,
+				v.expected,
+				actual)
+		}
+	}
+
+	if _, ok := helper.ParseAPIError(errors.New("dial tcp: refused")); ok {
+		t.Error("expected no API error for a connection failure")
+	}
+}
+
+func TestAPIError_Message(t *testing.T) {
+	t.Parallel()
+
+	apiErr, _ := helper.ParseAPIError(errors.New(
+		"sendRequest failed (HTTP StatusCode:503): service unavailable"))
+	if msg := apiErr.Message(); msg != "service unavailable" {
+		t.Errorf("expected the body as the message but got %q", msg)
+	}
+}
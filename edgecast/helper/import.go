@@ -14,6 +14,19 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+// nameKey is the import ID key that identifies an object by name, for
+// importers with a NameLookup.
+const nameKey = "name"
+
+// NameLookup returns the ID of the object with the given name. d holds the
+// other values of the import ID, e.g. the account number.
+type NameLookup func(
+	ctx context.Context,
+	d *schema.ResourceData,
+	m interface{},
+	name string,
+) (string, error)
+
 func parseKeys(s string) []string {
 	s = strings.TrimSpace(s)
 	if s == "" {
@@ -25,9 +38,24 @@ func parseKeys(s string) []string {
 
 // Import provides a schema.ResourceImporter to parse keys using the
 // ReadContextFunc.
+//
+// The import ID holds the values of keys separated by ":", either in order,
+// e.g. "0001:123", or named, e.g. "account_number=0001:id=123". Trailing
+// values may be omitted, and a single value is the ID alone.
 func Import(
 	read schema.ReadContextFunc,
 	keys ...string,
+) *schema.ResourceImporter {
+	return ImportWithLookup(read, nil, keys...)
+}
+
+// ImportWithLookup is like Import, but also accepts a "name" key in place of
+// the ID, e.g. "account_number=0001:name=My Rule", which lookup resolves to
+// the ID.
+func ImportWithLookup(
+	read schema.ReadContextFunc,
+	lookup NameLookup,
+	keys ...string,
 ) *schema.ResourceImporter {
 	return &schema.ResourceImporter{
 		StateContext: func(
@@ -35,29 +63,45 @@ func Import(
 			d *schema.ResourceData,
 			m interface{},
 		) ([]*schema.ResourceData, error) {
-			vals := parseKeys(d.Id())
-			var id string
-			if len(keys) == 0 || len(vals) == 0 {
+			importID := d.Id()
+			if len(keys) == 0 || len(parseKeys(importID)) == 0 {
 				return []*schema.ResourceData{d}, nil
 			}
 
-			for i, key := range keys {
-				if strings.EqualFold(key, "id") {
-					id = vals[i]
-					d.SetId(id)
+			vals, err := parseImportID(importID, keys, lookup != nil)
+			if err != nil {
+				return nil, fmt.Errorf(
+					"invalid import ID %q: %w\nExpected %s",
+					importID,
+					err,
+					importFormat(keys, lookup != nil))
+			}
 
+			for _, key := range keys {
+				// Empty values are skipped so that defaults still apply e.g.
+				// ":123" imports ID 123 under the provider's account number.
+				v := vals[key]
+				if strings.EqualFold(key, "id") || len(v) == 0 {
 					continue
 				}
 
-				if i < len(vals) {
-					err := d.Set(key, vals[i])
-					if err != nil {
-						v, _ := strconv.Atoi(vals[i])
-						_ = d.Set(key, v)
-					}
+				err := d.Set(key, v)
+				if err != nil {
+					n, _ := strconv.Atoi(v)
+					_ = d.Set(key, n)
+				}
+			}
+
+			id := vals["id"]
+			if name, ok := vals[nameKey]; ok {
+				id, err = lookup(ctx, d, m, name)
+				if err != nil {
+					return nil, err
 				}
 			}
 
+			d.SetId(id)
+
 			if res := read(ctx, d, m); res != nil {
 				for _, e := range res {
 					if e.Severity == diag.Error {
@@ -72,3 +116,183 @@ func Import(
 		},
 	}
 }
+
+// parseImportID returns the values of keys in an import ID, keyed by their
+// lower case names. If byName is set, a "name" key may be given in place of
+// the ID.
+func parseImportID(
+	importID string,
+	keys []string,
+	byName bool,
+) (map[string]string, error) {
+	segments := parseKeys(importID)
+	named := false
+	for _, s := range segments {
+		if strings.Contains(s, "=") {
+			named = true
+			break
+		}
+	}
+
+	if !named {
+		return parsePositional(segments, keys)
+	}
+
+	allowed := make(map[string]bool, len(keys)+1)
+	for _, key := range keys {
+		allowed[strings.ToLower(key)] = true
+	}
+
+	if byName {
+		allowed[nameKey] = true
+	}
+
+	vals := make(map[string]string, len(segments))
+	for _, s := range segments {
+		key, v, ok := strings.Cut(s, "=")
+		if !ok {
+			return nil, fmt.Errorf(
+				"%q is not a key=value pair; use key=value pairs for all "+
+					"values or for none",
+				s)
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !allowed[key] {
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+
+		if _, ok := vals[key]; ok {
+			return nil, fmt.Errorf("key %q is given more than once", key)
+		}
+
+		vals[key] = v
+	}
+
+	if !allowed["id"] {
+		return vals, nil
+	}
+
+	_, hasName := vals[nameKey]
+	if hasName && len(vals["id"]) > 0 {
+		return nil, fmt.Errorf("id and name cannot both be given")
+	}
+
+	if hasName && len(vals[nameKey]) == 0 {
+		return nil, fmt.Errorf("name is empty")
+	}
+
+	if !hasName && len(vals["id"]) == 0 {
+		if byName {
+			return nil, fmt.Errorf("id or name is required")
+		}
+
+		return nil, fmt.Errorf("id is required")
+	}
+
+	return vals, nil
+}
+
+// parsePositional returns the values of keys in import ID segments given in
+// the order of keys.
+func parsePositional(
+	segments []string,
+	keys []string,
+) (map[string]string, error) {
+	if len(segments) > len(keys) {
+		return nil, fmt.Errorf(
+			"got %d values, but at most %d are accepted",
+			len(segments),
+			len(keys))
+	}
+
+	// A single value is the ID alone, with any other keys e.g. the account
+	// number left to their defaults.
+	if len(segments) == 1 {
+		segments = alignToID(keys, segments[0])
+	}
+
+	vals := make(map[string]string, len(keys))
+	for i, key := range keys {
+		key = strings.ToLower(key)
+		if i < len(segments) {
+			vals[key] = segments[i]
+		}
+
+		if key == "id" && len(vals[key]) == 0 {
+			return nil, fmt.Errorf("id is required")
+		}
+	}
+
+	return vals, nil
+}
+
+// alignToID returns import values with id at the position of the "id" key.
+func alignToID(keys []string, id string) []string {
+	for i, key := range keys {
+		if strings.EqualFold(key, "id") {
+			vals := make([]string, i+1)
+			vals[i] = id
+
+			return vals
+		}
+	}
+
+	return []string{id}
+}
+
+// importFormat describes the import ID formats accepted for keys, e.g.
+// "ACCOUNT_NUMBER:ID, or key=value pairs such as
+// account_number=ACCOUNT_NUMBER:id=ID".
+func importFormat(keys []string, byName bool) string {
+	positional := make([]string, 0, len(keys))
+	named := make([]string, 0, len(keys))
+	for _, key := range keys {
+		positional = append(positional, strings.ToUpper(key))
+		named = append(
+			named,
+			strings.ToLower(key)+"="+strings.ToUpper(key))
+	}
+
+	format := fmt.Sprintf(
+		"%s, or key=value pairs such as %s",
+		strings.Join(positional, ":"),
+		strings.Join(named, ":"))
+
+	if byName {
+		format += ", with name=NAME in place of id=ID"
+	}
+
+	return format
+}
+
+// FindIDByName returns the ID of the one item with the given name. kind
+// describes the items in errors, e.g. "access rule".
+func FindIDByName[T any](
+	kind string,
+	name string,
+	items []T,
+	nameOf func(T) string,
+	idOf func(T) string,
+) (string, error) {
+	var ids []string
+	for _, item := range items {
+		if nameOf(item) == name {
+			ids = append(ids, idOf(item))
+		}
+	}
+
+	switch len(ids) {
+	case 0:
+		return "", fmt.Errorf("no %s named %q was found", kind, name)
+	case 1:
+		return ids[0], nil
+	default:
+		return "", fmt.Errorf(
+			"%d %ss named %q were found, import one by ID: %s",
+			len(ids),
+			kind,
+			name,
+			strings.Join(ids, ", "))
+	}
+}
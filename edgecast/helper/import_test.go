@@ -116,3 +116,218 @@ func TestImporter_NoKeys(t *testing.T) {
 	expect.NotNil(rds)
 	expect.Empty(rd.Id())
 }
+
+func TestImporter_IDOnly(t *testing.T) {
+	expect := assert.New(t)
+	i, rd := createResourceData(t, "account_number", "id", "media_type_id")
+	rd.SetId("456")
+	rds, err := i.StateContext(context.Background(), rd, nil)
+	expect.NoError(err)
+	expect.NotNil(rds)
+	expect.Equal("456", rd.Id())
+	expect.Equal("", rd.Get("account_number"))
+	expect.Equal("", rd.Get("media_type_id"))
+}
+
+func TestImporter_EmptyAccountNumber(t *testing.T) {
+	expect := assert.New(t)
+	i, rd := createResourceData(t, "account_number", "id", "media_type_id")
+	rd.Set("account_number", "123")
+	rd.SetId(":456:789")
+	rds, err := i.StateContext(context.Background(), rd, nil)
+	expect.NoError(err)
+	expect.NotNil(rds)
+	expect.Equal("456", rd.Id())
+	expect.Equal("123", rd.Get("account_number"))
+	expect.Equal("789", rd.Get("media_type_id"))
+}
+
+func TestImporter_NamedKeys(t *testing.T) {
+	expect := assert.New(t)
+	i, rd := createResourceData(t, "account_number", "id", "media_type_id")
+	rd.SetId("media_type_id=789:id=456:account_number=123")
+	rds, err := i.StateContext(context.Background(), rd, nil)
+	expect.NoError(err)
+	expect.NotNil(rds)
+	expect.Equal("456", rd.Id())
+	expect.Equal("123", rd.Get("account_number"))
+	expect.Equal("789", rd.Get("media_type_id"))
+}
+
+func TestImporter_NamedKeysDefaultAccountNumber(t *testing.T) {
+	expect := assert.New(t)
+	i, rd := createResourceData(t, "account_number", "id")
+	rd.Set("account_number", "123")
+	rd.SetId("id=456")
+	_, err := i.StateContext(context.Background(), rd, nil)
+	expect.NoError(err)
+	expect.Equal("456", rd.Id())
+	expect.Equal("123", rd.Get("account_number"))
+}
+
+func TestImporter_InvalidIDs(t *testing.T) {
+	cases := []struct {
+		name   string
+		id     string
+		errMsg string
+	}{
+		{
+			name:   "Too many values",
+			id:     "123:456:789:0",
+			errMsg: "got 4 values, but at most 3 are accepted",
+		},
+		{
+			name:   "Empty ID",
+			id:     "123::789",
+			errMsg: "id is required",
+		},
+		{
+			name:   "Mixed positional and named",
+			id:     "123:id=456",
+			errMsg: `"123" is not a key=value pair`,
+		},
+		{
+			name:   "Unknown key",
+			id:     "account=123:id=456",
+			errMsg: `unknown key "account"`,
+		},
+		{
+			name:   "Duplicate key",
+			id:     "id=123:id=456",
+			errMsg: `key "id" is given more than once`,
+		},
+		{
+			name:   "Missing named ID",
+			id:     "account_number=123",
+			errMsg: "id is required",
+		},
+		{
+			name:   "Name without lookup",
+			id:     "name=My Origin",
+			errMsg: `unknown key "name"`,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			expect := assert.New(t)
+			i, rd := createResourceData(
+				t,
+				"account_number",
+				"id",
+				"media_type_id")
+			rd.SetId(c.id)
+			_, err := i.StateContext(context.Background(), rd, nil)
+			expect.ErrorContains(err, c.errMsg)
+			expect.ErrorContains(
+				err,
+				"Expected ACCOUNT_NUMBER:ID:MEDIA_TYPE_ID, or key=value pairs "+
+					"such as account_number=ACCOUNT_NUMBER:id=ID:"+
+					"media_type_id=MEDIA_TYPE_ID")
+		})
+	}
+}
+
+func TestImporter_Lookup(t *testing.T) {
+	lookup := func(
+		_ context.Context,
+		d *schema.ResourceData,
+		_ interface{},
+		name string,
+	) (string, error) {
+		return helper.FindIDByName(
+			"rule",
+			name,
+			[]string{d.Get("account_number").(string) + "/rule-1", "rule-2"},
+			func(s string) string { return s },
+			func(s string) string { return "id-" + s })
+	}
+
+	cases := []struct {
+		name   string
+		id     string
+		expect string
+		errMsg string
+
+		// invalid is set if the import ID itself is invalid, in which case
+		// the error describes the expected format.
+		invalid bool
+	}{
+		{
+			name:   "Found",
+			id:     "account_number=123:name=123/rule-1",
+			expect: "id-123/rule-1",
+		},
+		{
+			name:   "Not found",
+			id:     "account_number=123:name=rule-3",
+			errMsg: `no rule named "rule-3" was found`,
+		},
+		{
+			name:    "ID and name",
+			id:      "id=1:name=rule-2",
+			errMsg:  "id and name cannot both be given",
+			invalid: true,
+		},
+		{
+			name:    "Neither ID nor name",
+			id:      "account_number=123",
+			errMsg:  "id or name is required",
+			invalid: true,
+		},
+		{
+			name:   "Positional ID",
+			id:     "123:456",
+			expect: "456",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			expect := assert.New(t)
+			i := helper.ImportWithLookup(
+				readSchema,
+				lookup,
+				"account_number",
+				"id")
+			rd := schema.TestResourceDataRaw(
+				t,
+				map[string]*schema.Schema{
+					"account_number": {Type: schema.TypeString, Optional: true},
+				},
+				map[string]interface{}{})
+			rd.SetId(c.id)
+
+			_, err := i.StateContext(context.Background(), rd, nil)
+			if len(c.errMsg) > 0 {
+				expect.ErrorContains(err, c.errMsg)
+				if c.invalid {
+					expect.ErrorContains(err, "name=NAME in place of id=ID")
+				}
+
+				return
+			}
+
+			expect.NoError(err)
+			expect.Equal(c.expect, rd.Id())
+		})
+	}
+}
+
+func TestFindIDByName(t *testing.T) {
+	expect := assert.New(t)
+	items := []string{"a", "b", "b"}
+	same := func(s string) string { return s }
+
+	id, err := helper.FindIDByName("rule", "a", items, same, same)
+	expect.NoError(err)
+	expect.Equal("a", id)
+
+	_, err = helper.FindIDByName("rule", "b", items, same, same)
+	expect.ErrorContains(err, `2 rules named "b" were found, import one by ID`)
+
+	_, err = helper.FindIDByName("rule", "c", items, same, same)
+	expect.ErrorContains(err, `no rule named "c" was found`)
+}
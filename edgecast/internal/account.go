@@ -0,0 +1,106 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// InheritAccountNumber wraps the CRUD and import functions of an
+// account-scoped resource so that its key attribute, e.g. account_number,
+// defaults to the provider's account_number. If neither is set, CRUD
+// operations fail with a diagnostic pointing at the attribute. The
+// attribute must be Optional and Computed.
+func InheritAccountNumber(resource *schema.Resource, key string) *schema.Resource {
+	type crudFunc = func(
+		context.Context,
+		*schema.ResourceData,
+		interface{},
+	) diag.Diagnostics
+
+	wrap := func(f crudFunc) crudFunc {
+		if f == nil {
+			return nil
+		}
+
+		return func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) diag.Diagnostics {
+			if diags := setAccountNumber(d, m, key, true); diags != nil {
+				return diags
+			}
+
+			return f(ctx, d, m)
+		}
+	}
+
+	resource.CreateContext = wrap(resource.CreateContext)
+	resource.ReadContext = wrap(resource.ReadContext)
+	resource.UpdateContext = wrap(resource.UpdateContext)
+	resource.DeleteContext = wrap(resource.DeleteContext)
+
+	// The import ID may omit the account number, in which case the
+	// provider's is used. An account number in the import ID takes
+	// precedence as the importer sets it afterwards.
+	if resource.Importer != nil && resource.Importer.StateContext != nil {
+		importState := resource.Importer.StateContext
+		resource.Importer.StateContext = func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) ([]*schema.ResourceData, error) {
+			setAccountNumber(d, m, key, false)
+
+			return importState(ctx, d, m)
+		}
+	}
+
+	return resource
+}
+
+// setAccountNumber stores the provider's account number in the resource data
+// if the resource does not set its own.
+func setAccountNumber(
+	d *schema.ResourceData,
+	m interface{},
+	key string,
+	required bool,
+) diag.Diagnostics {
+	if v, ok := d.GetOk(key); ok && len(v.(string)) > 0 {
+		return nil
+	}
+
+	config, ok := m.(ProviderConfig)
+	if ok && len(config.AccountNumber) > 0 {
+		if err := d.Set(key, config.AccountNumber); err != nil {
+			return diag.FromErr(err)
+		}
+
+		return nil
+	}
+
+	if !required {
+		return nil
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("Missing %s", key),
+			Detail: fmt.Sprintf(
+				"Set %s on the resource, or set account_number in the "+
+					"provider configuration or through the %s environment "+
+					"variable.",
+				key,
+				EnvVarName("account_number")),
+			AttributePath: cty.GetAttrPath(key),
+		},
+	}
+}
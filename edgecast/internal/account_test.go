@@ -0,0 +1,182 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"context"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/helper"
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newAccountScopedResource creates a resource whose CRUD functions record the
+// account number they see.
+func newAccountScopedResource(seen *string) *schema.Resource {
+	crud := func(
+		ctx context.Context,
+		d *schema.ResourceData,
+		m interface{},
+	) diag.Diagnostics {
+		*seen = d.Get("account_number").(string)
+		return nil
+	}
+
+	return &schema.Resource{
+		CreateContext: crud,
+		ReadContext:   crud,
+		UpdateContext: crud,
+		DeleteContext: crud,
+		Importer:      helper.Import(crud, "account_number", "id"),
+		Schema: map[string]*schema.Schema{
+			"account_number": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func TestInheritAccountNumber(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		resourceAccount string
+		providerAccount string
+		want            string
+		expectError     bool
+	}{
+		{
+			name:            "inherited from provider",
+			providerAccount: "A1",
+			want:            "A1",
+		},
+		{
+			name:            "resource takes precedence",
+			resourceAccount: "B2",
+			providerAccount: "A1",
+			want:            "B2",
+		},
+		{
+			name:            "resource only",
+			resourceAccount: "B2",
+			want:            "B2",
+		},
+		{
+			name:        "neither set",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var seen string
+			resource := internal.InheritAccountNumber(
+				newAccountScopedResource(&seen),
+				"account_number")
+
+			raw := map[string]any{}
+
+			if len(tt.resourceAccount) > 0 {
+				raw["account_number"] = tt.resourceAccount
+			}
+
+			d := schema.TestResourceDataRaw(t, resource.Schema, raw)
+			config := internal.ProviderConfig{AccountNumber: tt.providerAccount}
+
+			diags := resource.CreateContext(context.Background(), d, config)
+			if tt.expectError {
+				if !diags.HasError() {
+					t.Fatal("expected error, but got none")
+				}
+
+				path := cty.GetAttrPath("account_number")
+				if !diags[0].AttributePath.Equals(path) {
+					t.Errorf("got path %#v, want %#v", diags[0].AttributePath, path)
+				}
+
+				return
+			}
+
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+
+			if seen != tt.want {
+				t.Errorf("got %q, want %q", seen, tt.want)
+			}
+
+			if got := d.Get("account_number"); got != tt.want {
+				t.Errorf("got %q in state, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInheritAccountNumber_Import(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		importID string
+		wantID   string
+		want     string
+	}{
+		{
+			name:     "ID only",
+			importID: "123",
+			wantID:   "123",
+			want:     "A1",
+		},
+		{
+			name:     "empty account number",
+			importID: ":123",
+			wantID:   "123",
+			want:     "A1",
+		},
+		{
+			name:     "account number and ID",
+			importID: "B2:123",
+			wantID:   "123",
+			want:     "B2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var seen string
+			resource := internal.InheritAccountNumber(
+				newAccountScopedResource(&seen),
+				"account_number")
+
+			d := schema.TestResourceDataRaw(t, resource.Schema, map[string]any{})
+			d.SetId(tt.importID)
+			config := internal.ProviderConfig{AccountNumber: "A1"}
+
+			_, err := resource.Importer.StateContext(context.Background(), d, config)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if d.Id() != tt.wantID {
+				t.Errorf("got ID %q, want %q", d.Id(), tt.wantID)
+			}
+
+			if seen != tt.want {
+				t.Errorf("got %q, want %q", seen, tt.want)
+			}
+		})
+	}
+}
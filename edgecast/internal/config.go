@@ -4,11 +4,32 @@ package internal
 
 import (
 	"fmt"
+	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
+const (
+	// DefaultAPIAddress is the base url of Edgecast resource APIs.
+	DefaultAPIAddress string = "https://api.vdms.io"
+
+	// DefaultAPIAddressLegacy is the base url of legacy Edgecast resource
+	// APIs.
+	DefaultAPIAddressLegacy string = "https://api.edgecast.com"
+
+	// DefaultIDSAddress is the base url of Edgecast identity APIs.
+	DefaultIDSAddress string = "https://id.vdms.io"
+
+	// EnvVarPrefix is prepended to the upper-cased name of a provider
+	// setting to form the environment variable it falls back to.
+	EnvVarPrefix string = "EDGECAST_"
+)
+
 // ProviderConfig holds configuration values for the provider.
 type ProviderConfig struct {
 	APIToken         string `json:"-"` // sensitive.
@@ -25,19 +46,101 @@ type ProviderConfig struct {
 	PartnerID        int
 	PartnerUserID    int
 	UserAgent        string
+
+	// MaxRetries is the number of times a failed API call is retried.
+	MaxRetries int
+
+	// RetryWaitMin is the minimum wait between retries.
+	RetryWaitMin time.Duration
+
+	// RetryWaitMax is the maximum wait between retries.
+	RetryWaitMax time.Duration
+
+	// MaxRequestsPerSecond limits the rate of API calls. Zero means no limit.
+	MaxRequestsPerSecond int
+
+	// MaxConcurrentRequests limits the number of API calls in flight. Zero
+	// means no limit.
+	MaxConcurrentRequests int
+
+	// Limiter applies MaxRequestsPerSecond and MaxConcurrentRequests to all
+	// API calls.
+	Limiter *RequestLimiter `json:"-"`
+
+	// ProxyURL is the proxy through which all API and IDS calls are sent.
+	ProxyURL string
+
+	// CABundleFile is a PEM file of certificate authorities trusted in
+	// addition to the system's.
+	CABundleFile string
+
+	// ClientCertFile and ClientKeyFile are the PEM files of the client
+	// certificate presented to the API and IDS.
+	ClientCertFile string
+	ClientKeyFile  string
+
+	// Transport applies ProxyURL, CABundleFile, ClientCertFile and
+	// ClientKeyFile to API calls. It is nil if none of them are set.
+	Transport *http.Transport `json:"-"`
+
+	// TransportHook, if set, wraps the transport of every API and IDS call,
+	// e.g. so that tests can record or replay API traffic. It is not
+	// configurable from Terraform.
+	TransportHook func(http.RoundTripper) http.RoundTripper `json:"-"`
+
+	// ReadOnly blocks every create, update and delete so that the provider
+	// can only read existing objects.
+	ReadOnly bool
+
+	// Services holds the SDK services shared by all resources.
+	Services *ServiceRegistry `json:"-"`
+
+	// ServiceOverrides replaces the SDK services registered under the given
+	// names, e.g. with mocks in unit tests. Each value must have the type that
+	// resources request from GetService. It is not configurable from
+	// Terraform.
+	ServiceOverrides map[string]any `json:"-"`
+
+	// scope holds the context of a single CRUD operation and the error
+	// responses received by its API calls. It is set by BindContext.
+	scope *serviceScope
+
+	// Sources describes where each provider setting was read from, keyed by
+	// setting name. It never contains the values themselves.
+	Sources map[string]string
+}
+
+// EnvVarName returns the name of the environment variable that the given
+// provider setting falls back to e.g. api_token -> EDGECAST_API_TOKEN.
+func EnvVarName(setting string) string {
+	return EnvVarPrefix + strings.ToUpper(setting)
 }
 
 // ExpandProviderConfig reads ProviderConfig using the TF Resource Data.
+// Each setting is read from the provider block first, then from its
+// EDGECAST_* environment variable, then from the selected credentials file
+// profile, and finally falls back to its default value.
 func ExpandProviderConfig(d *schema.ResourceData) (*ProviderConfig, error) {
+	r := settingResolver{d: d, sources: make(map[string]string)}
+
+	if err := r.loadProfile(); err != nil {
+		return nil, err
+	}
+
 	config := &ProviderConfig{
-		APIToken:         d.Get("api_token").(string),
-		AccountNumber:    d.Get("account_number").(string),
-		IdsClientID:      d.Get("ids_client_id").(string),
-		IdsClientSecret:  d.Get("ids_client_secret").(string),
-		IdsScope:         d.Get("ids_scope").(string),
-		IDSAddress:       d.Get("ids_address").(string),
-		APIAddress:       d.Get("api_address").(string),
-		APIAddressLegacy: d.Get("api_address_legacy").(string),
+		APIToken:         r.getString("api_token", ""),
+		AccountNumber:    r.getString("account_number", ""),
+		IdsClientID:      r.getString("ids_client_id", ""),
+		IdsClientSecret:  r.getString("ids_client_secret", ""),
+		IdsScope:         r.getString("ids_scope", ""),
+		IDSAddress:       r.getString("ids_address", DefaultIDSAddress),
+		APIAddress:       r.getString("api_address", DefaultAPIAddress),
+		APIAddressLegacy: r.getString("api_address_legacy", DefaultAPIAddressLegacy),
+		ProxyURL:         r.getString("proxy_url", ""),
+		CABundleFile:     r.getString("ca_bundle_file", ""),
+		ClientCertFile:   r.getString("client_cert_file", ""),
+		ClientKeyFile:    r.getString("client_key_file", ""),
+		Sources:          r.sources,
 	}
 
 	var err error
@@ -57,13 +160,234 @@ func ExpandProviderConfig(d *schema.ResourceData) (*ProviderConfig, error) {
 		return nil, fmt.Errorf("failed to parse legacy API URL: %w", err)
 	}
 
-	if partnerUserIDValue, ok := d.GetOk("partner_user_id"); ok {
-		config.PartnerUserID = partnerUserIDValue.(int)
+	if len(config.ProxyURL) > 0 {
+		if _, err := ParseProxyURL(config.ProxyURL); err != nil {
+			return nil, err
+		}
 	}
 
-	if partnerIDValue, ok := d.GetOk("partner_id"); ok {
-		config.PartnerID = partnerIDValue.(int)
+	if (len(config.ClientCertFile) > 0) != (len(config.ClientKeyFile) > 0) {
+		return nil, fmt.Errorf(
+			"client_cert_file and client_key_file must be set together")
+	}
+
+	config.PartnerUserID, err = r.getInt("partner_user_id")
+	if err != nil {
+		return nil, err
+	}
+
+	config.PartnerID, err = r.getInt("partner_id")
+	if err != nil {
+		return nil, err
+	}
+
+	config.MaxRetries, err = r.getIntWithDefault("max_retries", DefaultMaxRetries)
+	if err != nil {
+		return nil, err
+	}
+
+	config.RetryWaitMin, err = r.getDuration("retry_wait_min", DefaultRetryWaitMin)
+	if err != nil {
+		return nil, err
+	}
+
+	config.RetryWaitMax, err = r.getDuration("retry_wait_max", DefaultRetryWaitMax)
+	if err != nil {
+		return nil, err
+	}
+
+	config.MaxRequestsPerSecond, err = r.getInt("max_requests_per_second")
+	if err != nil {
+		return nil, err
+	}
+
+	config.MaxConcurrentRequests, err = r.getInt("max_concurrent_requests")
+	if err != nil {
+		return nil, err
+	}
+
+	config.ReadOnly, err = r.getBool("read_only")
+	if err != nil {
+		return nil, err
+	}
+
+	if config.MaxRequestsPerSecond < 0 {
+		return nil, fmt.Errorf("max_requests_per_second must not be negative")
+	}
+
+	if config.MaxConcurrentRequests < 0 {
+		return nil, fmt.Errorf("max_concurrent_requests must not be negative")
+	}
+
+	if config.MaxRetries < 0 {
+		return nil, fmt.Errorf("max_retries must not be negative")
+	}
+
+	if config.RetryWaitMin > config.RetryWaitMax {
+		return nil, fmt.Errorf(
+			"retry_wait_min (%s) must not exceed retry_wait_max (%s)",
+			config.RetryWaitMin,
+			config.RetryWaitMax)
 	}
 
 	return config, nil
 }
+
+// settingResolver looks up provider settings across all supported sources
+// and records which source supplied each one.
+type settingResolver struct {
+	d             *schema.ResourceData
+	profile       map[string]string
+	profileSource string
+	sources       map[string]string
+}
+
+// loadProfile reads the credentials file section selected by the profile
+// setting. The profile and credentials file settings themselves may only be
+// set in the provider block or through the environment.
+func (r *settingResolver) loadProfile() error {
+	profile, _ := r.lookup("profile")
+	if len(profile) == 0 {
+		return nil
+	}
+
+	path, ok := r.lookup("credentials_file")
+	if !ok {
+		var err error
+
+		path, err = DefaultCredentialsFile()
+		if err != nil {
+			return err
+		}
+	}
+
+	section, err := ReadCredentialsProfile(path, profile)
+	if err != nil {
+		return err
+	}
+
+	r.profile = section
+	r.profileSource = fmt.Sprintf("profile %q in %s", profile, path)
+
+	return nil
+}
+
+// lookup reads a setting from the provider block or its environment variable.
+func (r *settingResolver) lookup(key string) (string, bool) {
+	if v, ok := r.d.GetOk(key); ok {
+		r.sources[key] = "provider configuration"
+		return fmt.Sprint(v), true
+	}
+
+	env := EnvVarName(key)
+	if v := os.Getenv(env); len(v) > 0 {
+		r.sources[key] = "environment variable " + env
+		return v, true
+	}
+
+	return "", false
+}
+
+// resolve reads a setting from the provider block, its environment variable
+// or the selected credentials file profile, in that order.
+func (r *settingResolver) resolve(key string) (string, bool) {
+	if v, ok := r.lookup(key); ok {
+		return v, true
+	}
+
+	if v := r.profile[key]; len(v) > 0 {
+		r.sources[key] = r.profileSource
+		return v, true
+	}
+
+	return "", false
+}
+
+func (r *settingResolver) getString(key string, defaultValue string) string {
+	if v, ok := r.resolve(key); ok {
+		return v
+	}
+
+	if len(defaultValue) > 0 {
+		r.sources[key] = "default"
+	}
+
+	return defaultValue
+}
+
+func (r *settingResolver) getInt(key string) (int, error) {
+	return r.getIntWithDefault(key, 0)
+}
+
+// getIntWithDefault reads an integer setting. Unlike strings, an explicit
+// zero in the provider block takes precedence over other sources.
+func (r *settingResolver) getIntWithDefault(
+	key string,
+	defaultValue int,
+) (int, error) {
+	//nolint:staticcheck // GetOk cannot tell an explicit zero from unset.
+	if v, ok := r.d.GetOkExists(key); ok {
+		r.sources[key] = "provider configuration"
+		return v.(int), nil
+	}
+
+	v, ok := r.resolve(key)
+	if !ok {
+		if defaultValue != 0 {
+			r.sources[key] = "default"
+		}
+
+		return defaultValue, nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s from %s is not a number", key, r.sources[key])
+	}
+
+	return i, nil
+}
+
+// getBool reads a boolean setting. An explicit false in the provider block
+// takes precedence over other sources.
+func (r *settingResolver) getBool(key string) (bool, error) {
+	//nolint:staticcheck // GetOk cannot tell an explicit false from unset.
+	if v, ok := r.d.GetOkExists(key); ok {
+		r.sources[key] = "provider configuration"
+		return v.(bool), nil
+	}
+
+	v, ok := r.resolve(key)
+	if !ok {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, fmt.Errorf("%s from %s is not a boolean", key, r.sources[key])
+	}
+
+	return b, nil
+}
+
+func (r *settingResolver) getDuration(
+	key string,
+	defaultValue time.Duration,
+) (time.Duration, error) {
+	v, ok := r.resolve(key)
+	if !ok {
+		r.sources[key] = "default"
+		return defaultValue, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf(
+			"%s from %s is not a valid duration: %w",
+			key,
+			r.sources[key],
+			err)
+	}
+
+	return d, nil
+}
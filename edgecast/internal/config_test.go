@@ -3,8 +3,12 @@
 package internal_test
 
 import (
+	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"terraform-provider-edgecast/edgecast"
 	"terraform-provider-edgecast/edgecast/internal"
@@ -51,6 +55,9 @@ func TestExpandProviderConfig(t *testing.T) {
 				"api_address_legacy": apiAddressLegacy,
 				"partner_user_id":    partnerUserID,
 				"partner_id":         partnerID,
+				"max_retries":        0,
+				"retry_wait_min":     "500ms",
+				"retry_wait_max":     "10s",
 			},
 			want: &internal.ProviderConfig{
 				APIToken:         apiTok,
@@ -66,9 +73,52 @@ func TestExpandProviderConfig(t *testing.T) {
 				APIURLLegacy:     apiLegacyURL,
 				PartnerUserID:    partnerUserID,
 				PartnerID:        partnerID,
+				MaxRetries:       0,
+				RetryWaitMin:     500 * time.Millisecond,
+				RetryWaitMax:     10 * time.Second,
+				Sources: map[string]string{
+					"api_token":          "provider configuration",
+					"account_number":     "provider configuration",
+					"ids_client_id":      "provider configuration",
+					"ids_client_secret":  "provider configuration",
+					"ids_scope":          "provider configuration",
+					"ids_address":        "provider configuration",
+					"api_address":        "provider configuration",
+					"api_address_legacy": "provider configuration",
+					"partner_user_id":    "provider configuration",
+					"partner_id":         "provider configuration",
+					"max_retries":        "provider configuration",
+					"retry_wait_min":     "provider configuration",
+					"retry_wait_max":     "provider configuration",
+				},
 			},
 			expectError: false,
 		},
+		{
+			name: "retry wait min exceeds max",
+			arg: map[string]any{
+				"retry_wait_min": "2m",
+				"retry_wait_max": "1m",
+			},
+			want:        nil,
+			expectError: true,
+		},
+		{
+			name: "unsupported proxy url",
+			arg: map[string]any{
+				"proxy_url": "ftp://proxy.example.com",
+			},
+			want:        nil,
+			expectError: true,
+		},
+		{
+			name: "client certificate without key",
+			arg: map[string]any{
+				"client_cert_file": "client.pem",
+			},
+			want:        nil,
+			expectError: true,
+		},
 		{
 			name: "bad ids url",
 			arg: map[string]any{
@@ -129,3 +179,147 @@ func TestExpandProviderConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestExpandProviderConfig_Fallbacks(t *testing.T) {
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	credentials := `
+# shared credentials
+[default]
+api_token = default-token
+
+[ci]
+api_token = "profile-token"
+ids_client_id = profile-client-id
+partner_id = 42
+`
+	if err := os.WriteFile(credentialsFile, []byte(credentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("EDGECAST_ACCOUNT_NUMBER", "ENV1")
+	t.Setenv("EDGECAST_IDS_CLIENT_ID", "env-client-id")
+	t.Setenv("EDGECAST_CREDENTIALS_FILE", credentialsFile)
+	t.Setenv("EDGECAST_PROFILE", "ci")
+	t.Setenv("EDGECAST_READ_ONLY", "true")
+
+	data := schema.TestResourceDataRaw(
+		t,
+		edgecast.GetProviderSchema(),
+		map[string]any{
+			"ids_client_id": "hcl-client-id",
+		})
+
+	got, err := internal.ExpandProviderConfig(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	apiURL, _ := url.Parse(internal.DefaultAPIAddress)
+	apiLegacyURL, _ := url.Parse(internal.DefaultAPIAddressLegacy)
+	idsURL, _ := url.Parse(internal.DefaultIDSAddress)
+	profileSource := fmt.Sprintf("profile %q in %s", "ci", credentialsFile)
+
+	want := &internal.ProviderConfig{
+		APIToken:         "profile-token",
+		AccountNumber:    "ENV1",
+		IdsClientID:      "hcl-client-id",
+		IDSAddress:       internal.DefaultIDSAddress,
+		APIAddress:       internal.DefaultAPIAddress,
+		APIAddressLegacy: internal.DefaultAPIAddressLegacy,
+		IdsURL:           idsURL,
+		APIURL:           apiURL,
+		APIURLLegacy:     apiLegacyURL,
+		PartnerID:        42,
+		MaxRetries:       internal.DefaultMaxRetries,
+		RetryWaitMin:     internal.DefaultRetryWaitMin,
+		RetryWaitMax:     internal.DefaultRetryWaitMax,
+		ReadOnly:         true,
+		Sources: map[string]string{
+			"profile":            "environment variable EDGECAST_PROFILE",
+			"credentials_file":   "environment variable EDGECAST_CREDENTIALS_FILE",
+			"api_token":          profileSource,
+			"account_number":     "environment variable EDGECAST_ACCOUNT_NUMBER",
+			"ids_client_id":      "provider configuration",
+			"ids_address":        "default",
+			"api_address":        "default",
+			"api_address_legacy": "default",
+			"partner_id":         profileSource,
+			"max_retries":        "default",
+			"retry_wait_min":     "default",
+			"retry_wait_max":     "default",
+			"read_only":          "environment variable EDGECAST_READ_ONLY",
+		},
+	}
+
+	if diffs := deep.Equal(got, want); len(diffs) > 0 {
+		t.Errorf("Differences: %v", diffs)
+	}
+}
+
+func TestExpandProviderConfig_FallbackErrors(t *testing.T) {
+	credentialsFile := filepath.Join(t.TempDir(), "credentials")
+	credentials := "[ci]\napi_token\n"
+	if err := os.WriteFile(credentialsFile, []byte(credentials), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		env  map[string]string
+	}{
+		{
+			name: "non-numeric partner id",
+			env:  map[string]string{"EDGECAST_PARTNER_ID": "abc"},
+		},
+		{
+			name: "invalid retry wait",
+			env:  map[string]string{"EDGECAST_RETRY_WAIT_MAX": "soon"},
+		},
+		{
+			name: "non-boolean read only",
+			env:  map[string]string{"EDGECAST_READ_ONLY": "maybe"},
+		},
+		{
+			name: "negative max retries",
+			env:  map[string]string{"EDGECAST_MAX_RETRIES": "-1"},
+		},
+		{
+			name: "missing profile",
+			env: map[string]string{
+				"EDGECAST_PROFILE":          "production",
+				"EDGECAST_CREDENTIALS_FILE": credentialsFile,
+			},
+		},
+		{
+			name: "missing credentials file",
+			env: map[string]string{
+				"EDGECAST_PROFILE":          "ci",
+				"EDGECAST_CREDENTIALS_FILE": credentialsFile + ".missing",
+			},
+		},
+		{
+			name: "malformed credentials file",
+			env: map[string]string{
+				"EDGECAST_PROFILE":          "ci",
+				"EDGECAST_CREDENTIALS_FILE": credentialsFile,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			data := schema.TestResourceDataRaw(
+				t,
+				edgecast.GetProviderSchema(),
+				map[string]any{})
+
+			if _, err := internal.ExpandProviderConfig(data); err == nil {
+				t.Fatal("expected error, but got none")
+			}
+		})
+	}
+}
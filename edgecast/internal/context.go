@@ -0,0 +1,311 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// DefaultTimeout is the default time allowed for each CRUD operation of a
+// resource. Operations may make several API calls, each of which may be
+// retried.
+const DefaultTimeout = 20 * time.Minute
+
+// DefaultResourceTimeouts returns the timeouts of a resource whose operations
+// use the default timeout. They may be overridden through the resource's
+// timeouts block.
+func DefaultResourceTimeouts() *schema.ResourceTimeout {
+	return &schema.ResourceTimeout{
+		Create: schema.DefaultTimeout(DefaultTimeout),
+		Read:   schema.DefaultTimeout(DefaultTimeout),
+		Update: schema.DefaultTimeout(DefaultTimeout),
+		Delete: schema.DefaultTimeout(DefaultTimeout),
+	}
+}
+
+// BindContext wraps the CRUD and import functions of a resource or data source
+// so that the API calls they make through GetService are bound to the
+// operation's context. The SDK does not accept a context, so without this a
+// canceled run or an expired timeout would not stop calls in flight or their
+// retries.
+func BindContext(resource *schema.Resource) *schema.Resource {
+	type crudFunc = func(
+		context.Context,
+		*schema.ResourceData,
+		interface{},
+	) diag.Diagnostics
+
+	wrap := func(f crudFunc) crudFunc {
+		if f == nil {
+			return nil
+		}
+
+		return func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) diag.Diagnostics {
+			return f(ctx, d, withScope(ctx, m))
+		}
+	}
+
+	resource.CreateContext = wrap(resource.CreateContext)
+	resource.ReadContext = wrap(resource.ReadContext)
+	resource.UpdateContext = wrap(resource.UpdateContext)
+	resource.DeleteContext = wrap(resource.DeleteContext)
+
+	if resource.Importer != nil && resource.Importer.StateContext != nil {
+		importState := resource.Importer.StateContext
+		resource.Importer.StateContext = func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) ([]*schema.ResourceData, error) {
+			return importState(ctx, d, withScope(ctx, m))
+		}
+	}
+
+	return resource
+}
+
+// withScope returns a copy of the provider configuration that carries a new
+// scope for the operation. Services returned by GetService for the copy are
+// built for the operation, and send their API calls with its context, see
+// serviceScope.bindClient.
+func withScope(ctx context.Context, m interface{}) interface{} {
+	config, ok := m.(ProviderConfig)
+	if !ok {
+		return m
+	}
+
+	config.scope = &serviceScope{ctx: ctx}
+
+	return config
+}
+
+// serviceScope holds the context of a single CRUD operation, along with the
+// SDK services built for it and the error responses received by the API
+// calls made for it.
+type serviceScope struct {
+	ctx context.Context
+
+	servicesMu sync.Mutex
+	services   map[string]any
+
+	failuresMu sync.Mutex
+	failures   []failedResponse
+}
+
+// service returns the service registered under name for the operation,
+// building it with build on first use.
+func (s *serviceScope) service(
+	name string,
+	build func() (any, error),
+) (any, error) {
+	s.servicesMu.Lock()
+	defer s.servicesMu.Unlock()
+
+	if service, ok := s.services[name]; ok {
+		return service, nil
+	}
+
+	service, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	if s.services == nil {
+		s.services = make(map[string]any)
+	}
+
+	s.services[name] = service
+
+	return service, nil
+}
+
+// maxFailedResponses limits the failed responses kept per operation.
+const maxFailedResponses = 20
+
+// requestIDHeaders are the response headers that may identify a request to
+// Edgecast support.
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Correlation-Id",
+	"Request-Id",
+}
+
+// failedResponse records an error response received during an operation. The
+// SDK drops response headers from its errors, so the request ID is taken
+// from here.
+type failedResponse struct {
+	statusCode int
+	requestID  string
+}
+
+// recordFailure records an error response.
+func (s *serviceScope) recordFailure(resp *http.Response) {
+	f := failedResponse{
+		statusCode: resp.StatusCode,
+		requestID:  responseRequestID(resp),
+	}
+
+	s.failuresMu.Lock()
+	defer s.failuresMu.Unlock()
+
+	s.failures = append(s.failures, f)
+	if len(s.failures) > maxFailedResponses {
+		s.failures = s.failures[1:]
+	}
+}
+
+// responseRequestID returns the request ID of a response, if any.
+func responseRequestID(resp *http.Response) string {
+	for _, h := range requestIDHeaders {
+		if v := resp.Header.Get(h); len(v) > 0 {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// logger returns the logger of the operation's subsystem, if the operation
+// was wrapped by WithLogging.
+func (s *serviceScope) logger() (Logger, bool) {
+	subsystem, ok := logSubsystem(s.ctx)
+	return Logger(subsystem), ok
+}
+
+// requestID returns the request ID of the most recent error response with
+// the given status code, if any.
+func (s *serviceScope) requestID(statusCode int) string {
+	s.failuresMu.Lock()
+	defer s.failuresMu.Unlock()
+
+	for i := len(s.failures) - 1; i >= 0; i-- {
+		if s.failures[i].statusCode == statusCode {
+			return s.failures[i].requestID
+		}
+	}
+
+	return ""
+}
+
+// bindClient binds an HTTP client configured by configureClient to the
+// operation, so that it sends its requests with the operation's context and
+// records their error responses in the scope. The client must not be shared
+// with other operations.
+func (s *serviceScope) bindClient(c *retryablehttp.Client) {
+	c.HTTPClient.Transport = s.transport(c.HTTPClient.Transport)
+
+	// The client passes the context of the request to CheckRetry, which the
+	// SDK never sets.
+	checkRetry := c.CheckRetry
+	c.CheckRetry = func(
+		_ context.Context,
+		resp *http.Response,
+		err error,
+	) (bool, error) {
+		return checkRetry(s.ctx, resp, err)
+	}
+
+	c.Backoff = s.backoff(c.Backoff)
+}
+
+// transport returns a transport that sends requests through base with the
+// context of the operation.
+func (s *serviceScope) transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &scopedTransport{base: base, scope: s}
+}
+
+// backoff returns a backoff that waits for the duration given by base,
+// unless the context of the operation is done first, and returns 0 so that
+// the HTTP client retries immediately. The client waits on the request's own
+// context, which the SDK never sets, so the wait is done here to let
+// cancellation interrupt it. The retried request then fails at once with the
+// context's error.
+func (s *serviceScope) backoff(base retryablehttp.Backoff) retryablehttp.Backoff {
+	return func(
+		min time.Duration,
+		max time.Duration,
+		attemptNum int,
+		resp *http.Response,
+	) time.Duration {
+		timer := time.NewTimer(base(min, max, attemptNum, resp))
+		defer timer.Stop()
+
+		select {
+		case <-s.ctx.Done():
+		case <-timer.C:
+		}
+
+		return 0
+	}
+}
+
+// scopedTransport sends each request with the context of an operation, which
+// the SDK does not set on the requests it builds, and records error responses
+// in the operation's scope.
+type scopedTransport struct {
+	base  http.RoundTripper
+	scope *serviceScope
+}
+
+func (t *scopedTransport) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	scope := t.scope
+
+	if err := scope.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	logger, logging := scope.logger()
+	if logging {
+		logger.Debug(scope.ctx, "Sending API request", map[string]any{
+			LogFieldHTTPMethod: req.Method,
+			LogFieldHTTPURL:    req.URL.Redacted(),
+		})
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req.WithContext(scope.ctx))
+
+	if logging {
+		fields := map[string]any{
+			LogFieldHTTPMethod:   req.Method,
+			LogFieldHTTPURL:      req.URL.Redacted(),
+			LogFieldHTTPDuration: time.Since(start).Milliseconds(),
+		}
+
+		if resp != nil {
+			fields[LogFieldHTTPStatus] = resp.StatusCode
+			if id := responseRequestID(resp); len(id) > 0 {
+				fields[LogFieldRequestID] = id
+			}
+		}
+
+		if err != nil {
+			logger.Warn(scope.ctx, "API request failed: "+err.Error(), fields)
+		} else {
+			logger.Debug(scope.ctx, "Received API response", fields)
+		}
+	}
+
+	if resp != nil && resp.StatusCode >= http.StatusBadRequest {
+		scope.recordFailure(resp)
+	}
+
+	return resp, err
+}
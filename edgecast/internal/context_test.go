@@ -0,0 +1,306 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/rulesengine"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/scopes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newBoundResource creates a resource wrapped by BindContext whose Read
+// function calls read.
+func newBoundResource(
+	read func(config internal.ProviderConfig) error,
+) *schema.Resource {
+	return internal.BindContext(&schema.Resource{
+		ReadContext: func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) diag.Diagnostics {
+			return diag.FromErr(read(m.(internal.ProviderConfig)))
+		},
+		Schema: map[string]*schema.Schema{},
+	})
+}
+
+func TestBindContext_CancelsRequests(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+	}{
+		{
+			name: "request in flight",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				<-r.Context().Done()
+			},
+		},
+		{
+			name: "waiting to retry",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Retry-After", "30")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(tt.handler)
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			config := internal.ProviderConfig{
+				APIToken:     "token",
+				APIURLLegacy: serverURL,
+				MaxRetries:   5,
+				RetryWaitMax: time.Minute,
+				Services:     internal.NewServiceRegistry(),
+			}
+
+			resource := newBoundResource(func(c internal.ProviderConfig) error {
+				svc, err := internal.GetService(c, "waf", waf.New)
+				if err != nil {
+					return err
+				}
+
+				_, err = svc.Scopes.GetAllScopes(
+					scopes.GetAllScopesParams{AccountNumber: "A1"})
+				return err
+			})
+
+			ctx, cancel := context.WithTimeout(
+				context.Background(),
+				100*time.Millisecond)
+			defer cancel()
+
+			d := resource.TestResourceData()
+
+			start := time.Now()
+			diags := resource.ReadContext(ctx, d, config)
+			elapsed := time.Since(start)
+
+			if !diags.HasError() {
+				t.Fatal("expected error, but got none")
+			}
+
+			if elapsed > 5*time.Second {
+				t.Errorf("call took %s after its context expired", elapsed)
+			}
+		})
+	}
+}
+
+func TestBindContext_ConcurrentOperations(t *testing.T) {
+	t.Parallel()
+
+	// Requests for account "slow" only end when their context is done.
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if strings.Contains(r.URL.Path, "slow") {
+				<-r.Context().Done()
+				return
+			}
+
+			jsonHandler(`{}`)(w, r)
+		}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	config := internal.ProviderConfig{
+		APIToken:     "token",
+		APIURLLegacy: serverURL,
+		Services:     internal.NewServiceRegistry(),
+	}
+
+	var builds int32
+	newService := func(c edgecast.SDKConfig) (*waf.WafService, error) {
+		atomic.AddInt32(&builds, 1)
+		return waf.New(c)
+	}
+
+	read := func(ctx context.Context, account string) diag.Diagnostics {
+		resource := newBoundResource(func(c internal.ProviderConfig) error {
+			svc, err := internal.GetService(c, "waf", newService)
+			if err != nil {
+				return err
+			}
+
+			again, err := internal.GetService(c, "waf", newService)
+			if err != nil {
+				return err
+			}
+
+			if again != svc {
+				return errors.New("service not reused within the operation")
+			}
+
+			_, err = svc.Scopes.GetAllScopes(
+				scopes.GetAllScopesParams{AccountNumber: account})
+			return err
+		})
+
+		return resource.ReadContext(ctx, resource.TestResourceData(), config)
+	}
+
+	// An operation whose context expires does not affect another one running
+	// at the same time, since each builds a service of its own.
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		100*time.Millisecond)
+	defer cancel()
+
+	var slow, fast diag.Diagnostics
+	wg := sync.WaitGroup{}
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		slow = read(ctx, "slow")
+	}()
+	go func() {
+		defer wg.Done()
+		fast = read(context.Background(), "fast")
+	}()
+	wg.Wait()
+
+	if !slow.HasError() {
+		t.Error("expected the expired operation to fail")
+	}
+
+	if fast.HasError() {
+		t.Errorf("unexpected error: %v", fast)
+	}
+
+	if got := atomic.LoadInt32(&builds); got != 2 {
+		t.Errorf("expected 2 services to be built, built %d", got)
+	}
+}
+
+func TestBindContext_Goroutines(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	config := internal.ProviderConfig{
+		APIToken:     "token",
+		APIURLLegacy: serverURL,
+		Services:     internal.NewServiceRegistry(),
+	}
+
+	// API calls made from a goroutine started by the operation are bound to
+	// the operation, since its service carries the operation's context.
+	resource := newBoundResource(func(c internal.ProviderConfig) error {
+		svc, err := internal.GetService(c, "waf", waf.New)
+		if err != nil {
+			return err
+		}
+
+		errs := make(chan error, 1)
+		go func() {
+			_, err := svc.Scopes.GetAllScopes(
+				scopes.GetAllScopesParams{AccountNumber: "A1"})
+			errs <- err
+		}()
+
+		return <-errs
+	})
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	diags := resource.ReadContext(ctx, resource.TestResourceData(), config)
+	elapsed := time.Since(start)
+
+	if !diags.HasError() {
+		t.Fatal("expected error, but got none")
+	}
+
+	if elapsed > 5*time.Second {
+		t.Errorf("call took %s after its context expired", elapsed)
+	}
+}
+
+func TestBindContext_IDSToken(t *testing.T) {
+	t.Parallel()
+
+	ids := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// The server only notices that the client has gone once the
+			// request body has been read.
+			io.Copy(io.Discard, r.Body)
+			<-r.Context().Done()
+		}))
+	defer ids.Close()
+
+	idsURL, _ := url.Parse(ids.URL)
+	config := internal.ProviderConfig{
+		IdsClientID:     "id",
+		IdsClientSecret: "secret",
+		IdsScope:        "ec.rules",
+		IdsURL:          idsURL,
+		APIURL:          idsURL,
+		Services:        internal.NewServiceRegistry(),
+	}
+
+	// The token request made for the operation's first API call is canceled
+	// with the operation.
+	resource := newBoundResource(func(c internal.ProviderConfig) error {
+		svc, err := internal.GetService(c, "rulesengine", rulesengine.New)
+		if err != nil {
+			return err
+		}
+
+		params := rulesengine.NewGetPolicyParams()
+		params.PolicyID = 1
+		_, err = svc.GetPolicy(*params)
+		return err
+	})
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	diags := resource.ReadContext(ctx, resource.TestResourceData(), config)
+	elapsed := time.Since(start)
+
+	if !diags.HasError() {
+		t.Fatal("expected error, but got none")
+	}
+
+	if elapsed > 5*time.Second {
+		t.Errorf("token request took %s after its context expired", elapsed)
+	}
+}
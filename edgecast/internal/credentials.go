@@ -0,0 +1,97 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultCredentialsFile returns the location of the shared credentials file
+// i.e. ~/.edgecast/credentials.
+func DefaultCredentialsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to locate credentials file: %w", err)
+	}
+
+	return filepath.Join(home, ".edgecast", "credentials"), nil
+}
+
+// ReadCredentialsProfile reads the named section from a credentials file.
+// The file uses an INI-like format where each section holds provider
+// settings e.g.
+//
+//	[production]
+//	api_token = 12345467890abcdefghijklmnopqrst
+//	account_number = A1234
+//
+// Blank lines and lines starting with '#' or ';' are ignored.
+func ReadCredentialsProfile(
+	path string,
+	profile string,
+) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open credentials file: %w", err)
+	}
+	defer f.Close()
+
+	var (
+		section string
+		found   bool
+		values  = make(map[string]string)
+		scanner = bufio.NewScanner(f)
+		lineNum = 0
+	)
+
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+
+		if len(line) == 0 ||
+			strings.HasPrefix(line, "#") ||
+			strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			found = found || section == profile
+			continue
+		}
+
+		if section != profile {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			// The line itself is not included as it may contain a secret.
+			return nil, fmt.Errorf(
+				"credentials file %s: line %d is not a key = value pair",
+				path,
+				lineNum)
+		}
+
+		values[strings.TrimSpace(key)] = strings.Trim(
+			strings.TrimSpace(value),
+			`"`)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	if !found {
+		return nil, fmt.Errorf(
+			"profile %q not found in credentials file %s",
+			profile,
+			path)
+	}
+
+	return values, nil
+}
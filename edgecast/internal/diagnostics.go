@@ -0,0 +1,377 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"terraform-provider-edgecast/edgecast/helper"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ExplainErrors wraps the CRUD functions of a resource or data source so that
+// diagnostics caused by failed API calls report the HTTP status, the request
+// ID and the API's message, along with a hint for common mistakes. Each
+// validation message that the API returns for a field becomes a separate
+// diagnostic attached to the matching attribute. Resources must be wrapped by
+// ExplainErrors before BindContext so that request IDs can be found.
+func ExplainErrors(resource *schema.Resource) *schema.Resource {
+	type crudFunc = func(
+		context.Context,
+		*schema.ResourceData,
+		interface{},
+	) diag.Diagnostics
+
+	wrap := func(f crudFunc, operation string) crudFunc {
+		if f == nil {
+			return nil
+		}
+
+		return func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) diag.Diagnostics {
+			diags := f(ctx, d, m)
+			if !diags.HasError() {
+				return diags
+			}
+
+			var scope *serviceScope
+			if config, ok := m.(ProviderConfig); ok {
+				scope = config.scope
+			}
+
+			return explainDiagnostics(resource.Schema, scope, operation, diags)
+		}
+	}
+
+	resource.CreateContext = wrap(resource.CreateContext, "create")
+	resource.ReadContext = wrap(resource.ReadContext, "read")
+	resource.UpdateContext = wrap(resource.UpdateContext, "update")
+	resource.DeleteContext = wrap(resource.DeleteContext, "delete")
+
+	return resource
+}
+
+// explainDiagnostics replaces error diagnostics caused by failed API calls
+// with structured ones, leaving all others unchanged.
+func explainDiagnostics(
+	schemaMap map[string]*schema.Schema,
+	scope *serviceScope,
+	operation string,
+	diags diag.Diagnostics,
+) diag.Diagnostics {
+	explained := make(diag.Diagnostics, 0, len(diags))
+
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			explained = append(explained, d)
+			continue
+		}
+
+		apiErr, summary, ok := parseDiagnostic(d)
+		if !ok {
+			if hint := authHint(d.Summary + " " + d.Detail); len(hint) > 0 {
+				d.Detail = joinParagraphs(d.Detail, hint)
+			}
+
+			explained = append(explained, d)
+			continue
+		}
+
+		if len(apiErr.RequestID) == 0 && scope != nil {
+			apiErr.RequestID = scope.requestID(apiErr.StatusCode)
+		}
+
+		explained = append(
+			explained,
+			apiErrorDiagnostics(schemaMap, apiErr, summary, operation)...)
+	}
+
+	return explained
+}
+
+// parseDiagnostic extracts the API error from a diagnostic's summary or
+// detail, along with the summary to use for it.
+func parseDiagnostic(d diag.Diagnostic) (*helper.APIError, string, bool) {
+	if apiErr, ok := helper.ParseAPIErrorMessage(d.Detail); ok {
+		return apiErr, d.Summary, true
+	}
+
+	if apiErr, ok := helper.ParseAPIErrorMessage(d.Summary); ok {
+		return apiErr, apiErr.Operation, true
+	}
+
+	return nil, "", false
+}
+
+// apiErrorDiagnostics creates the diagnostics for an API error: one for the
+// error as a whole, and one per field error.
+func apiErrorDiagnostics(
+	schemaMap map[string]*schema.Schema,
+	apiErr *helper.APIError,
+	summary string,
+	operation string,
+) diag.Diagnostics {
+	status := fmt.Sprintf(
+		"HTTP %d %s",
+		apiErr.StatusCode,
+		http.StatusText(apiErr.StatusCode))
+
+	if len(summary) == 0 {
+		summary = "API request failed"
+	}
+
+	var requestID string
+	if len(apiErr.RequestID) > 0 {
+		requestID = fmt.Sprintf(
+			"Request ID: %s. Include it when contacting support.",
+			apiErr.RequestID)
+	}
+
+	diags := diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("%s: %s", summary, status),
+			Detail: joinParagraphs(
+				apiErr.Message(),
+				requestID,
+				hint(apiErr, operation)),
+		},
+	}
+
+	for _, f := range apiErr.Fields {
+		path := attributePath(schemaMap, f.Field)
+
+		name := f.Field
+		if len(path) > 0 {
+			name = pathString(path)
+		}
+
+		diags = append(diags, diag.Diagnostic{
+			Severity:      diag.Error,
+			Summary:       fmt.Sprintf("Invalid %s", name),
+			Detail:        joinParagraphs(f.Message, requestID),
+			AttributePath: path,
+		})
+	}
+
+	return diags
+}
+
+// hint suggests how to fix common causes of an API error.
+func hint(apiErr *helper.APIError, operation string) string {
+	if h := authHint(apiErr.Body); len(h) > 0 {
+		return h
+	}
+
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized:
+		return "Check that the provider's credentials are valid: api_token " +
+			"for resources managed through a REST API token, or " +
+			"ids_client_id, ids_client_secret and ids_scope for resources " +
+			"managed through REST API (OAuth 2.0) client credentials."
+	case http.StatusForbidden:
+		return "The credentials are not authorized for this request. Check " +
+			"that account_number identifies an account they may manage, " +
+			"and that ids_scope includes the scope the resource requires, " +
+			"e.g. ec.rules for Rules Engine policies or " +
+			"sec.cps.certificates for CPS certificates."
+	case http.StatusNotFound:
+		if operation != "read" {
+			return "Check that account_number is correct and that the " +
+				"object has not been deleted outside of Terraform."
+		}
+	case http.StatusTooManyRequests:
+		return "The API is still throttling requests after all retries. " +
+			"Consider lowering max_requests_per_second or " +
+			"max_concurrent_requests."
+	}
+
+	return ""
+}
+
+// authHint suggests how to fix a failure to get an IDS token.
+func authHint(text string) string {
+	lower := strings.ToLower(text)
+
+	switch {
+	case strings.Contains(lower, "invalid_scope"):
+		return "The IDS client has not been granted the requested scope. " +
+			"Check that ids_scope lists the scope the resource requires, " +
+			"e.g. ec.rules for Rules Engine policies or " +
+			"sec.cps.certificates for CPS certificates."
+	case strings.Contains(lower, "invalid_client"),
+		strings.Contains(lower, "client id, secret, and scope required"):
+		return "Check that ids_client_id, ids_client_secret and ids_scope " +
+			"are set and valid."
+	}
+
+	return ""
+}
+
+// attributePath finds the attribute that a field named by the API refers to.
+// Field names may be in PascalCase or camelCase, be prefixed with the name
+// of the request e.g. "request.DirectoryName", or be JSON pointers e.g.
+// "/domains/0/name". An empty path is returned if no attribute matches.
+func attributePath(schemaMap map[string]*schema.Schema, field string) cty.Path {
+	segments := fieldSegments(field)
+
+	// Skip a leading segment that names the request rather than a field.
+	if len(segments) > 1 {
+		if _, ok := attributeName(schemaMap, segments[0]); !ok {
+			segments = segments[1:]
+		}
+	}
+
+	path := cty.Path{}
+	current := schemaMap
+
+	for i := 0; i < len(segments); i++ {
+		name, ok := attributeName(current, segments[i])
+		if !ok {
+			break
+		}
+
+		s := current[name]
+
+		path = path.GetAttr(name)
+
+		elem, ok := s.Elem.(*schema.Resource)
+		if !ok {
+			break
+		}
+
+		// Lists may be indexed. Blocks with a single element are usually
+		// sent to the API as an object, so the index is implied.
+		if i+1 < len(segments) {
+			if index, err := strconv.Atoi(segments[i+1]); err == nil {
+				if s.Type == schema.TypeList {
+					path = path.IndexInt(index)
+				}
+				i++
+			} else if s.Type == schema.TypeList && s.MaxItems == 1 {
+				path = path.IndexInt(0)
+			} else {
+				break
+			}
+		}
+
+		current = elem.Schema
+	}
+
+	if len(path) == 0 {
+		return nil
+	}
+
+	return path
+}
+
+// attributeName returns the attribute in schemaMap that a field named by the
+// API refers to. Blocks are often named in the singular e.g. origin for a
+// list of Origins.
+func attributeName(
+	schemaMap map[string]*schema.Schema,
+	field string,
+) (string, bool) {
+	name := toSnakeCase(field)
+	if _, ok := schemaMap[name]; ok {
+		return name, true
+	}
+
+	singular := strings.TrimSuffix(name, "s")
+	if s, ok := schemaMap[singular]; ok {
+		if _, isBlock := s.Elem.(*schema.Resource); isBlock {
+			return singular, true
+		}
+	}
+
+	return "", false
+}
+
+// fieldSegments splits a field name into its parts e.g. "a.b[0].c" and
+// "/a/b/0/c" into "a", "b", "0", "c".
+func fieldSegments(field string) []string {
+	field = strings.TrimPrefix(field, "$")
+
+	return strings.FieldsFunc(field, func(r rune) bool {
+		return r == '.' || r == '/' || r == '[' || r == ']'
+	})
+}
+
+// toSnakeCase converts a field name to the naming used by attributes e.g.
+// DirectoryName and directoryName to directory_name, and HTTPHostnames to
+// http_hostnames.
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				prev := runes[i-1]
+				nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+
+				if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+					(unicode.IsUpper(prev) && nextIsLower) {
+					b.WriteRune('_')
+				}
+			}
+
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+
+		if r == '-' {
+			r = '_'
+		}
+
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// pathString formats a path the way it is written in configuration e.g.
+// origin[0].name.
+func pathString(path cty.Path) string {
+	var b strings.Builder
+
+	for _, step := range path {
+		switch s := step.(type) {
+		case cty.GetAttrStep:
+			if b.Len() > 0 {
+				b.WriteRune('.')
+			}
+			b.WriteString(s.Name)
+		case cty.IndexStep:
+			if s.Key.Type() == cty.Number {
+				i, _ := s.Key.AsBigFloat().Int64()
+				fmt.Fprintf(&b, "[%d]", i)
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// joinParagraphs joins the non-empty paragraphs with blank lines.
+func joinParagraphs(paragraphs ...string) string {
+	nonEmpty := make([]string, 0, len(paragraphs))
+	for _, p := range paragraphs {
+		if p = strings.TrimSpace(p); len(p) > 0 {
+			nonEmpty = append(nonEmpty, p)
+		}
+	}
+
+	return strings.Join(nonEmpty, "\n\n")
+}
@@ -0,0 +1,217 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/helper"
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/scopes"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// newExplainedResource creates a resource wrapped by ExplainErrors and
+// BindContext whose Create function calls the WAF API.
+func newExplainedResource() *schema.Resource {
+	resource := &schema.Resource{
+		CreateContext: func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) diag.Diagnostics {
+			svc, err := internal.GetService(
+				m.(internal.ProviderConfig),
+				"waf",
+				waf.New)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+
+			_, err = svc.Scopes.GetAllScopes(
+				scopes.GetAllScopesParams{AccountNumber: "A1"})
+			if err != nil {
+				return helper.CreationError(d, err)
+			}
+
+			return nil
+		},
+		Schema: map[string]*schema.Schema{
+			"account_number": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"directory_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"origin": {
+				Type:     schema.TypeList,
+				Optional: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host_header": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	return internal.BindContext(internal.ExplainErrors(resource))
+}
+
+func TestExplainErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		status    int
+		body      string
+		wantDiags []diag.Diagnostic
+	}{
+		{
+			name:   "field errors",
+			status: http.StatusBadRequest,
+			body: `{"Message":"The request is invalid.",` +
+				`"ModelState":{"request.DirectoryName":["Required."],` +
+				`"Origins[1].HostHeader":["Too long."],` +
+				`"Unknown":["Not an attribute."]}}`,
+			wantDiags: []diag.Diagnostic{
+				{
+					Summary: "error getting scopes: SubmitRequest: HTTP 400 Bad Request",
+					Detail: "The request is invalid.\n\n" +
+						"Request ID: req-123. Include it when contacting support.",
+				},
+				{
+					Summary: "Invalid origin[1].host_header",
+					Detail: "Too long.\n\n" +
+						"Request ID: req-123. Include it when contacting support.",
+					AttributePath: cty.GetAttrPath("origin").
+						IndexInt(1).
+						GetAttr("host_header"),
+				},
+				{
+					Summary: "Invalid Unknown",
+					Detail: "Not an attribute.\n\n" +
+						"Request ID: req-123. Include it when contacting support.",
+				},
+				{
+					Summary: "Invalid directory_name",
+					Detail: "Required.\n\n" +
+						"Request ID: req-123. Include it when contacting support.",
+					AttributePath: cty.GetAttrPath("directory_name"),
+				},
+			},
+		},
+		{
+			name:   "wrong account",
+			status: http.StatusForbidden,
+			body:   `{"Message":"Access denied."}`,
+			wantDiags: []diag.Diagnostic{
+				{
+					Summary: "error getting scopes: SubmitRequest: HTTP 403 Forbidden",
+					Detail: "Access denied.\n\n" +
+						"Request ID: req-123. Include it when contacting support.\n\n" +
+						"The credentials are not authorized for this request. " +
+						"Check that account_number identifies an account they " +
+						"may manage, and that ids_scope includes the scope the " +
+						"resource requires, e.g. ec.rules for Rules Engine " +
+						"policies or sec.cps.certificates for CPS certificates.",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					w.Header().Set("X-Request-Id", "req-123")
+					w.Header().Set("Content-Type", "application/json")
+					w.WriteHeader(tt.status)
+					w.Write([]byte(tt.body))
+				}))
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			config := internal.ProviderConfig{
+				APIToken:     "token",
+				APIURLLegacy: serverURL,
+				Services:     internal.NewServiceRegistry(),
+			}
+
+			resource := newExplainedResource()
+			diags := resource.CreateContext(
+				context.Background(),
+				resource.TestResourceData(),
+				config)
+
+			if len(diags) != len(tt.wantDiags) {
+				t.Fatalf("got %d diagnostics, want %d: %+v",
+					len(diags), len(tt.wantDiags), diags)
+			}
+
+			for i, want := range tt.wantDiags {
+				got := diags[i]
+				if got.Severity != diag.Error {
+					t.Errorf("diagnostic %d: got severity %v", i, got.Severity)
+				}
+
+				if got.Summary != want.Summary {
+					t.Errorf("diagnostic %d: got summary %q, want %q",
+						i, got.Summary, want.Summary)
+				}
+
+				if got.Detail != want.Detail {
+					t.Errorf("diagnostic %d: got detail %q, want %q",
+						i, got.Detail, want.Detail)
+				}
+
+				if !got.AttributePath.Equals(want.AttributePath) {
+					t.Errorf("diagnostic %d: got path %#v, want %#v",
+						i, got.AttributePath, want.AttributePath)
+				}
+			}
+		})
+	}
+}
+
+func TestExplainErrors_IDSScope(t *testing.T) {
+	t.Parallel()
+
+	resource := internal.ExplainErrors(&schema.Resource{
+		ReadContext: func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) diag.Diagnostics {
+			return diag.Errorf(
+				"authentication error: bad request: invalid_scope")
+		},
+		Schema: map[string]*schema.Schema{},
+	})
+
+	diags := resource.ReadContext(
+		context.Background(),
+		resource.TestResourceData(),
+		internal.ProviderConfig{})
+
+	if len(diags) != 1 || !strings.Contains(diags[0].Detail, "ids_scope") {
+		t.Errorf("expected a hint about ids_scope, got %+v", diags)
+	}
+}
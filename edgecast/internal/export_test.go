@@ -0,0 +1,25 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// FindSDKClient returns the HTTP client of an SDK service, and whether it
+// authenticates with IDS, for tests of the SDK adapter, which
+// configureService depends on.
+func FindSDKClient(service any) (*retryablehttp.Client, bool, error) {
+	s, ok, err := findSDKService(service)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if !ok {
+		return nil, false, fmt.Errorf("%T is not an SDK service", service)
+	}
+
+	return s.client, s.idsProvider.IsValid(), nil
+}
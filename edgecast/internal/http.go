@@ -0,0 +1,296 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+const (
+	// DefaultMaxRetries is the default number of times a failed API call is
+	// retried.
+	DefaultMaxRetries = 5
+
+	// DefaultRetryWaitMin is the default minimum wait between retries.
+	DefaultRetryWaitMin = 1 * time.Second
+
+	// DefaultRetryWaitMax is the default maximum wait between retries.
+	DefaultRetryWaitMax = 60 * time.Second
+)
+
+// safeRetryRequests lists non-idempotent requests that are known to be safe to
+// retry, keyed by HTTP method and matched against the request path.
+var safeRetryRequests = map[string][]string{
+	// WAF scopes are replaced as a whole, so repeating the call has the same
+	// effect as making it once.
+	http.MethodPost: {"waf/v1.0/scopes"},
+}
+
+// configureService applies the provider-wide HTTP settings to the HTTP
+// client of an SDK service, and has it send its requests through a
+// transport built by the provider, see tokenTransport. Within an operation,
+// its requests are bound to the operation's context. An error is returned
+// if the service cannot be configured, see findSDKService.
+func configureService(service any, config ProviderConfig) error {
+	sdk, ok, err := findSDKService(service)
+	if err != nil || !ok {
+		return err
+	}
+
+	if err := sdk.disableIDSRequests(); err != nil {
+		return err
+	}
+
+	checkRetry := CheckRetry
+	if sdk.customRetry {
+		checkRetry = withSDKRetry(sdk.client.CheckRetry)
+	}
+
+	config.configureClient(sdk.client, checkRetry)
+
+	return nil
+}
+
+// NewRetryableClient creates an HTTP client for API calls that the SDK does
+// not provide. It has the same settings as the clients of SDK services, and
+// within an operation its requests are bound to the operation's context.
+// Requests must carry their own Authorization header, see
+// AuthorizationProvider.
+func (c ProviderConfig) NewRetryableClient() *retryablehttp.Client {
+	client := retryablehttp.NewClient()
+	client.Logger = nil
+
+	c.configureClient(client, CheckRetry)
+
+	return client
+}
+
+// configureClient applies the provider-wide HTTP settings to client, which
+// decides whether to retry with checkRetry.
+func (c ProviderConfig) configureClient(
+	client *retryablehttp.Client,
+	checkRetry retryablehttp.CheckRetry,
+) {
+	waitMin := c.RetryWaitMin
+	if waitMin <= 0 {
+		waitMin = DefaultRetryWaitMin
+	}
+
+	waitMax := c.RetryWaitMax
+	if waitMax <= 0 {
+		waitMax = DefaultRetryWaitMax
+	}
+
+	client.RetryMax = c.MaxRetries
+	client.RetryWaitMin = waitMin
+	client.RetryWaitMax = waitMax
+	client.CheckRetry = checkRetry
+	client.Backoff = Backoff
+
+	tokens := c.idsTokens()
+	tokenRequests := tokens.transport
+	if c.scope != nil {
+		tokenRequests = c.scope.transport(tokenRequests)
+	}
+
+	client.HTTPClient = &http.Client{
+		Transport: &tokenTransport{
+			base:          c.apiTransport(),
+			tokens:        tokens,
+			tokenRequests: tokenRequests,
+		},
+	}
+
+	if c.scope != nil {
+		c.scope.bindClient(client)
+	}
+}
+
+// apiTransport returns the transport that API and IDS calls are sent
+// through: config.Transport, or http.DefaultTransport if not set, wrapped by
+// config.TransportHook and config.Limiter.
+func (c ProviderConfig) apiTransport() http.RoundTripper {
+	var transport http.RoundTripper = http.DefaultTransport
+	if c.Transport != nil {
+		transport = c.Transport
+	}
+
+	if c.TransportHook != nil {
+		transport = c.TransportHook(transport)
+	}
+
+	if c.Limiter != nil {
+		transport = c.Limiter.Transport(transport)
+	}
+
+	return transport
+}
+
+// withSDKRetry returns a retry policy that asks sdkRetry, the policy set by
+// an SDK service for its API, before CheckRetry. A retry requested by
+// sdkRetry is only made if the API rejected the request with a client error,
+// e.g. WAF scopes whose rules are still being processed. Otherwise CheckRetry
+// decides, so that requests the API may have processed are not repeated.
+func withSDKRetry(sdkRetry retryablehttp.CheckRetry) retryablehttp.CheckRetry {
+	if sdkRetry == nil {
+		return CheckRetry
+	}
+
+	return func(
+		ctx context.Context,
+		resp *http.Response,
+		err error,
+	) (bool, error) {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		retry, sdkErr := sdkRetry(ctx, resp, err)
+		if sdkErr != nil {
+			return false, sdkErr
+		}
+
+		if retry && err == nil &&
+			resp.StatusCode >= http.StatusBadRequest &&
+			resp.StatusCode < http.StatusInternalServerError {
+			return true, nil
+		}
+
+		return CheckRetry(ctx, resp, err)
+	}
+}
+
+// CheckRetry decides whether a failed API call is retried. Throttled calls
+// are always retried since the API did not process them. Server errors and
+// connection failures are only retried for idempotent methods and for
+// requests listed in safeRetryRequests.
+func CheckRetry(
+	ctx context.Context,
+	resp *http.Response,
+	err error,
+) (bool, error) {
+	// Do not retry on context.Canceled or context.DeadlineExceeded.
+	if ctx.Err() != nil {
+		return false, ctx.Err()
+	}
+
+	if err != nil {
+		// The request was not sent, since no IDS token could be retrieved.
+		var tokenErr *tokenError
+		if errors.As(err, &tokenErr) {
+			return false, nil
+		}
+
+		// A request sent within a canceled operation fails with the
+		// context's error, see BindContext.
+		if errors.Is(err, context.Canceled) ||
+			errors.Is(err, context.DeadlineExceeded) {
+			return false, err
+		}
+
+		// http.Client reports the method of a failed request as the Op of a
+		// url.Error e.g. "Get".
+		var urlErr *url.Error
+		if !errors.As(err, &urlErr) {
+			return false, nil
+		}
+
+		method := strings.ToUpper(urlErr.Op)
+		if !isIdempotent(method) && !isSafeRetry(method, urlErr.URL) {
+			return false, nil
+		}
+
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return true, nil
+	}
+
+	method := resp.Request.Method
+	path := resp.Request.URL.Path
+
+	if isIdempotent(method) || isSafeRetry(method, path) {
+		return retryablehttp.DefaultRetryPolicy(ctx, resp, err)
+	}
+
+	return false, nil
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet,
+		http.MethodHead,
+		http.MethodOptions,
+		http.MethodPut,
+		http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+func isSafeRetry(method string, path string) bool {
+	for _, p := range safeRetryRequests[method] {
+		if strings.Contains(path, p) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Backoff calculates how long to wait before retrying an API call. A
+// Retry-After header, given either in seconds or as an HTTP date, is honoured
+// up to max. Otherwise, the wait grows exponentially from min up to max.
+func Backoff(
+	min time.Duration,
+	max time.Duration,
+	attemptNum int,
+	resp *http.Response,
+) time.Duration {
+	if wait, ok := retryAfter(resp); ok {
+		if wait > max {
+			return max
+		}
+
+		return wait
+	}
+
+	return retryablehttp.DefaultBackoff(min, max, attemptNum, nil)
+}
+
+// retryAfter parses the Retry-After header of a response.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+
+	header := resp.Header.Get("Retry-After")
+	if len(header) == 0 {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+
+		return wait, true
+	}
+
+	return 0, false
+}
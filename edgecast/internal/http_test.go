@@ -0,0 +1,340 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/rate"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/scopes"
+)
+
+func TestServiceRetries(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		call         func(svc *waf.WafService) error
+		wantAttempts int32
+		expectError  bool
+	}{
+		{
+			name:     "GET retried after throttling",
+			statuses: []int{429, 429, 200},
+			call: func(svc *waf.WafService) error {
+				_, err := svc.Scopes.GetAllScopes(
+					scopes.GetAllScopesParams{AccountNumber: "A1"})
+				return err
+			},
+			wantAttempts: 3,
+		},
+		{
+			name:     "GET retried after server error",
+			statuses: []int{503, 200},
+			call: func(svc *waf.WafService) error {
+				_, err := svc.Scopes.GetAllScopes(
+					scopes.GetAllScopesParams{AccountNumber: "A1"})
+				return err
+			},
+			wantAttempts: 2,
+		},
+		{
+			name:     "GET gives up after max retries",
+			statuses: []int{503, 503, 503, 503, 503},
+			call: func(svc *waf.WafService) error {
+				_, err := svc.Scopes.GetAllScopes(
+					scopes.GetAllScopesParams{AccountNumber: "A1"})
+				return err
+			},
+			wantAttempts: 4,
+			expectError:  true,
+		},
+		{
+			name:     "POST not retried after server error",
+			statuses: []int{500, 200},
+			call: func(svc *waf.WafService) error {
+				_, err := svc.Rate.AddRateRule(
+					rate.AddRateRuleParams{AccountNumber: "A1"})
+				return err
+			},
+			wantAttempts: 1,
+			expectError:  true,
+		},
+		{
+			name:     "POST retried after throttling",
+			statuses: []int{429, 200},
+			call: func(svc *waf.WafService) error {
+				_, err := svc.Rate.AddRateRule(
+					rate.AddRateRuleParams{AccountNumber: "A1"})
+				return err
+			},
+			wantAttempts: 2,
+		},
+		{
+			name:     "WAF scopes POST retried after server error",
+			statuses: []int{502, 200},
+			call: func(svc *waf.WafService) error {
+				_, err := svc.Scopes.ModifyAllScopes(
+					scopes.Scopes{CustomerID: "A1"})
+				return err
+			},
+			wantAttempts: 2,
+		},
+		{
+			name:     "WAF scopes POST retried after bad request",
+			statuses: []int{400, 400, 200},
+			call: func(svc *waf.WafService) error {
+				_, err := svc.Scopes.ModifyAllScopes(
+					scopes.Scopes{CustomerID: "A1"})
+				return err
+			},
+			wantAttempts: 3,
+		},
+		{
+			name:     "POST not retried after bad request",
+			statuses: []int{400, 200},
+			call: func(svc *waf.WafService) error {
+				_, err := svc.Rate.AddRateRule(
+					rate.AddRateRuleParams{AccountNumber: "A1"})
+				return err
+			},
+			wantAttempts: 1,
+			expectError:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var attempts int32
+			server := httptest.NewServer(http.HandlerFunc(
+				func(w http.ResponseWriter, r *http.Request) {
+					i := atomic.AddInt32(&attempts, 1) - 1
+					status := tt.statuses[i]
+					if status == http.StatusTooManyRequests {
+						w.Header().Set("Retry-After", "0")
+					}
+
+					if status == http.StatusOK {
+						w.Header().Set("Content-Type", "application/json")
+						w.WriteHeader(status)
+						w.Write([]byte("{}"))
+						return
+					}
+
+					w.WriteHeader(status)
+				}))
+			defer server.Close()
+
+			serverURL, _ := url.Parse(server.URL)
+			config := internal.ProviderConfig{
+				APIToken:     "token",
+				APIURLLegacy: serverURL,
+				MaxRetries:   3,
+				RetryWaitMin: time.Millisecond,
+				RetryWaitMax: time.Millisecond,
+			}
+
+			svc, err := internal.GetService(config, "waf", waf.New)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err = tt.call(svc)
+			if tt.expectError && err == nil {
+				t.Fatal("expected error, but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("got %d attempts, want %d", got, tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestCheckRetry(t *testing.T) {
+	t.Parallel()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	newResponse := func(method string, status int) *http.Response {
+		req := httptest.NewRequest(method, "https://api.example.com/v2/x", nil)
+		return &http.Response{StatusCode: status, Request: req}
+	}
+
+	connErr := func(op string) error {
+		return &url.Error{
+			Op:  op,
+			URL: "https://api.example.com/v2/x",
+			Err: errors.New("connection refused"),
+		}
+	}
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		resp *http.Response
+		err  error
+		want bool
+	}{
+		{
+			name: "canceled context",
+			ctx:  canceled,
+			resp: newResponse(http.MethodGet, 503),
+			want: false,
+		},
+		{
+			name: "GET success",
+			ctx:  context.Background(),
+			resp: newResponse(http.MethodGet, 200),
+			want: false,
+		},
+		{
+			name: "GET not found",
+			ctx:  context.Background(),
+			resp: newResponse(http.MethodGet, 404),
+			want: false,
+		},
+		{
+			name: "PUT server error",
+			ctx:  context.Background(),
+			resp: newResponse(http.MethodPut, 502),
+			want: true,
+		},
+		{
+			name: "PATCH server error",
+			ctx:  context.Background(),
+			resp: newResponse(http.MethodPatch, 502),
+			want: false,
+		},
+		{
+			name: "GET bad request",
+			ctx:  context.Background(),
+			resp: newResponse(http.MethodGet, 400),
+			want: false,
+		},
+		{
+			name: "DELETE throttled",
+			ctx:  context.Background(),
+			resp: newResponse(http.MethodDelete, 429),
+			want: true,
+		},
+		{
+			name: "GET connection error",
+			ctx:  context.Background(),
+			err:  connErr("Get"),
+			want: true,
+		},
+		{
+			name: "POST connection error",
+			ctx:  context.Background(),
+			err:  connErr("Post"),
+			want: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, _ := internal.CheckRetry(tt.ctx, tt.resp, tt.err)
+			if got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	withRetryAfter := func(v string) *http.Response {
+		return &http.Response{Header: http.Header{"Retry-After": []string{v}}}
+	}
+
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		attempt int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "no response",
+			resp:    nil,
+			attempt: 0,
+			wantMin: time.Second,
+			wantMax: time.Second,
+		},
+		{
+			name:    "exponential",
+			resp:    &http.Response{Header: http.Header{}},
+			attempt: 2,
+			wantMin: 4 * time.Second,
+			wantMax: 4 * time.Second,
+		},
+		{
+			name:    "retry after seconds",
+			resp:    withRetryAfter("7"),
+			wantMin: 7 * time.Second,
+			wantMax: 7 * time.Second,
+		},
+		{
+			name:    "retry after seconds capped at max",
+			resp:    withRetryAfter("3600"),
+			wantMin: time.Minute,
+			wantMax: time.Minute,
+		},
+		{
+			name: "retry after date",
+			resp: withRetryAfter(
+				time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)),
+			wantMin: 28 * time.Second,
+			wantMax: 30 * time.Second,
+		},
+		{
+			name: "retry after date in the past",
+			resp: withRetryAfter(
+				time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)),
+			wantMin: 0,
+			wantMax: 0,
+		},
+		{
+			name:    "invalid retry after",
+			resp:    withRetryAfter("soon"),
+			wantMin: time.Second,
+			wantMax: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := internal.Backoff(time.Second, time.Minute, tt.attempt, tt.resp)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("got %s, want between %s and %s", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
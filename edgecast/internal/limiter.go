@@ -0,0 +1,208 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RequestLimiter limits the rate and concurrency of API calls made by all
+// resources managed by a single provider instance. A nil RequestLimiter does
+// not limit anything. It is safe for concurrent use.
+type RequestLimiter struct {
+	// interval is the minimum time between the start of two API calls.
+	interval time.Duration
+
+	// slots holds one token per API call in flight.
+	slots chan struct{}
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// NewRequestLimiter creates a RequestLimiter. A value of zero for either
+// limit disables it. If both limits are disabled, nil is returned.
+func NewRequestLimiter(
+	requestsPerSecond int,
+	maxConcurrent int,
+) *RequestLimiter {
+	if requestsPerSecond <= 0 && maxConcurrent <= 0 {
+		return nil
+	}
+
+	l := &RequestLimiter{}
+
+	if requestsPerSecond > 0 {
+		l.interval = time.Second / time.Duration(requestsPerSecond)
+	}
+
+	if maxConcurrent > 0 {
+		l.slots = make(chan struct{}, maxConcurrent)
+	}
+
+	return l
+}
+
+// Acquire blocks until an API call may be made, or ctx is done. The returned
+// function must be called once the API call has completed.
+func (l *RequestLimiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+
+	if err := l.wait(ctx); err != nil {
+		release()
+		return nil, err
+	}
+
+	return release, nil
+}
+
+// wait blocks until the next API call may start, spacing calls evenly.
+func (l *RequestLimiter) wait(ctx context.Context) error {
+	if l.interval <= 0 {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	delay := time.Until(start)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Transport wraps an http.RoundTripper so that every request it sends,
+// including retries, is subject to the limiter.
+func (l *RequestLimiter) Transport(base http.RoundTripper) http.RoundTripper {
+	if l == nil {
+		return base
+	}
+
+	if t, ok := base.(*limitedTransport); ok && t.limiter == l {
+		return base
+	}
+
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &limitedTransport{base: base, limiter: l}
+}
+
+// limitedTransport holds a limiter slot from the time a request is sent until
+// its response body is closed.
+type limitedTransport struct {
+	base    http.RoundTripper
+	limiter *RequestLimiter
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	release, err := t.limiter.Acquire(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		release()
+		return resp, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
+
+// releasingBody releases a limiter slot when closed.
+type releasingBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+
+	return err
+}
+
+// WorkerGroup runs functions concurrently, at most as many at a time as the
+// provider allows concurrent API calls. It is used by resources that fan out
+// API calls e.g. one per origin in an origin group.
+type WorkerGroup struct {
+	wg    sync.WaitGroup
+	slots chan struct{}
+}
+
+// NewWorkerGroup creates a WorkerGroup bound by the provider's concurrency
+// limit.
+func (c ProviderConfig) NewWorkerGroup() *WorkerGroup {
+	g := &WorkerGroup{}
+
+	if c.MaxConcurrentRequests > 0 {
+		g.slots = make(chan struct{}, c.MaxConcurrentRequests)
+	}
+
+	return g
+}
+
+// Go runs f in a new goroutine, blocking until a worker is available.
+func (g *WorkerGroup) Go(f func()) {
+	if g.slots != nil {
+		g.slots <- struct{}{}
+	}
+
+	g.wg.Add(1)
+	go func() {
+		defer func() {
+			if g.slots != nil {
+				<-g.slots
+			}
+			g.wg.Done()
+		}()
+
+		f()
+	}()
+}
+
+// Wait blocks until all functions started with Go have returned.
+func (g *WorkerGroup) Wait() {
+	g.wg.Wait()
+}
@@ -0,0 +1,256 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/scopes"
+)
+
+func TestRequestLimiter_ConcurrencyAcrossServices(t *testing.T) {
+	t.Parallel()
+
+	const maxConcurrent = 2
+
+	var inFlight, peak int32
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			n := atomic.AddInt32(&inFlight, 1)
+			defer atomic.AddInt32(&inFlight, -1)
+
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+
+			time.Sleep(20 * time.Millisecond)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte("{}"))
+		}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	config := internal.ProviderConfig{
+		APIToken:              "token",
+		APIURLLegacy:          serverURL,
+		MaxConcurrentRequests: maxConcurrent,
+		Limiter:               internal.NewRequestLimiter(0, maxConcurrent),
+	}
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		// Build a separate service per call so that the limit is shown to
+		// apply across services rather than per HTTP client.
+		svc, err := internal.GetService(config, "waf", waf.New)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := svc.Scopes.GetAllScopes(
+				scopes.GetAllScopesParams{AccountNumber: "A1"})
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&peak); got > maxConcurrent {
+		t.Errorf("got %d concurrent requests, want at most %d", got, maxConcurrent)
+	}
+}
+
+func TestRequestLimiter_IDSTokens(t *testing.T) {
+	t.Parallel()
+
+	var tokens int32
+	ids := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&tokens, 1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"abc","expires_in":300}`))
+		}))
+	defer ids.Close()
+
+	idsURL, _ := url.Parse(ids.URL)
+	config := internal.ProviderConfig{
+		IdsClientID:     "id",
+		IdsClientSecret: "secret",
+		IdsScope:        "ec.rules",
+		IdsURL:          idsURL,
+		Limiter:         internal.NewRequestLimiter(0, 1),
+	}
+
+	// Hold the only slot, so that the token request has to wait for it.
+	release, err := config.Limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := config.AuthorizationProvider().GetAuthorizationHeader()
+		errs <- err
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	if got := atomic.LoadInt32(&tokens); got != 0 {
+		t.Fatalf("got %d token requests while the limit was reached, want 0", got)
+	}
+
+	release()
+
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&tokens); got != 1 {
+		t.Errorf("got %d token requests, want 1", got)
+	}
+}
+
+func TestRequestLimiter_Rate(t *testing.T) {
+	t.Parallel()
+
+	l := internal.NewRequestLimiter(100, 0)
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		release, err := l.Acquire(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release()
+	}
+
+	// The first call starts immediately and the rest are 10ms apart.
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("5 calls at 100/s took %s, want at least 40ms", elapsed)
+	}
+}
+
+func TestRequestLimiter_Canceled(t *testing.T) {
+	t.Parallel()
+
+	l := internal.NewRequestLimiter(0, 1)
+
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := l.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRequestLimiter_Disabled(t *testing.T) {
+	t.Parallel()
+
+	l := internal.NewRequestLimiter(0, 0)
+	if l != nil {
+		t.Fatal("expected no limiter when both limits are disabled")
+	}
+
+	release, err := l.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release()
+
+	if got := l.Transport(http.DefaultTransport); got != http.DefaultTransport {
+		t.Error("expected transport to be returned unchanged")
+	}
+}
+
+func TestWorkerGroup(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name          string
+		maxConcurrent int
+		workers       int
+		wantPeak      int32
+	}{
+		{
+			name:          "limited",
+			maxConcurrent: 2,
+			workers:       6,
+			wantPeak:      2,
+		},
+		{
+			name:          "unlimited",
+			maxConcurrent: 0,
+			workers:       6,
+			wantPeak:      6,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := internal.ProviderConfig{
+				MaxConcurrentRequests: tt.maxConcurrent,
+			}
+
+			var running, peak, done int32
+			release := make(chan struct{})
+
+			wg := config.NewWorkerGroup()
+			go func() {
+				for i := 0; i < tt.workers; i++ {
+					wg.Go(func() {
+						n := atomic.AddInt32(&running, 1)
+						for {
+							p := atomic.LoadInt32(&peak)
+							if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+								break
+							}
+						}
+
+						<-release
+						atomic.AddInt32(&running, -1)
+						atomic.AddInt32(&done, 1)
+					})
+				}
+			}()
+
+			// Give the workers time to start before letting them finish.
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+
+			for atomic.LoadInt32(&done) < int32(tt.workers) {
+				time.Sleep(time.Millisecond)
+			}
+			wg.Wait()
+
+			if got := atomic.LoadInt32(&peak); got != tt.wantPeak {
+				t.Errorf("got %d concurrent workers, want %d", got, tt.wantPeak)
+			}
+		})
+	}
+}
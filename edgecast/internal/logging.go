@@ -0,0 +1,257 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// Logging subsystems, one per Edgecast service. Each appears in TF_LOG output
+// under its own name, e.g. edgecast.waf, and its level may be set separately
+// through TF_LOG_PROVIDER_EDGECAST_<SUBSYSTEM> e.g.
+// TF_LOG_PROVIDER_EDGECAST_WAF=trace.
+const (
+	SubsystemCustomer    = "customer"
+	SubsystemCPS         = "cps"
+	SubsystemDNS         = "dns"
+	SubsystemEdgeCname   = "edgecname"
+	SubsystemOrigin      = "origin"
+	SubsystemOriginV3    = "originv3"
+	SubsystemRulesEngine = "rulesengine"
+	SubsystemWAF         = "waf"
+)
+
+// Fields added to log entries so that the entries for an operation and its
+// API calls can be correlated.
+const (
+	LogFieldResourceType  = "edgecast_resource_type"
+	LogFieldOperation     = "edgecast_operation"
+	LogFieldResourceID    = "edgecast_resource_id"
+	LogFieldHTTPMethod    = "http_method"
+	LogFieldHTTPURL       = "http_url"
+	LogFieldHTTPStatus    = "http_status_code"
+	LogFieldHTTPDuration  = "http_duration_ms"
+	LogFieldRequestID     = "edgecast_request_id"
+	logLevelEnvVarPrefix  = "TF_LOG_PROVIDER_EDGECAST_"
+)
+
+// RedactedValue replaces sensitive values in log entries and snapshots.
+const RedactedValue = "***"
+
+// sensitiveLogKeys are the names of fields whose values are never logged,
+// normalized by normalizeLogKey. They are matched at any depth of a logged
+// payload.
+var sensitiveLogKeys = map[string]bool{
+	"accesstoken":        true,
+	"apitoken":           true,
+	"authorization":      true,
+	"clientsecret":       true,
+	"email":              true,
+	"firstname":          true,
+	"idsclientsecret":    true,
+	"keyvalue":           true,
+	"lastname":           true,
+	"password":           true,
+	"phone":              true,
+	"privatekey":         true,
+	"recaptchasecretkey": true,
+	"secret":             true,
+	"token":              true,
+}
+
+// logSubsystemKey is the context key of the subsystem set by WithLogging.
+type logSubsystemKey struct{}
+
+// WithLogging wraps the CRUD and import functions of a resource or data source
+// so that log entries written through Logger during an operation go to the
+// given subsystem, carry fields identifying the resource and operation, and
+// have sensitive values masked. Resources must be wrapped by WithLogging after
+// BindContext so that the API calls made during the operation are logged to
+// the same subsystem.
+func WithLogging(
+	resource *schema.Resource,
+	resourceType string,
+	subsystem string,
+) *schema.Resource {
+	type crudFunc = func(
+		context.Context,
+		*schema.ResourceData,
+		interface{},
+	) diag.Diagnostics
+
+	wrap := func(f crudFunc, operation string) crudFunc {
+		if f == nil {
+			return nil
+		}
+
+		return func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) diag.Diagnostics {
+			ctx = newLogContext(ctx, subsystem, resourceType, operation, d.Id())
+			return f(ctx, d, m)
+		}
+	}
+
+	resource.CreateContext = wrap(resource.CreateContext, "create")
+	resource.ReadContext = wrap(resource.ReadContext, "read")
+	resource.UpdateContext = wrap(resource.UpdateContext, "update")
+	resource.DeleteContext = wrap(resource.DeleteContext, "delete")
+
+	if resource.Importer != nil && resource.Importer.StateContext != nil {
+		importState := resource.Importer.StateContext
+		resource.Importer.StateContext = func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) ([]*schema.ResourceData, error) {
+			ctx = newLogContext(ctx, subsystem, resourceType, "import", d.Id())
+			return importState(ctx, d, m)
+		}
+	}
+
+	return resource
+}
+
+// newLogContext sets up the subsystem logger of an operation.
+func newLogContext(
+	ctx context.Context,
+	subsystem string,
+	resourceType string,
+	operation string,
+	id string,
+) context.Context {
+	ctx = tflog.NewSubsystem(
+		ctx,
+		subsystem,
+		tflog.WithRootFields(),
+		tflog.WithLevelFromEnv(logLevelEnvVarPrefix+strings.ToUpper(subsystem)),
+		tflog.WithAdditionalLocationOffset(1))
+
+	ctx = tflog.SubsystemSetField(ctx, subsystem, LogFieldResourceType, resourceType)
+	ctx = tflog.SubsystemSetField(ctx, subsystem, LogFieldOperation, operation)
+
+	if len(id) > 0 {
+		ctx = tflog.SubsystemSetField(ctx, subsystem, LogFieldResourceID, id)
+	}
+
+	return context.WithValue(ctx, logSubsystemKey{}, subsystem)
+}
+
+// logSubsystem returns the subsystem set by WithLogging, if any.
+func logSubsystem(ctx context.Context) (string, bool) {
+	subsystem, ok := ctx.Value(logSubsystemKey{}).(string)
+	return subsystem, ok
+}
+
+// Logger writes log entries to the subsystem of an Edgecast service. The
+// values of fields are redacted before they are logged, so that SDK models
+// and other payloads may be passed as is.
+type Logger string
+
+// Trace logs msg at the TRACE level.
+func (l Logger) Trace(ctx context.Context, msg string, fields ...map[string]any) {
+	tflog.SubsystemTrace(ctx, string(l), msg, redactFields(fields)...)
+}
+
+// Debug logs msg at the DEBUG level.
+func (l Logger) Debug(ctx context.Context, msg string, fields ...map[string]any) {
+	tflog.SubsystemDebug(ctx, string(l), msg, redactFields(fields)...)
+}
+
+// Info logs msg at the INFO level.
+func (l Logger) Info(ctx context.Context, msg string, fields ...map[string]any) {
+	tflog.SubsystemInfo(ctx, string(l), msg, redactFields(fields)...)
+}
+
+// Warn logs msg at the WARN level.
+func (l Logger) Warn(ctx context.Context, msg string, fields ...map[string]any) {
+	tflog.SubsystemWarn(ctx, string(l), msg, redactFields(fields)...)
+}
+
+// Error logs msg at the ERROR level.
+func (l Logger) Error(ctx context.Context, msg string, fields ...map[string]any) {
+	tflog.SubsystemError(ctx, string(l), msg, redactFields(fields)...)
+}
+
+// redactFields redacts the values of each set of fields.
+func redactFields(fields []map[string]any) []map[string]any {
+	redacted := make([]map[string]any, 0, len(fields))
+
+	for _, f := range fields {
+		r := make(map[string]any, len(f))
+		for k, v := range f {
+			if sensitiveLogKeys[normalizeLogKey(k)] {
+				r[k] = RedactedValue
+				continue
+			}
+
+			r[k] = Redact(v)
+		}
+
+		redacted = append(redacted, r)
+	}
+
+	return redacted
+}
+
+// Redact returns a copy of v that is safe to log. Structs, maps and slices are
+// converted to their JSON representation with the values of sensitive fields,
+// e.g. recaptcha_secret_key or a TSIG KeyValue, replaced by "***". Other
+// values are returned unchanged.
+func Redact(v any) any {
+	switch v.(type) {
+	case nil, string, bool, int, int32, int64, float32, float64:
+		return v
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return RedactedValue
+	}
+
+	var decoded any
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return RedactedValue
+	}
+
+	return redactValue(decoded)
+}
+
+func redactValue(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, item := range value {
+			if sensitiveLogKeys[normalizeLogKey(k)] {
+				value[k] = RedactedValue
+				continue
+			}
+
+			value[k] = redactValue(item)
+		}
+
+		return value
+	case []any:
+		for i, item := range value {
+			value[i] = redactValue(item)
+		}
+
+		return value
+	default:
+		return v
+	}
+}
+
+// normalizeLogKey lowercases a field name and removes separators so that e.g.
+// "recaptcha_secret_key" and "ReCaptchaSecretKey" compare equal.
+func normalizeLogKey(key string) string {
+	key = strings.ToLower(key)
+	return strings.NewReplacer("_", "", "-", "").Replace(key)
+}
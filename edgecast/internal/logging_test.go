@@ -0,0 +1,208 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast/routedns"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/scopes"
+	"github.com/go-test/deep"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestRedact(t *testing.T) {
+	t.Parallel()
+
+	secret := "recaptcha-secret"
+	siteKey := "site-key"
+
+	tests := []struct {
+		name string
+		arg  any
+		want any
+	}{
+		{
+			name: "string",
+			arg:  "value",
+			want: "value",
+		},
+		{
+			name: "nested struct",
+			arg: scopes.Scopes{
+				CustomerID: "A1",
+				Scopes: []scopes.Scope{
+					{
+						ReCaptchaSecretKey: &secret,
+						ReCaptchaSiteKey:   &siteKey,
+					},
+				},
+			},
+			want: "***",
+		},
+		{
+			name: "tsig",
+			arg: routedns.TSIGGetOK{
+				TSIG: routedns.TSIG{
+					Alias:    "alias",
+					KeyName:  "key",
+					KeyValue: "c2VjcmV0",
+				},
+			},
+			want: "***",
+		},
+		{
+			name: "contact",
+			arg: []map[string]any{
+				{"first_name": "Jane", "email": "jane@example.com", "title": "CTO"},
+			},
+			want: []any{
+				map[string]any{
+					"first_name": "***",
+					"email":      "***",
+					"title":      "CTO",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := internal.Redact(tt.arg)
+
+			if tt.want == "***" {
+				// Only check that no secret survives.
+				b, err := json.Marshal(got)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if bytes.Contains(b, []byte(secret)) ||
+					bytes.Contains(b, []byte("c2VjcmV0")) {
+					t.Errorf("secret was not redacted: %v", got)
+				}
+
+				return
+			}
+
+			if diffs := deep.Equal(got, tt.want); len(diffs) > 0 {
+				t.Errorf("Differences: %v", diffs)
+			}
+		})
+	}
+}
+
+func TestWithLogging(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Request-Id", "req-456")
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"customer_id":"A1","scopes":[]}`))
+		}))
+	defer server.Close()
+
+	serverURL, _ := url.Parse(server.URL)
+	config := internal.ProviderConfig{
+		APIToken:     "token",
+		APIURLLegacy: serverURL,
+		Services:     internal.NewServiceRegistry(),
+	}
+
+	logger := internal.Logger(internal.SubsystemWAF)
+	resource := internal.WithLogging(
+		internal.BindContext(&schema.Resource{
+			ReadContext: func(
+				ctx context.Context,
+				d *schema.ResourceData,
+				m interface{},
+			) diag.Diagnostics {
+				svc, err := internal.GetService(
+					m.(internal.ProviderConfig),
+					"waf",
+					waf.New)
+				if err != nil {
+					return diag.FromErr(err)
+				}
+
+				logger.Debug(ctx, "Reading", map[string]any{
+					"recaptcha_secret_key": "secret",
+				})
+
+				_, err = svc.Scopes.GetAllScopes(
+					scopes.GetAllScopesParams{AccountNumber: "A1"})
+
+				return diag.FromErr(err)
+			},
+			Schema: map[string]*schema.Schema{},
+		}),
+		"edgecast_waf_scopes",
+		internal.SubsystemWAF)
+
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+
+	d := resource.TestResourceData()
+	d.SetId("A1")
+
+	if diags := resource.ReadContext(ctx, d, config); diags.HasError() {
+		t.Fatalf("unexpected diagnostics: %+v", diags)
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]map[string]any{
+		"Reading": {
+			"@module":                "provider.waf",
+			"edgecast_resource_type": "edgecast_waf_scopes",
+			"edgecast_operation":     "read",
+			"edgecast_resource_id":   "A1",
+			"recaptcha_secret_key":   "***",
+		},
+		"Received API response": {
+			"@module":             "provider.waf",
+			"edgecast_operation":  "read",
+			"http_method":         "GET",
+			"http_status_code":    float64(200),
+			"edgecast_request_id": "req-456",
+		},
+	}
+
+	for _, entry := range entries {
+		msg, _ := entry["@message"].(string)
+		fields, ok := want[msg]
+		if !ok {
+			continue
+		}
+
+		for k, v := range fields {
+			if entry[k] != v {
+				t.Errorf("%q: got %s = %v, want %v", msg, k, entry[k], v)
+			}
+		}
+
+		delete(want, msg)
+	}
+
+	for msg := range want {
+		t.Errorf("no log entry %q in %v", msg, entries)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// EnforceReadOnly wraps the create, update and delete functions of a resource
+// so that they fail before making any API call when the provider is in
+// read-only mode. Reads and imports are unaffected.
+func EnforceReadOnly(resource *schema.Resource) *schema.Resource {
+	type crudFunc = func(
+		context.Context,
+		*schema.ResourceData,
+		interface{},
+	) diag.Diagnostics
+
+	wrap := func(f crudFunc, operation string) crudFunc {
+		if f == nil {
+			return nil
+		}
+
+		return func(
+			ctx context.Context,
+			d *schema.ResourceData,
+			m interface{},
+		) diag.Diagnostics {
+			if config, ok := m.(ProviderConfig); ok && config.ReadOnly {
+				return readOnlyDiagnostics(config, operation)
+			}
+
+			return f(ctx, d, m)
+		}
+	}
+
+	resource.CreateContext = wrap(resource.CreateContext, "create")
+	resource.UpdateContext = wrap(resource.UpdateContext, "update")
+	resource.DeleteContext = wrap(resource.DeleteContext, "delete")
+
+	return resource
+}
+
+// readOnlyDiagnostics explains why an operation was blocked and how to allow
+// it.
+func readOnlyDiagnostics(config ProviderConfig, operation string) diag.Diagnostics {
+	source := config.Sources["read_only"]
+	if len(source) == 0 {
+		source = "provider configuration"
+	}
+
+	return diag.Diagnostics{
+		{
+			Severity: diag.Error,
+			Summary:  "Provider is in read-only mode",
+			Detail: fmt.Sprintf(
+				"Cannot %s the resource because read_only is enabled by %s. "+
+					"Remove the setting or set it to false to allow changes.",
+				operation,
+				source),
+		},
+	}
+}
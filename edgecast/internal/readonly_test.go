@@ -0,0 +1,98 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"context"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestEnforceReadOnly(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		readOnly    bool
+		operation   string
+		expectError bool
+	}{
+		{name: "create blocked", readOnly: true, operation: "create", expectError: true},
+		{name: "update blocked", readOnly: true, operation: "update", expectError: true},
+		{name: "delete blocked", readOnly: true, operation: "delete", expectError: true},
+		{name: "read allowed", readOnly: true, operation: "read"},
+		{name: "import allowed", readOnly: true, operation: "import"},
+		{name: "create allowed", readOnly: false, operation: "create"},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			called := false
+			crud := func(
+				ctx context.Context,
+				d *schema.ResourceData,
+				m interface{},
+			) diag.Diagnostics {
+				called = true
+				return nil
+			}
+
+			resource := internal.EnforceReadOnly(&schema.Resource{
+				CreateContext: crud,
+				ReadContext:   crud,
+				UpdateContext: crud,
+				DeleteContext: crud,
+				Importer: &schema.ResourceImporter{
+					StateContext: func(
+						ctx context.Context,
+						d *schema.ResourceData,
+						m interface{},
+					) ([]*schema.ResourceData, error) {
+						called = true
+						return []*schema.ResourceData{d}, nil
+					},
+				},
+				Schema: map[string]*schema.Schema{},
+			})
+
+			config := internal.ProviderConfig{
+				ReadOnly: tt.readOnly,
+				Sources: map[string]string{
+					"read_only": "environment variable EDGECAST_READ_ONLY",
+				},
+			}
+			ctx := context.Background()
+			d := resource.TestResourceData()
+
+			var diags diag.Diagnostics
+			switch tt.operation {
+			case "create":
+				diags = resource.CreateContext(ctx, d, config)
+			case "read":
+				diags = resource.ReadContext(ctx, d, config)
+			case "update":
+				diags = resource.UpdateContext(ctx, d, config)
+			case "delete":
+				diags = resource.DeleteContext(ctx, d, config)
+			case "import":
+				_, err := resource.Importer.StateContext(ctx, d, config)
+				diags = diag.FromErr(err)
+			}
+
+			if diags.HasError() != tt.expectError {
+				t.Fatalf("unexpected diagnostics: %+v", diags)
+			}
+
+			if called == tt.expectError {
+				t.Errorf("expected the wrapped function to be called: %t", !tt.expectError)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package recorder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// UpdateData returns the resource data that Terraform passes to an update
+// function to apply changes to an object read into d. The configuration is
+// the object's current settings with changes applied, so only the changed
+// settings differ from state.
+func UpdateData(
+	t *testing.T,
+	r *schema.Resource,
+	d *schema.ResourceData,
+	changes map[string]any,
+) *schema.ResourceData {
+	t.Helper()
+
+	raw := make(map[string]any)
+	for key, s := range r.Schema {
+		if s.Computed && !s.Optional && !s.Required {
+			continue
+		}
+
+		raw[key] = configValue(s, d.Get(key))
+	}
+
+	for key, value := range changes {
+		raw[key] = value
+	}
+
+	state := d.State()
+
+	diff, err := r.SimpleDiff(
+		context.Background(),
+		state,
+		terraform.NewResourceConfigRaw(raw),
+		nil)
+	if err != nil {
+		t.Fatalf("computing diff: %v", err)
+	}
+
+	data, err := schema.InternalMap(r.Schema).Data(state, diff)
+	if err != nil {
+		t.Fatalf("building resource data: %v", err)
+	}
+
+	return data
+}
+
+// configValue converts a value read from resource data into its raw
+// configuration form, e.g. sets into lists, leaving out computed attributes
+// of nested blocks.
+func configValue(s *schema.Schema, v any) any {
+	if set, ok := v.(*schema.Set); ok {
+		v = set.List()
+	}
+
+	list, ok := v.([]any)
+	if !ok {
+		return v
+	}
+
+	elem, isBlock := s.Elem.(*schema.Resource)
+
+	values := make([]any, 0, len(list))
+	for _, item := range list {
+		obj, isObj := item.(map[string]any)
+		if !isBlock || !isObj {
+			values = append(values, item)
+			continue
+		}
+
+		block := make(map[string]any)
+		for key, attr := range elem.Schema {
+			if attr.Computed && !attr.Optional && !attr.Required {
+				continue
+			}
+
+			block[key] = configValue(attr, obj[key])
+		}
+
+		values = append(values, block)
+	}
+
+	return values
+}
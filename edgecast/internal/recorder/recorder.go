@@ -0,0 +1,591 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+// Package recorder records API traffic to cassettes and replays it, so that
+// unit tests can exercise resource CRUD functions against recorded API
+// responses without an Edgecast account.
+//
+// By default a Recorder replays the cassette named by the test. When the
+// EDGECAST_RECORD environment variable is set to 1, it instead sends requests
+// to the API configured through the usual EDGECAST_* environment variables
+// and writes the exchanges to the cassette when the test passes. Secrets are
+// scrubbed before anything is written: the Authorization header and IDS token
+// exchanges are never recorded, secret fields are Redacted in JSON and form
+// bodies and in query strings, and the credentials and tokens in use are
+// Redacted wherever else they appear.
+//
+// Cassettes are stored as JSON under test/cassettes.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal"
+)
+
+// RecordEnvVar is the environment variable that switches recorders from
+// replaying to recording.
+const RecordEnvVar = "EDGECAST_RECORD"
+
+// Redacted replaces scrubbed secrets in cassettes and is used as the
+// credentials of replayed providers.
+const Redacted = "REDACTED"
+
+// scrubbedKeys are the JSON keys whose values are replaced with Redacted,
+// compared case-insensitively and ignoring underscores.
+var scrubbedKeys = map[string]bool{
+	"accesstoken":   true,
+	"apitoken":      true,
+	"authorization": true,
+	"clientsecret":  true,
+	"idtoken":       true,
+	"keyvalue":      true,
+	"password":      true,
+	"refreshtoken":  true,
+	"secret":        true,
+	"token":         true,
+}
+
+// recordedHeaders are the headers kept in cassettes. All others, including
+// Authorization, are dropped.
+var recordedHeaders = []string{"Content-Type", "X-Request-Id"}
+
+// Cassette is a recorded sequence of API exchanges.
+type Cassette struct {
+	// Vars holds test inputs that depend on the recorded account, e.g. the
+	// ID of an existing object. See Recorder.Var.
+	Vars map[string]string `json:"vars,omitempty"`
+
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a single recorded request and its response.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. Its URL holds the path and query only, so
+// that cassettes replay against any API address.
+type Request struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Response is a recorded response.
+type Response struct {
+	StatusCode int               `json:"status_code"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// Recorder records or replays the API traffic of a test.
+type Recorder struct {
+	t         *testing.T
+	name      string
+	recording bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+
+	// secrets are the credentials and IDS tokens used while recording, which
+	// are Redacted wherever they appear in recorded exchanges.
+	secrets []string
+}
+
+// New creates a Recorder for the cassette with the given name, e.g.
+// "cps/certificate_read" for test/cassettes/cps/certificate_read.json. When
+// replaying, the test fails if the cassette does not exist.
+func New(t *testing.T, name string) *Recorder {
+	t.Helper()
+
+	r := &Recorder{
+		t:         t,
+		name:      name,
+		recording: os.Getenv(RecordEnvVar) == "1",
+	}
+
+	if r.recording {
+		r.cassette.Vars = make(map[string]string)
+		t.Cleanup(r.save)
+		return r
+	}
+
+	b, err := os.ReadFile(r.path())
+	if err != nil {
+		t.Fatalf("reading cassette: %v (record it with %s=1)", err, RecordEnvVar)
+	}
+
+	if err := json.Unmarshal(b, &r.cassette); err != nil {
+		t.Fatalf("parsing cassette %s: %v", r.path(), err)
+	}
+
+	r.used = make([]bool, len(r.cassette.Interactions))
+
+	return r
+}
+
+// Recording reports whether the recorder is recording rather than replaying.
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Var returns a test input that depends on the recorded account. When
+// recording, it is read from the environment variable EDGECAST_RECORD_<NAME>
+// and saved to the cassette, and the test is skipped if it is not set. When
+// replaying, it is read from the cassette.
+func (r *Recorder) Var(name string) string {
+	r.t.Helper()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.recording {
+		v, ok := r.cassette.Vars[name]
+		if !ok {
+			r.t.Fatalf("cassette %s has no var %s", r.name, name)
+		}
+
+		return v
+	}
+
+	envVar := RecordEnvVar + "_" + strings.ToUpper(name)
+
+	v := os.Getenv(envVar)
+	if len(v) == 0 {
+		r.t.Skipf("%s must be set to record %s", envVar, r.name)
+	}
+
+	r.cassette.Vars[name] = v
+
+	return v
+}
+
+// ProviderConfig returns a provider configuration whose API calls go through
+// the recorder. When recording, settings are read from the EDGECAST_*
+// environment variables. When replaying, credentials are Redacted.
+func (r *Recorder) ProviderConfig() internal.ProviderConfig {
+	r.t.Helper()
+
+	setting := func(name string, replayed string) string {
+		if !r.recording {
+			return replayed
+		}
+
+		if v := os.Getenv(internal.EnvVarName(name)); len(v) > 0 {
+			return v
+		}
+
+		return replayed
+	}
+
+	config := internal.ProviderConfig{
+		APIToken:         setting("api_token", Redacted),
+		AccountNumber:    setting("account_number", ""),
+		IdsClientID:      setting("ids_client_id", Redacted),
+		IdsClientSecret:  setting("ids_client_secret", Redacted),
+		IdsScope:         setting("ids_scope", Redacted),
+		IDSAddress:       setting("ids_address", internal.DefaultIDSAddress),
+		APIAddress:       setting("api_address", internal.DefaultAPIAddress),
+		APIAddressLegacy: setting("api_address_legacy", internal.DefaultAPIAddressLegacy),
+		TransportHook:    r.Hook,
+	}
+
+	if r.recording {
+		r.addSecrets(config.APIToken, config.IdsClientSecret)
+	}
+
+	var err error
+
+	if config.IdsURL, err = url.Parse(config.IDSAddress); err != nil {
+		r.t.Fatalf("parsing IDS address: %v", err)
+	}
+
+	if config.APIURL, err = url.Parse(config.APIAddress); err != nil {
+		r.t.Fatalf("parsing API address: %v", err)
+	}
+
+	if config.APIURLLegacy, err = url.Parse(config.APIAddressLegacy); err != nil {
+		r.t.Fatalf("parsing legacy API address: %v", err)
+	}
+
+	return config
+}
+
+// Hook wraps a transport so that requests sent through it are recorded or
+// replayed. It is meant for ProviderConfig.TransportHook.
+func (r *Recorder) Hook(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return &transport{recorder: r, next: next}
+}
+
+type transport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// IDS token exchanges are never recorded, since they only carry secrets.
+	// The tokens they return are scrubbed from the exchanges that are.
+	if isTokenRequest(req) {
+		if t.recorder.recording {
+			return t.recorder.roundTripToken(t.next, req)
+		}
+
+		return newResponse(req, Response{
+			StatusCode: http.StatusOK,
+			Headers:    map[string]string{"Content-Type": "application/json"},
+			Body: fmt.Sprintf(
+				`{"access_token":%q,"expires_in":3600,"token_type":"Bearer"}`,
+				Redacted),
+		}), nil
+	}
+
+	recorded := Request{
+		Method:  req.Method,
+		URL:     t.recorder.scrubSecrets(requestURL(req.URL)),
+		Headers: t.recorder.scrubHeaders(recordHeaders(req.Header)),
+		Body:    t.recorder.scrubSecrets(scrubBody(body)),
+	}
+
+	if !t.recorder.recording {
+		resp, err := t.recorder.replay(recorded)
+		if err != nil {
+			return nil, err
+		}
+
+		return newResponse(req, resp), nil
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	t.recorder.record(Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    t.recorder.scrubHeaders(recordHeaders(resp.Header)),
+			Body:       t.recorder.scrubSecrets(scrubBody(respBody)),
+		},
+	})
+
+	return resp, nil
+}
+
+// roundTripToken sends an IDS token request and remembers the token it
+// returns as a secret.
+func (r *Recorder) roundTripToken(
+	next http.RoundTripper,
+	req *http.Request,
+) (*http.Response, error) {
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+	}
+	if json.Unmarshal(body, &token) == nil {
+		r.addSecrets(token.AccessToken)
+	}
+
+	return resp, nil
+}
+
+// addSecrets adds values to the secrets scrubbed from recorded exchanges.
+// Empty values and Redacted are ignored.
+func (r *Recorder) addSecrets(values ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, v := range values {
+		if len(v) > 0 && v != Redacted {
+			r.secrets = append(r.secrets, v)
+		}
+	}
+}
+
+// scrubSecrets replaces the secrets used while recording in s.
+func (r *Recorder) scrubSecrets(s string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, Redacted)
+	}
+
+	return s
+}
+
+func (r *Recorder) scrubHeaders(headers map[string]string) map[string]string {
+	for name, value := range headers {
+		headers[name] = r.scrubSecrets(value)
+	}
+
+	return headers
+}
+
+// replay returns the response to the first unused interaction that matches
+// the request.
+func (r *Recorder) replay(req Request) (Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.cassette.Interactions {
+		if r.used[i] || !matches(interaction.Request, req) {
+			continue
+		}
+
+		r.used[i] = true
+
+		return interaction.Response, nil
+	}
+
+	return Response{}, fmt.Errorf(
+		"cassette %s has no unused interaction for %s %s",
+		r.name,
+		req.Method,
+		req.URL)
+}
+
+func (r *Recorder) record(interaction Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+}
+
+// save writes the recorded cassette if the test passed.
+func (r *Recorder) save() {
+	if r.t.Failed() || r.t.Skipped() {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		r.t.Errorf("encoding cassette: %v", err)
+		return
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path()), 0o755); err != nil {
+		r.t.Errorf("writing cassette: %v", err)
+		return
+	}
+
+	if err := os.WriteFile(r.path(), append(b, '\n'), 0o644); err != nil {
+		r.t.Errorf("writing cassette: %v", err)
+	}
+}
+
+// path returns the cassette's file path under test/cassettes, found relative
+// to this source file so that tests in any package share the same cassettes.
+func (r *Recorder) path() string {
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(file), "..", "..", "..")
+
+	return filepath.Join(root, "test", "cassettes", filepath.FromSlash(r.name)+".json")
+}
+
+// matches reports whether a recorded request matches a request being
+// replayed. Bodies are compared after scrubbing and, for JSON, regardless of
+// formatting and key order.
+func matches(recorded Request, req Request) bool {
+	return recorded.Method == req.Method &&
+		recorded.URL == req.URL &&
+		recorded.Body == req.Body
+}
+
+func isTokenRequest(req *http.Request) bool {
+	return req.Method == http.MethodPost &&
+		strings.HasSuffix(req.URL.Path, "/connect/token")
+}
+
+// requestURL returns the path and query of a URL, with the query parameters
+// sorted and the values of scrubbed keys Redacted.
+func requestURL(u *url.URL) string {
+	if len(u.RawQuery) == 0 {
+		return u.Path
+	}
+
+	return u.Path + "?" + scrubValues(u.Query()).Encode()
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func recordHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string)
+	for _, name := range recordedHeaders {
+		if v := header.Get(name); len(v) > 0 {
+			headers[name] = v
+		}
+	}
+
+	if len(headers) == 0 {
+		return nil
+	}
+
+	return headers
+}
+
+// scrubBody replaces the values of scrubbed keys in a JSON or form body, and
+// normalizes the formatting of JSON bodies. Other bodies are kept as they
+// are.
+func scrubBody(body []byte) string {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return ""
+	}
+
+	// Numbers are kept as written so that large IDs are not rounded.
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var v any
+	if err := decoder.Decode(&v); err != nil {
+		if form, ok := parseForm(body); ok {
+			return scrubValues(form).Encode()
+		}
+
+		return string(body)
+	}
+
+	b, err := json.Marshal(scrub(v))
+	if err != nil {
+		return string(body)
+	}
+
+	return string(b)
+}
+
+func scrub(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if scrubbedKeys[normalizeKey(key)] {
+				v[key] = Redacted
+				continue
+			}
+
+			v[key] = scrub(value)
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = scrub(item)
+		}
+	}
+
+	return v
+}
+
+// parseForm parses a form-encoded body, e.g. grant_type=client_credentials.
+// false is returned if the body is not a form with at least one scrubbed key,
+// so that other bodies are kept as they are.
+func parseForm(body []byte) (url.Values, bool) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, false
+	}
+
+	for key := range form {
+		if scrubbedKeys[normalizeKey(key)] {
+			return form, true
+		}
+	}
+
+	return nil, false
+}
+
+// scrubValues replaces the values of scrubbed keys in form or query values.
+func scrubValues(values url.Values) url.Values {
+	for key, v := range values {
+		if !scrubbedKeys[normalizeKey(key)] {
+			continue
+		}
+
+		for i := range v {
+			v[i] = Redacted
+		}
+	}
+
+	return values
+}
+
+func normalizeKey(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", ""))
+}
+
+func newResponse(req *http.Request, recorded Response) *http.Response {
+	header := make(http.Header)
+	for name, value := range recorded.Headers {
+		header.Set(name, value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,472 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package recorder
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestScrubBody(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "empty",
+			body: " ",
+			want: "",
+		},
+		{
+			name: "not JSON",
+			body: "grant_type=client_credentials",
+			want: "grant_type=client_credentials",
+		},
+		{
+			name: "secrets are scrubbed at any depth",
+			body: `{"Password":"p","keys":[{"KeyValue":"k","Alias":"a"}],"api_token":"t"}`,
+			want: `{"Password":"REDACTED","api_token":"REDACTED","keys":[{"Alias":"a","KeyValue":"REDACTED"}]}`,
+		},
+		{
+			name: "form secrets are scrubbed",
+			body: "client_id=id&client_secret=s&grant_type=client_credentials",
+			want: "client_id=id&client_secret=REDACTED&grant_type=client_credentials",
+		},
+		{
+			name: "tokens are scrubbed",
+			body: `{"token":"t","refresh_token":"r","name":"n"}`,
+			want: `{"name":"n","refresh_token":"REDACTED","token":"REDACTED"}`,
+		},
+		{
+			name: "large numbers are kept",
+			body: `{ "id": 12345678901234567890 }`,
+			want: `{"id":12345678901234567890}`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := scrubBody([]byte(tt.body)); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReplay(t *testing.T) {
+	t.Parallel()
+
+	r := &Recorder{
+		t:    t,
+		name: "test",
+		cassette: Cassette{
+			Interactions: []Interaction{
+				{
+					Request:  Request{Method: http.MethodGet, URL: "/objects/1"},
+					Response: Response{StatusCode: http.StatusOK, Body: "first"},
+				},
+				{
+					Request:  Request{Method: http.MethodGet, URL: "/objects/1"},
+					Response: Response{StatusCode: http.StatusOK, Body: "second"},
+				},
+				{
+					Request: Request{
+						Method: http.MethodPut,
+						URL:    "/objects/1?a=1&b=2",
+						Body:   `{"name":"x"}`,
+					},
+					Response: Response{StatusCode: http.StatusNoContent},
+				},
+			},
+		},
+		used: make([]bool, 3),
+	}
+
+	client := &http.Client{Transport: r.Hook(nil)}
+
+	tests := []struct {
+		method   string
+		url      string
+		body     string
+		want     string
+		wantCode int
+		wantErr  bool
+	}{
+		{
+			method:   http.MethodPost,
+			url:      "https://id.example.com/connect/token",
+			body:     "client_secret=secret",
+			want:     `{"access_token":"REDACTED","expires_in":3600,"token_type":"Bearer"}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			method:   http.MethodGet,
+			url:      "https://api.example.com/objects/1",
+			want:     "first",
+			wantCode: http.StatusOK,
+		},
+		{
+			method:   http.MethodGet,
+			url:      "https://api.example.com/objects/1",
+			want:     "second",
+			wantCode: http.StatusOK,
+		},
+		{
+			method:  http.MethodGet,
+			url:     "https://api.example.com/objects/1",
+			wantErr: true,
+		},
+		{
+			method:  http.MethodPut,
+			url:     "https://api.example.com/objects/1?b=2&a=1",
+			body:    `{"name":"y"}`,
+			wantErr: true,
+		},
+		{
+			method:   http.MethodPut,
+			url:      "https://api.example.com/objects/1?b=2&a=1",
+			body:     `{ "name": "x" }`,
+			wantCode: http.StatusNoContent,
+		},
+	}
+
+	// Requests are sent in order, since replay consumes interactions.
+	for _, tt := range tests {
+		req, err := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := client.Do(req)
+		if tt.wantErr {
+			if err == nil {
+				resp.Body.Close()
+				t.Errorf("%s %s: expected an error", tt.method, tt.url)
+			}
+
+			continue
+		}
+
+		if err != nil {
+			t.Fatalf("%s %s: %v", tt.method, tt.url, err)
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if resp.StatusCode != tt.wantCode || string(body) != tt.want {
+			t.Errorf("%s %s: got %d %q, want %d %q",
+				tt.method, tt.url, resp.StatusCode, body, tt.wantCode, tt.want)
+		}
+	}
+}
+
+func TestRecord_ScrubsSecrets(t *testing.T) {
+	t.Parallel()
+
+	const (
+		apiToken    = "api-token-value"
+		accessToken = "access-token-value"
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+
+			if strings.HasSuffix(req.URL.Path, "/connect/token") {
+				w.Write([]byte(`{"access_token":"` + accessToken + `"}`))
+				return
+			}
+
+			w.Header().Set("X-Request-Id", accessToken)
+			w.Write([]byte(`{"echo":"` + req.Header.Get("Authorization") + `"}`))
+		}))
+	defer server.Close()
+
+	r := &Recorder{t: t, name: "test", recording: true}
+	r.addSecrets(apiToken, Redacted, "")
+
+	client := &http.Client{Transport: r.Hook(nil)}
+
+	send := func(method, path, body, authorization string) {
+		req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		req.Header.Set("Authorization", authorization)
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp.Body.Close()
+	}
+
+	send(http.MethodPost, "/connect/token", "client_secret=secret", "")
+	send(http.MethodGet, "/objects?key="+apiToken+"&token=t", "", "Bearer "+accessToken)
+	send(http.MethodPost, "/objects", `{"note":"`+apiToken+`"}`, "TOK:"+apiToken)
+
+	if len(r.cassette.Interactions) != 2 {
+		t.Fatalf("expected 2 interactions, got %d", len(r.cassette.Interactions))
+	}
+
+	if problems := findSecrets(r.cassette); len(problems) > 0 {
+		t.Errorf("unexpected secrets:\n%s", strings.Join(problems, "\n"))
+	}
+
+	b, err := json.Marshal(r.cassette)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range []string{apiToken, accessToken, "secret"} {
+		if strings.Contains(string(b), secret) {
+			t.Errorf("cassette contains %q: %s", secret, b)
+		}
+	}
+}
+
+func TestCassettes_NoSecrets(t *testing.T) {
+	t.Parallel()
+
+	_, file, _, _ := runtime.Caller(0)
+	root := filepath.Join(filepath.Dir(file), "..", "..", "..", "test", "cassettes")
+
+	paths, err := filepath.Glob(filepath.Join(root, "*", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(paths) == 0 {
+		t.Fatalf("no cassettes found under %s", root)
+	}
+
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var c Cassette
+		if err := json.Unmarshal(b, &c); err != nil {
+			t.Fatalf("parsing %s: %v", path, err)
+		}
+
+		for _, problem := range findSecrets(c) {
+			t.Errorf("%s: %s", path, problem)
+		}
+	}
+}
+
+func Test_findSecrets(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		interaction Interaction
+		want        bool
+	}{
+		{
+			name: "Clean",
+			interaction: Interaction{
+				Request:  Request{Method: http.MethodGet, URL: "/objects?token=REDACTED"},
+				Response: Response{StatusCode: http.StatusOK, Body: `{"KeyValue":"REDACTED"}`},
+			},
+		},
+		{
+			name: "Authorization Header",
+			interaction: Interaction{
+				Request: Request{Headers: map[string]string{"authorization": "x"}},
+			},
+			want: true,
+		},
+		{
+			name: "JSON Secret",
+			interaction: Interaction{
+				Response: Response{Body: `{"items":[{"client_secret":"s"}]}`},
+			},
+			want: true,
+		},
+		{
+			name:        "Query Secret",
+			interaction: Interaction{Request: Request{URL: "/objects?api_token=t"}},
+			want:        true,
+		},
+		{
+			name:        "Form Secret",
+			interaction: Interaction{Request: Request{Body: "password=p&user=u"}},
+			want:        true,
+		},
+		{
+			name:        "Bearer Token",
+			interaction: Interaction{Response: Response{Body: "invalid Bearer abc"}},
+			want:        true,
+		},
+		{
+			name: "JWT",
+			interaction: Interaction{
+				Response: Response{Body: `{"note":"eyJhbGciOiJSUzI1NiJ9.eyJzdWIiOiIxIn0.c2ln"}`},
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := findSecrets(Cassette{Interactions: []Interaction{tt.interaction}})
+			if (len(got) > 0) != tt.want {
+				t.Errorf("expected secrets: %t, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+var (
+	// credentialPattern matches authorization header values, whose
+	// credential must be Redacted.
+	credentialPattern = regexp.MustCompile(`(?i)\b(?:Bearer\s+|TOK:)([A-Za-z0-9._~+/=-]+)`)
+
+	// jwtPattern matches JSON web tokens, e.g. IDS access tokens.
+	jwtPattern = regexp.MustCompile(`eyJ[A-Za-z0-9_-]{8,}\.[A-Za-z0-9_-]{8,}\.`)
+)
+
+// findSecrets describes each secret in c that the recorder should have
+// scrubbed.
+func findSecrets(c Cassette) []string {
+	var problems []string
+
+	check := func(where string, headers map[string]string, body string) {
+		for name := range headers {
+			if !contains(recordedHeaders, name) {
+				problems = append(problems,
+					where+": unexpected header "+name)
+			}
+		}
+
+		for _, text := range append(headerValues(headers), body) {
+			for _, m := range credentialPattern.FindAllStringSubmatch(text, -1) {
+				if m[1] != Redacted {
+					problems = append(problems, where+": credential "+m[0])
+				}
+			}
+
+			if jwtPattern.MatchString(text) {
+				problems = append(problems, where+": JSON web token")
+			}
+		}
+
+		var v any
+		if json.Unmarshal([]byte(body), &v) == nil {
+			for _, key := range unscrubbedKeys(v) {
+				problems = append(problems, where+": body key "+key)
+			}
+		} else if form, err := url.ParseQuery(body); err == nil {
+			for _, key := range unscrubbedValues(form) {
+				problems = append(problems, where+": form key "+key)
+			}
+		}
+	}
+
+	for i, interaction := range c.Interactions {
+		where := "interaction " + strconv.Itoa(i)
+
+		if u, err := url.Parse(interaction.Request.URL); err == nil {
+			for _, key := range unscrubbedValues(u.Query()) {
+				problems = append(problems, where+": query key "+key)
+			}
+		}
+
+		check(where+" request", interaction.Request.Headers, interaction.Request.Body)
+		check(where+" response", interaction.Response.Headers, interaction.Response.Body)
+	}
+
+	return problems
+}
+
+// unscrubbedKeys returns the scrubbed keys in a JSON value whose values are
+// not Redacted.
+func unscrubbedKeys(v any) []string {
+	var keys []string
+
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			if scrubbedKeys[normalizeKey(key)] && value != Redacted {
+				keys = append(keys, key)
+				continue
+			}
+
+			keys = append(keys, unscrubbedKeys(value)...)
+		}
+	case []any:
+		for _, item := range v {
+			keys = append(keys, unscrubbedKeys(item)...)
+		}
+	}
+
+	return keys
+}
+
+// unscrubbedValues returns the scrubbed keys in form or query values whose
+// values are not Redacted.
+func unscrubbedValues(values url.Values) []string {
+	var keys []string
+	for key, v := range values {
+		if !scrubbedKeys[normalizeKey(key)] {
+			continue
+		}
+
+		for _, value := range v {
+			if value != Redacted {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+
+	return keys
+}
+
+func headerValues(headers map[string]string) []string {
+	values := make([]string, 0, len(headers))
+	for _, v := range headers {
+		values = append(values, v)
+	}
+
+	return values
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,249 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// The SDK neither exposes the HTTP clients of its services nor accepts an
+// authorization provider, so the provider follows a fixed path through the
+// SDK's unexported fields to reach them. Only types defined by the SDK are
+// inspected along the way: the path ends at the service's retryablehttp.Client
+// and IDS authorization provider, whose exported fields are the only ones
+// changed. All such access is confined to this file.
+
+const (
+	// sdkModule is the module path of the Edgecast SDK.
+	sdkModule = "github.com/EdgeCast/ec-sdk-go"
+
+	// sdkClientPackage is the SDK package of the API client shared by the
+	// parts of a service.
+	sdkClientPackage = sdkModule + "/edgecast/internal/ecclient"
+
+	// sdkAuthPackage is the SDK package of the IDS authorization provider.
+	sdkAuthPackage = sdkModule + "/edgecast/internal/ecauth"
+
+	// sdkIDSProviderName names the SDK's IDS authorization provider.
+	sdkIDSProviderName = "IDSAuthorizationProvider"
+
+	// sdkTokenPlaceholder is the IDS token given to the SDK's IDS
+	// authorization providers, so that they never request one themselves.
+	// tokenTransport replaces it with a token from the provider's
+	// idsTokenSource.
+	sdkTokenPlaceholder = "edgecast-provider-token"
+)
+
+// sdkServiceWrapper is implemented by services that extend an SDK service,
+// so that the SDK service they wrap is configured by configureService.
+type sdkServiceWrapper interface {
+	SDKService() any
+}
+
+// sdkService holds the parts of an SDK service that the provider configures.
+type sdkService struct {
+	// client sends the service's requests. It is shared by all the parts of
+	// the service, e.g. the rule clients of the WAF service.
+	client *retryablehttp.Client
+
+	// customRetry reports whether the service set a retry policy of its own.
+	customRetry bool
+
+	// idsProvider is the service's IDS authorization provider, or nil if the
+	// service authenticates with an API token.
+	idsProvider reflect.Value
+}
+
+// findSDKService locates the parts of an SDK service configured by the
+// provider. false is returned for services that are not defined by the SDK,
+// e.g. test doubles. An error is returned if the service does not have the
+// expected layout, e.g. after an SDK upgrade.
+func findSDKService(service any) (sdkService, bool, error) {
+	if w, ok := service.(sdkServiceWrapper); ok {
+		service = w.SDKService()
+	}
+
+	v := reflect.ValueOf(service)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct || !isSDKType(v.Type()) {
+		return sdkService{}, false, nil
+	}
+
+	layoutErr := func(what string) error {
+		return fmt.Errorf(
+			"%s not found in %T: the SDK's layout has changed",
+			what,
+			service)
+	}
+
+	apiClient, ok := findAPIClient(v)
+	if !ok {
+		return sdkService{}, true, layoutErr("API client")
+	}
+
+	adapter := field(apiClient, "reqSender", "clientAdapter")
+	if adapter.Kind() != reflect.Pointer || adapter.IsNil() {
+		return sdkService{}, true, layoutErr("HTTP client adapter")
+	}
+
+	httpClient := adapter.Elem().FieldByName("RetryableHttpClient")
+	if !httpClient.IsValid() ||
+		httpClient.Type() != reflect.TypeOf(&retryablehttp.Client{}) ||
+		httpClient.IsNil() {
+		return sdkService{}, true, layoutErr("HTTP client")
+	}
+
+	customRetry := adapter.Elem().FieldByName("HasCustomRetry")
+	if !customRetry.IsValid() || customRetry.Kind() != reflect.Bool {
+		return sdkService{}, true, layoutErr("retry policy")
+	}
+
+	s := sdkService{
+		client:      (*retryablehttp.Client)(httpClient.UnsafePointer()),
+		customRetry: customRetry.Bool(),
+	}
+
+	auth := field(apiClient, "reqBuilder", "authProvider")
+	if !auth.IsValid() {
+		return sdkService{}, true, layoutErr("authorization provider")
+	}
+
+	if isIDSProvider(auth) {
+		// The provider is shared by all copies of the API client, so it is
+		// changed in place through a pointer that allows setting its
+		// exported fields.
+		s.idsProvider = reflect.NewAt(auth.Type().Elem(), auth.UnsafePointer())
+	}
+
+	return s, true, nil
+}
+
+// findAPIClient returns the API client of an SDK service. Most services keep
+// it in a field of their own, while others, e.g. WAF, only hold clients for
+// parts of the API that each keep a copy of it.
+func findAPIClient(service reflect.Value) (reflect.Value, bool) {
+	if c := apiClient(service); c.IsValid() {
+		return c, true
+	}
+
+	for i := 0; i < service.NumField(); i++ {
+		if !service.Type().Field(i).IsExported() {
+			continue
+		}
+
+		part := service.Field(i)
+		for (part.Kind() == reflect.Interface ||
+			part.Kind() == reflect.Pointer) && !part.IsNil() {
+			part = part.Elem()
+		}
+
+		if part.Kind() != reflect.Struct || !isSDKType(part.Type()) {
+			continue
+		}
+
+		if c := apiClient(part); c.IsValid() {
+			return c, true
+		}
+	}
+
+	return reflect.Value{}, false
+}
+
+// apiClient returns the SDK API client held by the client field of v, if
+// any.
+func apiClient(v reflect.Value) reflect.Value {
+	c := v.FieldByName("client")
+	if !c.IsValid() || c.Kind() != reflect.Interface || c.IsNil() {
+		return reflect.Value{}
+	}
+
+	c = c.Elem()
+	if c.Kind() != reflect.Struct || c.Type().PkgPath() != sdkClientPackage {
+		return reflect.Value{}
+	}
+
+	return c
+}
+
+// field follows the named fields from v, looking through the interfaces that
+// hold them. The zero Value is returned if any of them is missing.
+func field(v reflect.Value, names ...string) reflect.Value {
+	for _, name := range names {
+		for v.Kind() == reflect.Interface && !v.IsNil() {
+			v = v.Elem()
+		}
+
+		if v.Kind() != reflect.Struct || !isSDKType(v.Type()) {
+			return reflect.Value{}
+		}
+
+		v = v.FieldByName(name)
+		if !v.IsValid() {
+			return v
+		}
+	}
+
+	for v.Kind() == reflect.Interface && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	return v
+}
+
+// isSDKType reports whether t is defined by the SDK.
+func isSDKType(t reflect.Type) bool {
+	return strings.HasPrefix(t.PkgPath(), sdkModule+"/")
+}
+
+// isIDSProvider reports whether v holds one of the SDK's IDS authorization
+// providers.
+func isIDSProvider(v reflect.Value) bool {
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return false
+	}
+
+	t := v.Type().Elem()
+
+	return t.PkgPath() == sdkAuthPackage && t.Name() == sdkIDSProviderName
+}
+
+// disableIDSRequests gives the SDK's IDS authorization provider a
+// placeholder token that never expires, so that it does not request tokens
+// of its own. An error is returned if the provider does not have the
+// expected layout.
+func (s sdkService) disableIDSRequests() error {
+	if !s.idsProvider.IsValid() {
+		return nil
+	}
+
+	current := s.idsProvider.Elem().FieldByName("CurrentToken")
+	if !current.IsValid() || current.Kind() != reflect.Pointer {
+		return fmt.Errorf(
+			"IDS token not found in %s: the SDK's layout has changed",
+			s.idsProvider.Type())
+	}
+
+	token := reflect.New(current.Type().Elem())
+	accessToken := token.Elem().FieldByName("AccessToken")
+	expiry := token.Elem().FieldByName("ExpirationTime")
+	if !accessToken.IsValid() || accessToken.Kind() != reflect.String ||
+		!expiry.IsValid() || expiry.Type() != reflect.TypeOf(time.Time{}) {
+		return fmt.Errorf(
+			"unexpected IDS token type %s: the SDK's layout has changed",
+			current.Type())
+	}
+
+	accessToken.SetString(sdkTokenPlaceholder)
+	expiry.Set(reflect.ValueOf(time.Now().AddDate(100, 0, 0)))
+	current.Set(token)
+
+	return nil
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/cps"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/customer"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/edgecname"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/origin"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/originv3"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/routedns"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/rulesengine"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf_bot_manager"
+)
+
+// TestSDKLayout checks that the SDK adapter can find the HTTP client and IDS
+// authorization provider of every SDK service. The SDK does not expose them,
+// so the adapter relies on unexported fields that may move in any SDK
+// release.
+func TestSDKLayout(t *testing.T) {
+	t.Parallel()
+
+	sdkConfig := internal.ProviderConfig{
+		APIToken:        "token",
+		IdsClientID:     "client",
+		IdsClientSecret: "secret",
+		IdsScope:        "scope",
+	}.NewSDKConfig()
+
+	tests := []struct {
+		name       string
+		newService func(edgecast.SDKConfig) (any, error)
+		wantIDS    bool
+	}{
+		{
+			name:       "cps",
+			newService: newAny(cps.New),
+			wantIDS:    true,
+		},
+		{
+			name:       "customer",
+			newService: newAny(customer.New),
+		},
+		{
+			name:       "edgecname",
+			newService: newAny(edgecname.New),
+		},
+		{
+			name:       "origin",
+			newService: newAny(origin.New),
+		},
+		{
+			name:       "originv3",
+			newService: newAny(originv3.New),
+			wantIDS:    true,
+		},
+		{
+			name:       "routedns",
+			newService: newAny(routedns.New),
+		},
+		{
+			name:       "rulesengine",
+			newService: newAny(rulesengine.New),
+			wantIDS:    true,
+		},
+		{
+			name:       "waf",
+			newService: newAny(waf.New),
+		},
+		{
+			name:       "waf_bot_manager",
+			newService: newAny(waf_bot_manager.New),
+			wantIDS:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			service, err := tt.newService(sdkConfig)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			client, ids, err := internal.FindSDKClient(service)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if client == nil {
+				t.Error("found no HTTP client: the SDK's layout has changed")
+			}
+
+			if ids != tt.wantIDS {
+				t.Errorf(
+					"found IDS authorization provider: %t, want %t",
+					ids,
+					tt.wantIDS)
+			}
+		})
+	}
+}
+
+// newAny adapts an SDK service constructor to return the service as any.
+func newAny[T any](
+	newService func(edgecast.SDKConfig) (T, error),
+) func(edgecast.SDKConfig) (any, error) {
+	return func(sdkConfig edgecast.SDKConfig) (any, error) {
+		return newService(sdkConfig)
+	}
+}
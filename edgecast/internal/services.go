@@ -0,0 +1,219 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast"
+)
+
+// ServiceRegistry holds the SDK services shared by all resources managed by a
+// single provider instance. Services are built the first time they are
+// requested and reused afterwards. A service that fails to build is built
+// again on the next request, so that a transient failure does not last for
+// the rest of the run. IDS-backed services share a single token, so that
+// credentials are exchanged for a token once per provider instead of once
+// per service or CRUD call. It is safe for concurrent use.
+//
+// Operations wrapped by BindContext do not use the shared services. Each of
+// them builds its own, whose API calls are bound to the operation's context,
+// while still sharing the token and the provider's transport.
+type ServiceRegistry struct {
+	mu       sync.Mutex
+	services map[string]*lazyService
+
+	tokensOnce sync.Once
+	tokens     *idsTokenSource
+}
+
+// lazyService builds a single SDK service, retrying on later calls until it
+// has been built successfully.
+type lazyService struct {
+	mu      sync.Mutex
+	service any
+}
+
+// get returns the service, building it with build if it has not been built
+// yet. Concurrent callers wait for a single build.
+func (s *lazyService) get(build func() (any, error)) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.service != nil {
+		return s.service, nil
+	}
+
+	service, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	s.service = service
+
+	return service, nil
+}
+
+// NewServiceRegistry creates an empty ServiceRegistry.
+func NewServiceRegistry() *ServiceRegistry {
+	return &ServiceRegistry{
+		services: make(map[string]*lazyService),
+	}
+}
+
+// entry returns the lazyService for the given name, creating it if needed.
+func (r *ServiceRegistry) entry(name string) *lazyService {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if s, ok := r.services[name]; ok {
+		return s
+	}
+
+	s := &lazyService{}
+	r.services[name] = s
+
+	return s
+}
+
+// tokenSource returns the IDS token source shared by the registry's services,
+// creating it from config on first use.
+func (r *ServiceRegistry) tokenSource(config ProviderConfig) *idsTokenSource {
+	r.tokensOnce.Do(func() {
+		r.tokens = newIDSTokenSource(config)
+	})
+
+	return r.tokens
+}
+
+// AuthorizationProvider returns the IDS token source of the SDK services built
+// with config, so that API clients outside the SDK share their token. Within
+// an operation, tokens are requested with the operation's context. Without a
+// ServiceRegistry, a new token source is created on every call.
+func (c ProviderConfig) AuthorizationProvider() AuthorizationProvider {
+	tokens := c.idsTokens()
+	if c.scope != nil {
+		return c.scope.authorization(tokens)
+	}
+
+	return tokens
+}
+
+// idsTokens returns the IDS token source of the SDK services built with
+// config.
+func (c ProviderConfig) idsTokens() *idsTokenSource {
+	if c.Services != nil {
+		return c.Services.tokenSource(c)
+	}
+
+	return newIDSTokenSource(c)
+}
+
+// NewSDKConfig creates the SDK configuration used to build SDK services.
+func (c ProviderConfig) NewSDKConfig() edgecast.SDKConfig {
+	sdkConfig := edgecast.NewSDKConfig()
+	sdkConfig.APIToken = c.APIToken
+	sdkConfig.IDSCredentials = edgecast.IDSCredentials{
+		ClientID:     c.IdsClientID,
+		ClientSecret: c.IdsClientSecret,
+		Scope:        c.IdsScope,
+	}
+
+	if c.APIURL != nil {
+		sdkConfig.BaseAPIURL = *c.APIURL
+	}
+
+	if c.APIURLLegacy != nil {
+		sdkConfig.BaseAPIURLLegacy = *c.APIURLLegacy
+	}
+
+	if c.IdsURL != nil {
+		sdkConfig.BaseIDSURL = *c.IdsURL
+	}
+
+	if len(c.UserAgent) > 0 {
+		sdkConfig.UserAgent = c.UserAgent
+	}
+
+	return sdkConfig
+}
+
+// GetService returns the SDK service registered under name, building it with
+// newService on first use. If config has no ServiceRegistry, e.g. in unit
+// tests, a new service is built on every call. Within a CRUD operation
+// wrapped by BindContext, the service is built for the operation instead, so
+// that its API calls are bound to the operation's context. A service in
+// config.ServiceOverrides is returned as is.
+func GetService[T any](
+	config ProviderConfig,
+	name string,
+	newService func(edgecast.SDKConfig) (T, error),
+) (T, error) {
+	if service, ok := config.ServiceOverrides[name]; ok {
+		return castService[T](name, service)
+	}
+
+	build := func() (any, error) {
+		return buildService(config, newService)
+	}
+
+	if config.scope != nil {
+		service, err := config.scope.service(name, build)
+		if err != nil {
+			var zero T
+			return zero, err
+		}
+
+		return castService[T](name, service)
+	}
+
+	if config.Services == nil {
+		return buildService(config, newService)
+	}
+
+	shared, err := config.Services.entry(name).get(build)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return castService[T](name, shared)
+}
+
+// castService converts a service registered under name to T.
+func castService[T any](name string, service any) (T, error) {
+	s, ok := service.(T)
+	if !ok {
+		var zero T
+		return zero, fmt.Errorf(
+			"service %s was registered as %T, not %T",
+			name,
+			service,
+			zero)
+	}
+
+	return s, nil
+}
+
+// buildService builds an SDK service and applies the provider-wide HTTP
+// settings to it.
+func buildService[T any](
+	config ProviderConfig,
+	newService func(edgecast.SDKConfig) (T, error),
+) (T, error) {
+	sdkConfig := config.NewSDKConfig()
+
+	service, err := newService(sdkConfig)
+	if err != nil {
+		return service, err
+	}
+
+	err = configureService(service, config)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+
+	return service, nil
+}
@@ -0,0 +1,317 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/rulesengine"
+)
+
+type fakeService struct {
+	config edgecast.SDKConfig
+}
+
+func TestGetService(t *testing.T) {
+	t.Parallel()
+
+	var builds int32
+	newService := func(c edgecast.SDKConfig) (*fakeService, error) {
+		atomic.AddInt32(&builds, 1)
+		return &fakeService{config: c}, nil
+	}
+
+	config := internal.ProviderConfig{
+		APIToken:  "token",
+		UserAgent: "test-agent",
+		Services:  internal.NewServiceRegistry(),
+	}
+
+	const workers = 20
+	results := make([]*fakeService, workers)
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			svc, err := internal.GetService(config, "fake", newService)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			results[i] = svc
+		}(i)
+	}
+	wg.Wait()
+
+	if builds != 1 {
+		t.Fatalf("expected service to be built once, built %d times", builds)
+	}
+
+	for _, svc := range results {
+		if svc != results[0] {
+			t.Fatal("expected all callers to share the same service")
+		}
+	}
+
+	if results[0].config.APIToken != "token" ||
+		results[0].config.UserAgent != "test-agent" {
+		t.Errorf("unexpected SDK config: %+v", results[0].config)
+	}
+}
+
+func TestGetService_Error(t *testing.T) {
+	t.Parallel()
+
+	var builds int32
+	newService := func(c edgecast.SDKConfig) (*fakeService, error) {
+		// The first two builds fail, e.g. because IDS is unavailable.
+		if atomic.AddInt32(&builds, 1) <= 2 {
+			return nil, errors.New("authentication error: HTTP request failed")
+		}
+
+		return &fakeService{config: c}, nil
+	}
+
+	config := internal.ProviderConfig{Services: internal.NewServiceRegistry()}
+
+	for i := 0; i < 2; i++ {
+		if _, err := internal.GetService(config, "fake", newService); err == nil {
+			t.Fatal("expected error, but got none")
+		}
+	}
+
+	// Failed builds are not cached, so a later call succeeds and its service
+	// is shared from then on.
+	first, err := internal.GetService(config, "fake", newService)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	second, err := internal.GetService(config, "fake", newService)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if first != second {
+		t.Error("expected the service to be shared once built")
+	}
+
+	if builds != 3 {
+		t.Errorf("expected service to be built 3 times, built %d times", builds)
+	}
+}
+
+// TestGetService_SharedIDSToken runs operations in parallel on a shared
+// IDS-backed service. Run with -race, it also checks that the token is not
+// accessed without synchronization.
+func TestGetService_SharedIDSToken(t *testing.T) {
+	t.Parallel()
+
+	var tokens int32
+	ids := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&tokens, 1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"abc","expires_in":300}`))
+		}))
+	defer ids.Close()
+
+	var unauthorized int32
+	api := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer abc" {
+				atomic.AddInt32(&unauthorized, 1)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"1"}`))
+		}))
+	defer api.Close()
+
+	idsURL, _ := url.Parse(ids.URL)
+	apiURL, _ := url.Parse(api.URL)
+	config := internal.ProviderConfig{
+		IdsClientID:     "id",
+		IdsClientSecret: "secret",
+		IdsScope:        "ec.rules",
+		IdsURL:          idsURL,
+		APIURL:          apiURL,
+		Services:        internal.NewServiceRegistry(),
+	}
+
+	const workers = 20
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			svc, err := internal.GetService(config, "rulesengine", rulesengine.New)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+
+			params := rulesengine.NewGetPolicyParams()
+			params.PolicyID = 1
+			if _, err := svc.GetPolicy(*params); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&tokens); got != 1 {
+		t.Errorf("got %d token requests, want 1", got)
+	}
+
+	if got := atomic.LoadInt32(&unauthorized); got != 0 {
+		t.Errorf("got %d unauthorized API calls, want 0", got)
+	}
+}
+
+// TestGetService_IDSError checks that an API call is not retried when no IDS
+// token could be retrieved for it.
+func TestGetService_IDSError(t *testing.T) {
+	t.Parallel()
+
+	var tokens int32
+	ids := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&tokens, 1)
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_client"}`))
+		}))
+	defer ids.Close()
+
+	var calls int32
+	api := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+		}))
+	defer api.Close()
+
+	idsURL, _ := url.Parse(ids.URL)
+	apiURL, _ := url.Parse(api.URL)
+	config := internal.ProviderConfig{
+		IdsClientID:     "id",
+		IdsClientSecret: "secret",
+		IdsScope:        "ec.rules",
+		IdsURL:          idsURL,
+		APIURL:          apiURL,
+		MaxRetries:      3,
+		Services:        internal.NewServiceRegistry(),
+	}
+
+	svc, err := internal.GetService(config, "rulesengine", rulesengine.New)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	params := rulesengine.NewGetPolicyParams()
+	params.PolicyID = 1
+	_, err = svc.GetPolicy(*params)
+	if err == nil || !strings.Contains(err.Error(), "invalid_client") {
+		t.Fatalf("expected IDS error, got %v", err)
+	}
+
+	if got := atomic.LoadInt32(&tokens); got != 1 {
+		t.Errorf("got %d token requests, want 1", got)
+	}
+
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("got %d API calls, want 0", got)
+	}
+}
+
+func TestGetService_NoRegistry(t *testing.T) {
+	t.Parallel()
+
+	var builds int32
+	newService := func(c edgecast.SDKConfig) (*fakeService, error) {
+		atomic.AddInt32(&builds, 1)
+		return &fakeService{config: c}, nil
+	}
+
+	config := internal.ProviderConfig{}
+
+	first, _ := internal.GetService(config, "fake", newService)
+	second, _ := internal.GetService(config, "fake", newService)
+
+	if builds != 2 || first == second {
+		t.Error("expected a new service for each call without a registry")
+	}
+}
+
+func TestGetService_TypeMismatch(t *testing.T) {
+	t.Parallel()
+
+	config := internal.ProviderConfig{Services: internal.NewServiceRegistry()}
+
+	_, err := internal.GetService(
+		config,
+		"fake",
+		func(c edgecast.SDKConfig) (*fakeService, error) {
+			return &fakeService{}, nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = internal.GetService(
+		config,
+		"fake",
+		func(c edgecast.SDKConfig) (string, error) {
+			return "", nil
+		})
+	if err == nil {
+		t.Fatal("expected error, but got none")
+	}
+}
+
+func TestGetService_Override(t *testing.T) {
+	t.Parallel()
+
+	override := &fakeService{}
+	config := internal.ProviderConfig{
+		Services:         internal.NewServiceRegistry(),
+		ServiceOverrides: map[string]any{"fake": override},
+	}
+
+	svc, err := internal.GetService(
+		config,
+		"fake",
+		func(c edgecast.SDKConfig) (*fakeService, error) {
+			t.Error("expected the override to be used instead of a new service")
+			return &fakeService{}, nil
+		})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if svc != override {
+		t.Error("expected the override to be returned")
+	}
+
+	_, err = internal.GetService(
+		config,
+		"fake",
+		func(c edgecast.SDKConfig) (string, error) {
+			return "", nil
+		})
+	if err == nil {
+		t.Fatal("expected error for an override of the wrong type, but got none")
+	}
+}
@@ -0,0 +1,258 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// tokenExpiryMargin is how long before its expiry an IDS token is replaced,
+// so that it does not expire while a request is in flight.
+const tokenExpiryMargin = 30 * time.Second
+
+// AuthorizationProvider provides the Authorization header of API calls. It
+// has the method set of the SDK's authorization providers, so that it can
+// stand in for them.
+type AuthorizationProvider interface {
+	GetAuthorizationHeader() (string, error)
+}
+
+// idsTokenSource requests IDS tokens on behalf of every SDK service of a
+// provider instance and caches them until shortly before they expire. It
+// replaces the SDK's IDS authorization providers, which each request a token
+// of their own and keep it in a field without any locking. It is safe for
+// concurrent use.
+type idsTokenSource struct {
+	tokenURL  string
+	form      url.Values
+	transport http.RoundTripper
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// newIDSTokenSource creates an idsTokenSource for the IDS credentials in
+// config. Tokens are requested through config.Transport,
+// config.TransportHook and config.Limiter, like API calls. Within an
+// operation, tokens are requested with the operation's context, see
+// serviceScope.authorization.
+func newIDSTokenSource(config ProviderConfig) *idsTokenSource {
+	idsURL := DefaultIDSAddress
+	if config.IdsURL != nil {
+		idsURL = config.IdsURL.String()
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "client_credentials")
+	form.Set("scope", config.IdsScope)
+	form.Set("client_id", config.IdsClientID)
+	form.Set("client_secret", config.IdsClientSecret)
+
+	return &idsTokenSource{
+		tokenURL:  strings.TrimSuffix(idsURL, "/") + "/connect/token",
+		form:      form,
+		transport: config.apiTransport(),
+	}
+}
+
+// GetAuthorizationHeader returns the Authorization header of API calls made
+// outside of an operation, requesting a new token if needed.
+func (s *idsTokenSource) GetAuthorizationHeader() (string, error) {
+	return s.authorizationHeader(context.Background(), s.transport)
+}
+
+// authorizationHeader returns the Authorization header of API calls,
+// requesting a new token with ctx through transport if needed.
+func (s *idsTokenSource) authorizationHeader(
+	ctx context.Context,
+	transport http.RoundTripper,
+) (string, error) {
+	token, err := s.accessToken(ctx, transport)
+	if err != nil {
+		return "", err
+	}
+
+	return "Bearer " + token, nil
+}
+
+// accessToken returns a valid IDS access token, requesting a new one if the cached
+// token has expired. Concurrent callers wait for a single request.
+func (s *idsTokenSource) accessToken(
+	ctx context.Context,
+	transport http.RoundTripper,
+) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.token) > 0 && time.Now().Before(s.expiry) {
+		return s.token, nil
+	}
+
+	token, expiresIn, err := s.requestToken(ctx, transport)
+	if err != nil {
+		return "", err
+	}
+
+	margin := tokenExpiryMargin
+	if expiresIn <= 2*margin {
+		margin = 0
+	}
+
+	s.token = token
+	s.expiry = time.Now().Add(expiresIn - margin)
+
+	return s.token, nil
+}
+
+// requestToken exchanges the client credentials for a token. Errors are
+// worded like the SDK's, so that authHint recognizes them.
+func (s *idsTokenSource) requestToken(
+	ctx context.Context,
+	transport http.RoundTripper,
+) (string, time.Duration, error) {
+	const errorPrefix = "authentication error:"
+
+	if len(s.form.Get("client_id")) == 0 ||
+		len(s.form.Get("client_secret")) == 0 ||
+		len(s.form.Get("scope")) == 0 {
+		return "", 0, errors.New("client ID, secret, and scope required")
+	}
+
+	body := s.form.Encode()
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		s.tokenURL,
+		strings.NewReader(body))
+	if err != nil {
+		return "", 0, fmt.Errorf(
+			"%s failed creating HTTP request: %w", errorPrefix, err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Cache-Control", "no-cache")
+
+	client := &http.Client{Transport: transport}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("%s HTTP request failed: %w", errorPrefix, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", 0, fmt.Errorf(
+			"%s error reading HTTP response: %w", errorPrefix, err)
+	}
+
+	if resp.StatusCode == http.StatusBadRequest {
+		var oauthErr struct {
+			Error string `json:"error"`
+		}
+		if err := json.Unmarshal(b, &oauthErr); err != nil {
+			return "", 0, fmt.Errorf(
+				"%s error parsing oAuth2Error response: %s", errorPrefix, b)
+		}
+
+		return "", 0, fmt.Errorf("%s bad request: %s", errorPrefix, oauthErr.Error)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf(
+			"%s expected 200 OK, received status code %d",
+			errorPrefix,
+			resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.Unmarshal(b, &token); err != nil {
+		return "", 0, fmt.Errorf(
+			"%s error decoding token response: %w", errorPrefix, err)
+	}
+
+	if len(token.AccessToken) == 0 {
+		return "", 0, errors.New(
+			"no access token retrieved, please check your IDS credentials")
+	}
+
+	return token.AccessToken, time.Duration(token.ExpiresIn) * time.Second, nil
+}
+
+// authorization returns an AuthorizationProvider that requests tokens from
+// tokens with the context of the operation.
+func (s *serviceScope) authorization(tokens *idsTokenSource) AuthorizationProvider {
+	return &scopedAuthorization{tokens: tokens, scope: s}
+}
+
+// scopedAuthorization stands in for an idsTokenSource within an operation.
+type scopedAuthorization struct {
+	tokens *idsTokenSource
+	scope  *serviceScope
+}
+
+func (a *scopedAuthorization) GetAuthorizationHeader() (string, error) {
+	return a.tokens.authorizationHeader(
+		a.scope.ctx,
+		a.scope.transport(a.tokens.transport))
+}
+
+// tokenError is returned by tokenTransport when no IDS token could be
+// retrieved. Requests failing with it are not retried, see CheckRetry.
+type tokenError struct {
+	err error
+}
+
+func (e *tokenError) Error() string {
+	return e.err.Error()
+}
+
+func (e *tokenError) Unwrap() error {
+	return e.err
+}
+
+// tokenTransport authorizes the requests of SDK services with tokens from an
+// idsTokenSource. The SDK's IDS authorization providers are given a
+// placeholder token, see sdkService.disableIDSRequests, which is replaced
+// here. Tokens are requested with the context of the request through
+// tokenRequests.
+type tokenTransport struct {
+	base          http.RoundTripper
+	tokens        *idsTokenSource
+	tokenRequests http.RoundTripper
+}
+
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Authorization") != "Bearer "+sdkTokenPlaceholder {
+		return t.base.RoundTrip(req)
+	}
+
+	authorization, err := t.tokens.authorizationHeader(
+		req.Context(),
+		t.tokenRequests)
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+
+		return nil, &tokenError{err: err}
+	}
+
+	// A RoundTripper must not modify the request it is given.
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", authorization)
+
+	return t.base.RoundTrip(req)
+}
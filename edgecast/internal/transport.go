@@ -0,0 +1,119 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// ConfigureTransport creates the HTTP transport used for all API and IDS
+// calls from the proxy and TLS settings in config. If none of these settings
+// are present, the default transport is used and config is left unchanged.
+func ConfigureTransport(config *ProviderConfig) error {
+	transport, err := newTransport(*config)
+	if err != nil || transport == nil {
+		return err
+	}
+
+	config.Transport = transport
+
+	return nil
+}
+
+// newTransport creates an http.Transport that applies the proxy and TLS
+// settings in config, or returns nil if none are set.
+func newTransport(config ProviderConfig) (*http.Transport, error) {
+	if len(config.ProxyURL) == 0 &&
+		len(config.CABundleFile) == 0 &&
+		len(config.ClientCertFile) == 0 &&
+		len(config.ClientKeyFile) == 0 {
+		return nil, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(config.ProxyURL) > 0 {
+		proxyURL, err := ParseProxyURL(config.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if len(config.CABundleFile) > 0 {
+		pool, err := loadCABundle(config.CABundleFile)
+		if err != nil {
+			return nil, err
+		}
+
+		transport.TLSClientConfig.RootCAs = pool
+	}
+
+	if len(config.ClientCertFile) > 0 || len(config.ClientKeyFile) > 0 {
+		if len(config.ClientCertFile) == 0 || len(config.ClientKeyFile) == 0 {
+			return nil, errors.New(
+				"client_cert_file and client_key_file must be set together")
+		}
+
+		cert, err := tls.LoadX509KeyPair(
+			config.ClientCertFile,
+			config.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return transport, nil
+}
+
+// ParseProxyURL parses and validates the proxy_url setting.
+func ParseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse proxy URL: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf(
+			"proxy URL scheme must be http, https or socks5, not %q",
+			proxyURL.Scheme)
+	}
+
+	if len(proxyURL.Host) == 0 {
+		return nil, errors.New("proxy URL must include a host")
+	}
+
+	return proxyURL, nil
+}
+
+// loadCABundle reads a PEM-encoded CA bundle and adds it to the system's
+// trusted roots.
+func loadCABundle(path string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("CA bundle %s contains no certificates", path)
+	}
+
+	return pool, nil
+}
@@ -0,0 +1,464 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package internal_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast/rulesengine"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/scopes"
+)
+
+// testCA issues certificates for local TLS servers and clients.
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pool *x509.CertPool
+	file string
+}
+
+func newTestCA(t *testing.T) *testCA {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(cert)
+
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	writePEM(t, file, "CERTIFICATE", der)
+
+	return &testCA{cert: cert, key: key, pool: pool, file: file}
+}
+
+// issue creates a certificate signed by the CA and returns it along with the
+// paths of its PEM certificate and key files.
+func (ca *testCA) issue(
+	t *testing.T,
+	usage x509.ExtKeyUsage,
+) (tls.Certificate, string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		DNSNames:     []string{"localhost"},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, certFile, keyFile
+}
+
+// newTLSServer starts a server with a certificate signed by the CA. If
+// requireClientCert is set, clients must present a certificate signed by the
+// CA.
+func (ca *testCA) newTLSServer(
+	t *testing.T,
+	requireClientCert bool,
+	handler http.Handler,
+) *httptest.Server {
+	t.Helper()
+
+	cert, _, _ := ca.issue(t, x509.ExtKeyUsageServerAuth)
+
+	server := httptest.NewUnstartedServer(handler)
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{cert}}
+	if requireClientCert {
+		server.TLS.ClientAuth = tls.RequireAndVerifyClientCert
+		server.TLS.ClientCAs = ca.pool
+	}
+
+	// Silence handshake failures, which some tests expect.
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func writePEM(t *testing.T, path string, blockType string, der []byte) {
+	t.Helper()
+
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func jsonHandler(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}
+}
+
+func getScopes(t *testing.T, config internal.ProviderConfig) error {
+	t.Helper()
+
+	svc, err := internal.GetService(config, "waf", waf.New)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = svc.Scopes.GetAllScopes(
+		scopes.GetAllScopesParams{AccountNumber: "A1"})
+
+	return err
+}
+
+func TestConfigureTransport_TLS(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	_, clientCertFile, clientKeyFile := ca.issue(t, x509.ExtKeyUsageClientAuth)
+	otherCA := newTestCA(t)
+	_, otherCertFile, otherKeyFile := otherCA.issue(t, x509.ExtKeyUsageClientAuth)
+
+	tests := []struct {
+		name              string
+		requireClientCert bool
+		config            internal.ProviderConfig
+		expectError       bool
+	}{
+		{
+			name:        "server signed by another CA",
+			config:      internal.ProviderConfig{CABundleFile: otherCA.file},
+			expectError: true,
+		},
+		{
+			name:   "trusted through CA bundle",
+			config: internal.ProviderConfig{CABundleFile: ca.file},
+		},
+		{
+			name:              "client certificate",
+			requireClientCert: true,
+			config: internal.ProviderConfig{
+				CABundleFile:   ca.file,
+				ClientCertFile: clientCertFile,
+				ClientKeyFile:  clientKeyFile,
+			},
+		},
+		{
+			name:              "missing client certificate",
+			requireClientCert: true,
+			config:            internal.ProviderConfig{CABundleFile: ca.file},
+			expectError:       true,
+		},
+		{
+			name:              "client certificate from another CA",
+			requireClientCert: true,
+			config: internal.ProviderConfig{
+				CABundleFile:   ca.file,
+				ClientCertFile: otherCertFile,
+				ClientKeyFile:  otherKeyFile,
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := ca.newTLSServer(t, tt.requireClientCert, jsonHandler("{}"))
+			serverURL, _ := url.Parse(server.URL)
+
+			config := tt.config
+			config.APIToken = "token"
+			config.APIURLLegacy = serverURL
+
+			if err := internal.ConfigureTransport(&config); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			err := getScopes(t, config)
+			if tt.expectError && err == nil {
+				t.Fatal("expected error, but got none")
+			}
+
+			if !tt.expectError && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestConfigureTransport_Proxy(t *testing.T) {
+	t.Parallel()
+
+	var proxied int32
+	proxy := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			// Proxied requests carry the absolute URL of the target.
+			if r.URL.Host == "api.example.invalid" {
+				atomic.AddInt32(&proxied, 1)
+			}
+
+			jsonHandler("{}")(w, r)
+		}))
+	defer proxy.Close()
+
+	apiURL, _ := url.Parse("http://api.example.invalid")
+	config := internal.ProviderConfig{
+		APIToken:     "token",
+		APIURLLegacy: apiURL,
+		ProxyURL:     proxy.URL,
+	}
+
+	if err := internal.ConfigureTransport(&config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := getScopes(t, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&proxied); got != 1 {
+		t.Errorf("got %d proxied requests, want 1", got)
+	}
+}
+
+func TestConfigureTransport_IDS(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	_, clientCertFile, clientKeyFile := ca.issue(t, x509.ExtKeyUsageClientAuth)
+
+	var tokens int32
+	ids := ca.newTLSServer(t, true, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&tokens, 1)
+			jsonHandler(`{"access_token":"abc","expires_in":300}`)(w, r)
+		}))
+
+	var authorized int32
+	api := ca.newTLSServer(t, true, http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "Bearer abc" {
+				atomic.AddInt32(&authorized, 1)
+			}
+
+			jsonHandler(`{"id":"1"}`)(w, r)
+		}))
+
+	idsURL, _ := url.Parse(ids.URL)
+	apiURL, _ := url.Parse(api.URL)
+	config := internal.ProviderConfig{
+		IdsClientID:     "id",
+		IdsClientSecret: "secret",
+		IdsScope:        "ec.rules",
+		IdsURL:          idsURL,
+		APIURL:          apiURL,
+		CABundleFile:    ca.file,
+		ClientCertFile:  clientCertFile,
+		ClientKeyFile:   clientKeyFile,
+	}
+
+	if err := internal.ConfigureTransport(&config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := http.DefaultTransport.(*http.Transport); !ok {
+		t.Errorf("http.DefaultTransport was replaced by %T", http.DefaultTransport)
+	}
+
+	svc, err := internal.GetService(config, "rulesengine", rulesengine.New)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	params := rulesengine.NewGetPolicyParams()
+	params.PolicyID = 1
+	if _, err := svc.GetPolicy(*params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&tokens); got != 1 {
+		t.Errorf("got %d token requests, want 1", got)
+	}
+
+	if got := atomic.LoadInt32(&authorized); got != 1 {
+		t.Errorf("got %d authorized API calls, want 1", got)
+	}
+}
+
+func TestTransportHook(t *testing.T) {
+	t.Parallel()
+
+	ids := httptest.NewServer(
+		jsonHandler(`{"access_token":"abc","expires_in":300}`))
+	defer ids.Close()
+
+	api := httptest.NewServer(jsonHandler(`{"id":"1"}`))
+	defer api.Close()
+
+	var paths []string
+	hook := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			paths = append(paths, r.URL.Path)
+			return next.RoundTrip(r)
+		})
+	}
+
+	idsURL, _ := url.Parse(ids.URL)
+	apiURL, _ := url.Parse(api.URL)
+	config := internal.ProviderConfig{
+		IdsClientID:     "id",
+		IdsClientSecret: "secret",
+		IdsScope:        "ec.rules",
+		IdsURL:          idsURL,
+		APIURL:          apiURL,
+		TransportHook:   hook,
+	}
+
+	svc, err := internal.GetService(config, "rulesengine", rulesengine.New)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	params := rulesengine.NewGetPolicyParams()
+	params.PolicyID = 1
+	if _, err := svc.GetPolicy(*params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"/connect/token", "/rules-engine/v1.1/policies/1"}
+	if len(paths) != len(want) || paths[0] != want[0] || paths[1] != want[1] {
+		t.Errorf("got hooked requests %v, want %v", paths, want)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestConfigureTransport_Errors(t *testing.T) {
+	t.Parallel()
+
+	ca := newTestCA(t)
+	_, certFile, _ := ca.issue(t, x509.ExtKeyUsageClientAuth)
+
+	tests := []struct {
+		name   string
+		config internal.ProviderConfig
+	}{
+		{
+			name:   "unsupported proxy scheme",
+			config: internal.ProviderConfig{ProxyURL: "ftp://proxy.example.com"},
+		},
+		{
+			name:   "proxy without host",
+			config: internal.ProviderConfig{ProxyURL: "http://"},
+		},
+		{
+			name: "missing CA bundle",
+			config: internal.ProviderConfig{
+				CABundleFile: filepath.Join(t.TempDir(), "missing.pem"),
+			},
+		},
+		{
+			name:   "CA bundle without certificates",
+			config: internal.ProviderConfig{CABundleFile: os.DevNull},
+		},
+		{
+			name:   "client certificate without key",
+			config: internal.ProviderConfig{ClientCertFile: certFile},
+		},
+		{
+			name: "client key does not match certificate",
+			config: internal.ProviderConfig{
+				ClientCertFile: certFile,
+				ClientKeyFile:  ca.file,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := tt.config
+			if err := internal.ConfigureTransport(&config); err == nil {
+				t.Fatal("expected error, but got none")
+			}
+		})
+	}
+}
@@ -5,8 +5,8 @@ package edgecast
 import (
 	"context"
 	"fmt"
+	"strings"
 
-	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 	"terraform-provider-edgecast/edgecast/resources/cps"
 	"terraform-provider-edgecast/edgecast/resources/customer"
@@ -18,15 +18,13 @@ import (
 	"terraform-provider-edgecast/edgecast/resources/waf"
 	"terraform-provider-edgecast/edgecast/resources/waf_bot_manager"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 const (
-	apiURLProd       string = "https://api.vdms.io"
-	apiURLProdLegacy string = "https://api.edgecast.com"
-	idsURLProd       string = "https://id.vdms.io"
-
 	// Version indicates the current version of this provider
 	Version string = "1.3.5"
 
@@ -44,7 +42,7 @@ func Provider() *schema.Provider {
 }
 
 func configureProvider(
-	tx context.Context,
+	ctx context.Context,
 	d *schema.ResourceData,
 ) (interface{}, diag.Diagnostics) {
 	config, err := internal.ExpandProviderConfig(d)
@@ -58,12 +56,42 @@ func configureProvider(
 		}
 	}
 
+	if err := internal.ConfigureTransport(config); err != nil {
+		return nil, diag.Diagnostics{
+			{
+				Summary:  "Failed to configure HTTP transport.",
+				Severity: diag.Error,
+				Detail:   err.Error(),
+			},
+		}
+	}
+
 	config.UserAgent = fmt.Sprintf(userAgentFormat, Version)
-	helper.LogInstanceAsPrettyJson("Provider Configuration", config)
+	config.Services = internal.NewServiceRegistry()
+	config.Limiter = internal.NewRequestLimiter(
+		config.MaxRequestsPerSecond,
+		config.MaxConcurrentRequests)
+	tflog.Debug(ctx, "Configured provider", map[string]any{
+		"account_number":          config.AccountNumber,
+		"api_address":             config.APIAddress,
+		"api_address_legacy":      config.APIAddressLegacy,
+		"ids_address":             config.IDSAddress,
+		"partner_id":              config.PartnerID,
+		"partner_user_id":         config.PartnerUserID,
+		"max_retries":             config.MaxRetries,
+		"max_requests_per_second": config.MaxRequestsPerSecond,
+		"max_concurrent_requests": config.MaxConcurrentRequests,
+		"read_only":               config.ReadOnly,
+		"sources":                 config.Sources,
+	})
 
 	return *config, nil
 }
 
+// GetProviderSchema returns the provider configuration schema. Any setting
+// omitted from the provider block falls back to an environment variable named
+// after it, e.g. api_token -> EDGECAST_API_TOKEN, and then to the selected
+// credentials file profile.
 func GetProviderSchema() map[string]*schema.Schema {
 	return map[string]*schema.Schema{
 		"api_token": {
@@ -109,49 +137,138 @@ func GetProviderSchema() map[string]*schema.Schema {
 			Type:        schema.TypeString,
 			Optional:    true,
 			Description: "The base url of Edgecast resource APIs. Omit to use the default url. For internal testing.",
-			Default:     apiURLProd,
 		},
 		"ids_address": {
 			Type:        schema.TypeString,
 			Optional:    true,
 			Description: "The base url of Edgecast identity APIs. Omit to use the default url. For internal testing.",
-			Default:     idsURLProd,
 		},
 		"api_address_legacy": {
 			Type:        schema.TypeString,
 			Optional:    true,
 			Description: "The base url of legacy Edgecast resource APIs. Omit to use the default url. For internal testing.",
-			Default:     apiURLProdLegacy,
+		},
+		"max_retries": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "The maximum number of times a throttled or failed API call is retried. Calls that are not idempotent are only retried when the API did not process them. Defaults to 5.",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"retry_wait_min": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      "The minimum time to wait between retries, e.g. `500ms` or `2s`. Defaults to `1s`.",
+			ValidateDiagFunc: internal.ValidateDuration,
+		},
+		"retry_wait_max": {
+			Type:             schema.TypeString,
+			Optional:         true,
+			Description:      "The maximum time to wait between retries, including any wait requested by the API through a `Retry-After` header. Defaults to `60s`.",
+			ValidateDiagFunc: internal.ValidateDuration,
+		},
+		"max_requests_per_second": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "The maximum number of API calls made per second across all resources, including retries. Defaults to 0, which means no limit.",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"max_concurrent_requests": {
+			Type:         schema.TypeInt,
+			Optional:     true,
+			Description:  "The maximum number of API calls in flight at once across all resources. Defaults to 0, which means no limit.",
+			ValidateFunc: validation.IntAtLeast(0),
+		},
+		"proxy_url": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The URL of a proxy through which all API and IDS calls are sent, e.g. `http://proxy.example.com:3128`. Defaults to the `HTTPS_PROXY` environment variable.",
+		},
+		"ca_bundle_file": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "The path to a PEM file of certificate authorities to trust in addition to the system's, e.g. for a TLS-intercepting proxy.",
+		},
+		"client_cert_file": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The path to a PEM client certificate presented to the API and IDS. Requires `client_key_file`.",
+			RequiredWith: []string{"client_key_file"},
+		},
+		"client_key_file": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The path to the PEM private key of `client_cert_file`.",
+			RequiredWith: []string{"client_cert_file"},
+		},
+		"read_only": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Description: "Prevents the provider from creating, updating or deleting any object, e.g. to safely plan or import against production. Reads, imports and data sources still work. Defaults to `false`.",
+		},
+		"profile": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Name of the credentials file section from which to read any settings not defined in the provider block or environment.",
+		},
+		"credentials_file": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Path to the credentials file used with `profile`. Defaults to `~/.edgecast/credentials`.",
 		},
 	}
 }
 
 func buildResourcesMap() map[string]*schema.Resource {
-	return map[string]*schema.Resource{
-		"edgecast_origin":                 origin.ResourceOrigin(),
-		"edgecast_edgecname":              edgecname.ResourceEdgeCname(),
-		"edgecast_customer":               customer.ResourceCustomer(),
-		"edgecast_customer_user":          customer.ResourceCustomerUser(),
-		"edgecast_rules_engine_policy":    rulesengine.ResourceRulesEngineV4Policy(),
-		"edgecast_dns_masterservergroup":  dnsroute.ResourceMasterServerGroup(),
-		"edgecast_dns_zone":               dnsroute.ResourceZone(),
-		"edgecast_dns_group":              dnsroute.ResourceGroup(),
-		"edgecast_dns_tsig":               dnsroute.ResourceTsig(),
-		"edgecast_dns_secondaryzonegroup": dnsroute.ResourceSecondaryZoneGroup(),
-		"edgecast_waf_access_rule":        waf.ResourceAccessRule(),
-		"edgecast_waf_rate_rule":          waf.ResourceRateRule(),
-		"edgecast_waf_managed_rule":       waf.ResourceManagedRule(),
-		"edgecast_waf_custom_rule_set":    waf.ResourceCustomRuleSet(),
-		"edgecast_waf_scopes":             waf.ResourceScopes(),
-		"edgecast_waf_bot_rule_set":       waf.ResourceBotRuleSet(),
-		"edgecast_cps_certificate":        cps.ResourceCertificate(),
-		"edgecast_originv3_httplarge":     originv3.ResourceOriginGrpHttpLarge(),
-		"edgecast_waf_botmanager":         waf_bot_manager.ResourceBotManager(),
+	resources := map[string]*schema.Resource{
+		"edgecast_origin": internal.InheritAccountNumber(
+			origin.ResourceOrigin(), "account_number"),
+		"edgecast_edgecname": internal.InheritAccountNumber(
+			edgecname.ResourceEdgeCname(), "account_number"),
+		"edgecast_customer":      customer.ResourceCustomer(),
+		"edgecast_customer_user": customer.ResourceCustomerUser(),
+		"edgecast_rules_engine_policy": internal.InheritAccountNumber(
+			rulesengine.ResourceRulesEngineV4Policy(), "account_number"),
+		"edgecast_dns_masterservergroup": internal.InheritAccountNumber(
+			dnsroute.ResourceMasterServerGroup(), "account_number"),
+		"edgecast_dns_zone": internal.InheritAccountNumber(
+			dnsroute.ResourceZone(), "account_number"),
+		"edgecast_dns_group": internal.InheritAccountNumber(
+			dnsroute.ResourceGroup(), "account_number"),
+		"edgecast_dns_tsig": internal.InheritAccountNumber(
+			dnsroute.ResourceTsig(), "account_number"),
+		"edgecast_dns_secondaryzonegroup": internal.InheritAccountNumber(
+			dnsroute.ResourceSecondaryZoneGroup(), "account_number"),
+		"edgecast_waf_access_rule": internal.InheritAccountNumber(
+			waf.ResourceAccessRule(), "account_number"),
+		"edgecast_waf_rate_rule": internal.InheritAccountNumber(
+			waf.ResourceRateRule(), "account_number"),
+		"edgecast_waf_managed_rule": internal.InheritAccountNumber(
+			waf.ResourceManagedRule(), "account_number"),
+		"edgecast_waf_custom_rule_set": internal.InheritAccountNumber(
+			waf.ResourceCustomRuleSet(), "account_number"),
+		"edgecast_waf_scopes": internal.InheritAccountNumber(
+			waf.ResourceScopes(), "account_number"),
+		"edgecast_waf_bot_rule_set": internal.InheritAccountNumber(
+			waf.ResourceBotRuleSet(), "account_number"),
+		"edgecast_cps_certificate":    cps.ResourceCertificate(),
+		"edgecast_originv3_httplarge": originv3.ResourceOriginGrpHttpLarge(),
+		"edgecast_waf_botmanager": internal.InheritAccountNumber(
+			waf_bot_manager.ResourceBotManager(), "customer_id"),
 	}
+
+	for name, r := range resources {
+		internal.WithLogging(
+			internal.BindContext(
+				internal.ExplainErrors(internal.EnforceReadOnly(r))),
+			name,
+			logSubsystem(name))
+	}
+
+	return resources
 }
 
 func buildDataSourcesMap() map[string]*schema.Resource {
-	return map[string]*schema.Resource{
+	dataSources := map[string]*schema.Resource{
 		"edgecast_customer_services":                     customer.DataSourceCustomerServices(),
 		"edgecast_cps_countrycodes":                      cps.DataSourceCountryCodes(),
 		"edgecast_cps_dcv_types":                         cps.DataSourceDCVTypes(),
@@ -167,4 +284,40 @@ func buildDataSourcesMap() map[string]*schema.Resource {
 		"edgecast_originv3_protocoltypes":                originv3.DataSourceProtocolTypes(),
 		"edgecast_originv3_hostname_resolution_methods":  originv3.DataSourceHostnameResolutionMethods(),
 	}
+
+	for name, r := range dataSources {
+		internal.WithLogging(
+			internal.BindContext(internal.ExplainErrors(r)),
+			name,
+			logSubsystem(name))
+	}
+
+	return dataSources
+}
+
+// logSubsystems maps resource and data source name prefixes to the logging
+// subsystem of their service. Longer prefixes must come first.
+var logSubsystems = []struct {
+	prefix    string
+	subsystem string
+}{
+	{"edgecast_originv3_", internal.SubsystemOriginV3},
+	{"edgecast_origin", internal.SubsystemOrigin},
+	{"edgecast_edgecname", internal.SubsystemEdgeCname},
+	{"edgecast_customer", internal.SubsystemCustomer},
+	{"edgecast_rules_engine_", internal.SubsystemRulesEngine},
+	{"edgecast_dns_", internal.SubsystemDNS},
+	{"edgecast_waf_", internal.SubsystemWAF},
+	{"edgecast_cps_", internal.SubsystemCPS},
+}
+
+// logSubsystem returns the logging subsystem of a resource or data source.
+func logSubsystem(name string) string {
+	for _, s := range logSubsystems {
+		if strings.HasPrefix(name, s.prefix) {
+			return s.subsystem
+		}
+	}
+
+	panic(fmt.Sprintf("no logging subsystem for %s", name))
 }
@@ -7,7 +7,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"sort"
 	"strconv"
 	"strings"
@@ -24,7 +23,6 @@ import (
 	"github.com/ahmetalpbalkan/go-linq"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/kr/pretty"
 )
 
 const (
@@ -37,8 +35,12 @@ func ResourceCertificate() *schema.Resource {
 		ReadContext:   ResourceCertificateRead,
 		UpdateContext: ResourceCertificateUpdate,
 		DeleteContext: ResourceCertificateDelete,
-		Importer:      helper.Import(ResourceCertificateImportRead, "id"),
-		Schema:        GetCertificateSchema(),
+		Importer: helper.ImportWithLookup(
+			ResourceCertificateImportRead,
+			lookupCertificate,
+			"id"),
+		Timeouts: internal.DefaultResourceTimeouts(),
+		Schema:   GetCertificateSchema(),
 	}
 }
 
@@ -113,8 +115,10 @@ func ResourceCertificateCreate(
 		}
 	}
 
-	log.Printf("[INFO] certificate created: %# v\n", pretty.Formatter(cresp))
-	log.Printf("[INFO] certificate id: %d\n", cresp.ID)
+	logger.Info(ctx, "Created certificate", map[string]any{
+		"certificate_id": cresp.ID,
+		"response":       cresp,
+	})
 
 	d.SetId(strconv.Itoa(int(cresp.ID)))
 
@@ -238,6 +242,48 @@ func ResourceCertificateImportRead(ctx context.Context,
 	return read(ctx, d, m, true)
 }
 
+// lookupCertificate returns the ID of the certificate whose label is name.
+func lookupCertificate(
+	_ context.Context,
+	_ *schema.ResourceData,
+	m interface{},
+	name string,
+) (string, error) {
+	config, ok := m.(internal.ProviderConfig)
+	if !ok {
+		return "", errors.New("failed to load configuration")
+	}
+
+	svc, err := buildCPSService(config)
+	if err != nil {
+		return "", err
+	}
+
+	var certs []*models.CdnProvidedCertificateWithoutOrg
+	for page := int32(1); ; page++ {
+		params := certificate.NewCertificateFindParams()
+		params.Page = &page
+
+		resp, err := svc.Certificate.CertificateFind(params)
+		if err != nil {
+			return "", err
+		}
+
+		certs = append(certs, resp.Items...)
+		if len(resp.Items) == 0 || len(certs) >= int(resp.TotalItems) {
+			break
+		}
+	}
+
+	return helper.FindIDByName("certificate", name, certs,
+		func(c *models.CdnProvidedCertificateWithoutOrg) string {
+			return c.CertificateLabel
+		},
+		func(c *models.CdnProvidedCertificateWithoutOrg) string {
+			return strconv.FormatInt(c.ID, 10)
+		})
+}
+
 func ResourceCertificateRead(ctx context.Context,
 	d *schema.ResourceData,
 	m interface{},
@@ -266,19 +312,21 @@ func read(ctx context.Context,
 	}
 
 	// Call APIs.
-	log.Printf("[INFO] Retrieving certificate : ID: %d\n", certID)
+	logger.Info(ctx, "Retrieving certificate", map[string]any{
+		"certificate_id": certID,
+	})
 
 	params := certificate.NewCertificateGetParams()
 	params.ID = certID
 
 	resp, err := svc.Certificate.CertificateGet(params)
 	if err != nil {
-		return helper.DiagFromErrorf("error getting cert: %w", err)
+		return helper.ReadError(ctx, d, fmt.Errorf("error getting cert: %w", err))
 	}
 
-	log.Printf("[INFO] Retrieved certificate: %# v\n", pretty.Formatter(resp))
-
-	log.Printf("[INFO] Retrieving certificate Status : ID: %d\n", certID)
+	logger.Debug(ctx, "Retrieved certificate", map[string]any{
+		"certificate": resp,
+	})
 
 	statusparams := certificate.NewCertificateGetCertificateStatusParams()
 	statusparams.ID = certID
@@ -288,28 +336,22 @@ func read(ctx context.Context,
 		return helper.DiagFromErrorf("error getting cert status: %w", err)
 	}
 
-	log.Printf(
-		"[INFO] Retrieved certificate request status: %# v\n",
-		pretty.Formatter(statusresp))
-
-	log.Printf("[INFO] Retrieving certificate dcv metadata : ID: %d\n", certID)
+	logger.Debug(ctx, "Retrieved certificate request status", map[string]any{
+		"status": statusresp,
+	})
 
 	var metadata []*models.DomainDcvFull
 
 	if resp.ValidationType == models.CdnProvidedCertificateValidationTypeDV {
 		// DV
 		// Use one API call for all domains.
-		metadata = GetDomainMetadata(resp, svc)
+		metadata = GetDomainMetadata(ctx, resp, svc)
 	} else {
 		// EV | OV
 		// Group domains by parent domain and call api for each group.
 		metadata = GetDomainGroupedMetadata(resp, svc)
 	}
 
-	log.Printf(
-		"[INFO] Retrieving certificate notification settings: ID: %d\n",
-		certID)
-
 	nparams := certificate.NewCertificateGetRequestNotificationsParams()
 	nparams.ID = certID
 
@@ -320,11 +362,10 @@ func read(ctx context.Context,
 			err)
 	}
 
-	log.Printf(
-		"[INFO] Retrieved certificate notification settings: %# v\n",
-		pretty.Formatter(nresp))
-
-	log.Printf("[INFO] metadata ALL: %# v\n", pretty.Formatter(metadata))
+	logger.Debug(ctx, "Retrieved certificate details", map[string]any{
+		"notification_settings": nresp,
+		"dcv_metadata":          metadata,
+	})
 
 	// Write TF state.
 	err = setCertificateState(d, resp, nresp, statusresp, metadata, isImport)
@@ -336,6 +377,7 @@ func read(ctx context.Context,
 }
 
 func GetDomainMetadata(
+	ctx context.Context,
 	resp *certificate.CertificateGetOK,
 	svc *cps.CpsService,
 ) []*models.DomainDcvFull {
@@ -360,14 +402,14 @@ func GetDomainMetadata(
 	if err == nil {
 		// continue
 		metadata = append(metadata, dcvresp.Items...)
+		logger.Debug(ctx, "Retrieved DCV metadata for DV certificate",
+			map[string]any{"dcv_metadata": dcvresp})
 	} else {
-		log.Printf("error while getting domain metadata: %s", err.Error())
+		logger.Warn(ctx, "Failed to get domain metadata", map[string]any{
+			"error": err.Error(),
+		})
 	}
 
-	log.Printf(
-		"[INFO] Retrieved dcv metadata for DV cert: %# v\n",
-		pretty.Formatter(dcvresp))
-
 	return metadata
 }
 
@@ -586,7 +628,7 @@ func ResourceCertificateUpdate(
 		return helper.DiagFromError("failed to determine update flow", err)
 	}
 
-	err = updater.Update()
+	err = updater.Update(ctx)
 	if err != nil {
 		return helper.DiagFromError("failed to update certificate", err)
 	}
@@ -643,7 +685,9 @@ func ResourceCertificateDelete(
 			return helper.DiagFromErrorf("error cancelling cert: %w", err)
 		}
 
-		log.Printf("[INFO] Canceled Certificate ID: %v", certID)
+		logger.Info(ctx, "Canceled certificate", map[string]any{
+			"certificate_id": certID,
+		})
 
 	} else if (statusResp.Status == "DomainControlValidation" ||
 		statusResp.Status == "OtherValidation") &&
@@ -660,7 +704,9 @@ func ResourceCertificateDelete(
 			return helper.DiagFromErrorf("error cancelling cert: %w", err)
 		}
 
-		log.Printf("[INFO] Canceled Certificate ID: %v", certID)
+		logger.Info(ctx, "Canceled certificate", map[string]any{
+			"certificate_id": certID,
+		})
 
 	} else {
 		// certificate has been issued.
@@ -672,7 +718,9 @@ func ResourceCertificateDelete(
 			return helper.DiagFromErrorf("error deleting cert: %w", err)
 		}
 
-		log.Printf("[INFO] Deleted Certificate ID: %v", certID)
+		logger.Info(ctx, "Deleted certificate", map[string]any{
+			"certificate_id": certID,
+		})
 	}
 
 	d.SetId("")
@@ -1299,27 +1347,27 @@ type CertUpdater struct {
 	UpdateOrganization         bool
 }
 
-func (u CertUpdater) Update() error {
-	if err := u.updateBasicSettings(); err != nil {
+func (u CertUpdater) Update(ctx context.Context) error {
+	if err := u.updateBasicSettings(ctx); err != nil {
 		return err
 	}
 
-	if err := u.updateNotificationSettings(); err != nil {
+	if err := u.updateNotificationSettings(ctx); err != nil {
 		return err
 	}
 
-	if err := u.updateDCVMethod(); err != nil {
+	if err := u.updateDCVMethod(ctx); err != nil {
 		return err
 	}
 
-	if err := u.updateOrganization(); err != nil {
+	if err := u.updateOrganization(ctx); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func (u CertUpdater) updateBasicSettings() error {
+func (u CertUpdater) updateBasicSettings(ctx context.Context) error {
 	params := certificate.NewCertificatePatchParams()
 	params.ID = u.State.CertificateID
 	params.CertificateRequest = &models.CertificateUpdate{
@@ -1338,14 +1386,16 @@ func (u CertUpdater) updateBasicSettings() error {
 		return fmt.Errorf("failed to update certificate: %w", err)
 	}
 
-	log.Printf("[INFO] certificate updated: %# v\n", pretty.Formatter(resp))
+	logger.Info(ctx, "Updated certificate", map[string]any{
+		"response": resp,
+	})
 
 	return nil
 }
 
-func (u CertUpdater) updateNotificationSettings() error {
+func (u CertUpdater) updateNotificationSettings(ctx context.Context) error {
 	if !u.UpdateNotificationSettings {
-		log.Printf("[INFO] Skipped updating notification settings")
+		logger.Info(ctx, "Skipped updating notification settings")
 		return nil
 	}
 
@@ -1358,24 +1408,26 @@ func (u CertUpdater) updateNotificationSettings() error {
 		return fmt.Errorf("failed to update notif settings: %w", err)
 	}
 
-	log.Printf("[INFO] notif settings updated: %# v\n", pretty.Formatter(resp))
+	logger.Info(ctx, "Updated notification settings", map[string]any{
+		"response": resp,
+	})
 
 	return nil
 }
 
-func (u CertUpdater) updateDCVMethod() error {
+func (u CertUpdater) updateDCVMethod(ctx context.Context) error {
 	// not yet implemeted.
 	if !u.UpdateDCVMethod {
-		log.Printf("[INFO] Skipped updating DCV method")
+		logger.Info(ctx, "Skipped updating DCV method")
 		return nil
 	}
 
 	return nil
 }
 
-func (u CertUpdater) updateOrganization() error {
+func (u CertUpdater) updateOrganization(ctx context.Context) error {
 	if !u.UpdateOrganization {
-		log.Printf("[INFO] Skipped updating organization")
+		logger.Info(ctx, "Skipped updating organization")
 		return nil
 	}
 
@@ -1388,7 +1440,9 @@ func (u CertUpdater) updateOrganization() error {
 		return fmt.Errorf("failed to update org details: %w", err)
 	}
 
-	log.Printf("[INFO] org details updated: %# v\n", pretty.Formatter(resp))
+	logger.Info(ctx, "Updated organization details", map[string]any{
+		"response": resp,
+	})
 
 	return nil
 }
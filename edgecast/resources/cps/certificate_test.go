@@ -4,6 +4,7 @@
 package cps
 
 import (
+	"context"
 	"errors"
 	"log"
 	"reflect"
@@ -12,6 +13,8 @@ import (
 	"time"
 
 	"terraform-provider-edgecast/edgecast/helper"
+	"terraform-provider-edgecast/edgecast/internal"
+	"terraform-provider-edgecast/edgecast/internal/recorder"
 
 	sdkcps "github.com/EdgeCast/ec-sdk-go/edgecast/cps"
 	"github.com/EdgeCast/ec-sdk-go/edgecast/cps/certificate"
@@ -1800,7 +1803,7 @@ func TestUpdaterUpdate(t *testing.T) {
 				UpdateOrganization:         tt.args.UpdateOrganization,
 			}
 
-			updater.Update()
+			updater.Update(context.Background())
 
 			// If UpdateNotificationSettings, assert that call did occurred.
 			if tt.args.UpdateNotificationSettings && len(mockNotifFunc.ParamsPassed) == 0 {
@@ -1825,18 +1828,166 @@ func TestUpdaterUpdate(t *testing.T) {
 	}
 }
 
+func TestResourceCertificateDelete(t *testing.T) {
+	t.Parallel()
+
+	statusWithOrder := func(status string, orderStatus string) func(params certificate.CertificateGetCertificateStatusParams) (*certificate.CertificateGetCertificateStatusOK, error) {
+		return func(params certificate.CertificateGetCertificateStatusParams) (*certificate.CertificateGetCertificateStatusOK, error) {
+			return &certificate.CertificateGetCertificateStatusOK{
+				CertificateStatus: models.CertificateStatus{
+					Status:          status,
+					OrderValidation: &models.OrderValidation{Status: orderStatus},
+				},
+			}, nil
+		}
+	}
+
+	apiErr := errors.New("sendRequest failed (HTTP StatusCode:500): ")
+
+	tests := []struct {
+		name       string
+		statusFunc func(params certificate.CertificateGetCertificateStatusParams) (*certificate.CertificateGetCertificateStatusOK, error)
+		callErr    error
+		wantCancel bool
+		wantDelete bool
+		expectErr  bool
+	}{
+		{
+			name:       "Processing and not placed is canceled",
+			statusFunc: mockStatusFunc("Processing"),
+			wantCancel: true,
+		},
+		{
+			name:       "DomainControlValidation pending is canceled",
+			statusFunc: statusWithOrder("DomainControlValidation", "Pending"),
+			wantCancel: true,
+		},
+		{
+			name:       "OtherValidation pending is canceled",
+			statusFunc: statusWithOrder("OtherValidation", "Pending"),
+			wantCancel: true,
+		},
+		{
+			name:       "DomainControlValidation issued is deleted",
+			statusFunc: statusWithOrder("DomainControlValidation", "Issued"),
+			wantDelete: true,
+		},
+		{
+			name:       "Processing and placed is deleted",
+			statusFunc: statusWithOrder("Processing", "Pending"),
+			wantDelete: true,
+		},
+		{
+			name:       "Active is deleted",
+			statusFunc: mockStatusFunc("Active"),
+			wantDelete: true,
+		},
+		{
+			name:       "Deleted outside of Terraform is removed",
+			statusFunc: mockStatusFunc("Deleted"),
+		},
+		{
+			name: "Error path status API error",
+			statusFunc: func(params certificate.CertificateGetCertificateStatusParams) (*certificate.CertificateGetCertificateStatusOK, error) {
+				return nil, apiErr
+			},
+			expectErr: true,
+		},
+		{
+			name:       "Error path cancel API error",
+			statusFunc: mockStatusFunc("Processing"),
+			callErr:    apiErr,
+			wantCancel: true,
+			expectErr:  true,
+		},
+		{
+			name:       "Error path delete API error",
+			statusFunc: mockStatusFunc("Active"),
+			callErr:    apiErr,
+			wantDelete: true,
+			expectErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var canceled, deleted []int64
+			mockSvc := &sdkcps.CpsService{
+				Certificate: mockCertificateService{
+					funcCertificateGetCertificateStatus: tt.statusFunc,
+					funcCertificateCancel: func(params certificate.CertificateCancelParams) (*certificate.CertificateCancelNoContent, error) {
+						if !params.Apply {
+							t.Error("expected the cancellation to be applied")
+						}
+
+						canceled = append(canceled, params.ID)
+						return &certificate.CertificateCancelNoContent{}, tt.callErr
+					},
+					funcCertificateDelete: func(params certificate.CertificateDeleteParams) (*certificate.CertificateDeleteNoContent, error) {
+						deleted = append(deleted, params.ID)
+						return &certificate.CertificateDeleteNoContent{}, tt.callErr
+					},
+				},
+			}
+
+			config := internal.ProviderConfig{
+				ServiceOverrides: map[string]any{"cps": mockSvc},
+			}
+
+			d := schema.TestResourceDataRaw(t, ResourceCertificate().Schema, map[string]any{})
+			d.SetId("42")
+
+			diags := ResourceCertificateDelete(context.Background(), d, config)
+
+			if tt.expectErr != diags.HasError() {
+				t.Fatalf("expected error: %t, got %v", tt.expectErr, diags)
+			}
+
+			if gotCancel := len(canceled) == 1 && canceled[0] == 42; gotCancel != tt.wantCancel {
+				t.Errorf("expected cancel: %t, got calls %v", tt.wantCancel, canceled)
+			}
+
+			if gotDelete := len(deleted) == 1 && deleted[0] == 42; gotDelete != tt.wantDelete {
+				t.Errorf("expected delete: %t, got calls %v", tt.wantDelete, deleted)
+			}
+
+			wantID := ""
+			if tt.expectErr {
+				wantID = "42"
+			}
+
+			if d.Id() != wantID {
+				t.Errorf("expected ID %q, got %q", wantID, d.Id())
+			}
+		})
+	}
+}
+
 type mockCertificateService struct {
+	funcCertificateCancel                     func(params certificate.CertificateCancelParams) (*certificate.CertificateCancelNoContent, error)
+	funcCertificateDelete                     func(params certificate.CertificateDeleteParams) (*certificate.CertificateDeleteNoContent, error)
 	funcCertificateGetCertificateStatus       func(params certificate.CertificateGetCertificateStatusParams) (*certificate.CertificateGetCertificateStatusOK, error)
 	funcCertificateUpdateRequestNotifications *MockUpdateNotificationsFunc
 	funcCertificatePutOrganizationDetails     *MockUpdateOrgFunc
 }
 
 func (svc mockCertificateService) CertificateCancel(params certificate.CertificateCancelParams) (*certificate.CertificateCancelNoContent, error) {
+	if svc.funcCertificateCancel != nil {
+		return svc.funcCertificateCancel(params)
+	}
+
 	// default implementation
 	return nil, nil
 }
 
 func (svc mockCertificateService) CertificateDelete(params certificate.CertificateDeleteParams) (*certificate.CertificateDeleteNoContent, error) {
+	if svc.funcCertificateDelete != nil {
+		return svc.funcCertificateDelete(params)
+	}
+
 	// default implementation
 	return nil, nil
 }
@@ -1965,3 +2116,34 @@ func NewMockUpdateNotificationsFunc() *MockUpdateNotificationsFunc {
 
 	return mf
 }
+
+func TestResourceCertificateRead(t *testing.T) {
+	t.Parallel()
+
+	rec := recorder.New(t, "cps/certificate_read")
+	certID := rec.Var("certificate_id")
+
+	d := schema.TestResourceDataRaw(t, ResourceCertificate().Schema, map[string]any{})
+	d.SetId(certID)
+
+	diags := ResourceCertificateRead(context.Background(), d, rec.ProviderConfig())
+	if diags.HasError() {
+		t.Fatalf("unexpected errors: %v", diags)
+	}
+
+	if d.Id() != certID {
+		t.Errorf("got ID %s, want %s", d.Id(), certID)
+	}
+
+	if len(d.Get("certificate_label").(string)) == 0 {
+		t.Error("expected certificate_label to be set")
+	}
+
+	if len(d.Get("domain").([]any)) == 0 {
+		t.Error("expected domains to be set")
+	}
+
+	if d.Get("validation_status").(*schema.Set).Len() == 0 {
+		t.Error("expected validation_status to be set")
+	}
+}
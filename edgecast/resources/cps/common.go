@@ -6,39 +6,25 @@ package cps
 import (
 	"context"
 	"fmt"
-	"log"
 
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
-	"github.com/EdgeCast/ec-sdk-go/edgecast"
 	"github.com/EdgeCast/ec-sdk-go/edgecast/cps"
 	"github.com/EdgeCast/ec-sdk-go/edgecast/cps/models"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/kr/pretty"
 )
 
-// buildCPSService builds the SDK CPS service to manage CPS
-// resources.
+// logger writes to the CPS logging subsystem.
+var logger = internal.Logger(internal.SubsystemCPS)
+
+// buildCPSService returns the shared SDK CPS service to manage CPS resources.
+// Tests may override it with a CpsService whose clients are mocks.
 func buildCPSService(
 	config internal.ProviderConfig,
 ) (*cps.CpsService, error) {
-	idsCredentials := edgecast.IDSCredentials{
-		ClientID:     config.IdsClientID,
-		ClientSecret: config.IdsClientSecret,
-		Scope:        config.IdsScope,
-	}
-
-	sdkConfig := edgecast.NewSDKConfig()
-	sdkConfig.APIToken = config.APIToken
-	sdkConfig.IDSCredentials = idsCredentials
-	sdkConfig.BaseAPIURL = *config.APIURL
-	sdkConfig.BaseAPIURLLegacy = *config.APIURLLegacy
-	sdkConfig.BaseIDSURL = *config.IdsURL
-	sdkConfig.UserAgent = config.UserAgent
-
-	return cps.New(sdkConfig)
+	return internal.GetService(config, "cps", cps.New)
 }
 
 func DataSourceNamedEntityRead(
@@ -60,7 +46,9 @@ func DataSourceNamedEntityRead(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Retrieved: %# v\n", pretty.Formatter(resp))
+	logger.Debug(ctx, "Retrieved named entities", map[string]any{
+		"response": resp,
+	})
 
 	flattened := FlattenNamedEntities(resp)
 	d.Set("items", flattened)
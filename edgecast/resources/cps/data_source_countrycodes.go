@@ -5,7 +5,6 @@ package cps
 
 import (
 	"context"
-	"log"
 
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
@@ -13,7 +12,6 @@ import (
 	"github.com/EdgeCast/ec-sdk-go/edgecast/cps/appendix"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/kr/pretty"
 )
 
 func DataSourceCountryCodes() *schema.Resource {
@@ -79,9 +77,9 @@ func DataSourceCountryCodesRead(
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Retrieved Country Codes: %# v",
-		pretty.Formatter(resp))
+	logger.Debug(ctx, "Retrieved country codes", map[string]any{
+		"response": resp,
+	})
 
 	flattened := FlattenCountries(resp)
 	d.Set("items", flattened)
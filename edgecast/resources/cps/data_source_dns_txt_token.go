@@ -6,7 +6,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -66,8 +65,6 @@ func DataSourceDNSTXTTokenRead(
 		return diag.Errorf("invalid wait_timeout: %v", err)
 	}
 
-	log.Printf("timeout: %v\n", timeout)
-
 	config, ok := m.(internal.ProviderConfig)
 	if !ok {
 		return diag.Errorf("failed to load configuration")
@@ -84,18 +81,21 @@ func DataSourceDNSTXTTokenRead(
 	}
 
 	// Call APIs.
-	log.Printf("[INFO] Retrieving certificate : ID: %d\n", certID)
+	logger.Info(ctx, "Retrieving certificate", map[string]any{
+		"certificate_id": certID,
+	})
 
 	params := certificate.NewCertificateGetParams()
 	params.ID = certID
 
-	log.Printf("[INFO] Retrieving certificate Status: ID: %d\n", certID)
 	statusparams := certificate.NewCertificateGetCertificateStatusParams()
 	statusparams.ID = certID
 
 	retry := d.Get("wait_until_available").(bool)
-	log.Printf("wait_until_available: %t\n", retry)
-	log.Printf("timeout: %v\n", d.Timeout(schema.TimeoutRead))
+	logger.Debug(ctx, "Waiting for DNS TXT token", map[string]any{
+		"wait_until_available": retry,
+		"wait_timeout":         timeout.String(),
+	})
 
 	err = resource.RetryContext(
 		ctx,
@@ -133,13 +133,17 @@ func DataSourceDNSTXTTokenRead(
 							resp.WorkflowErrorMessage))
 				}
 			} else {
-				metadata := GetDomainMetadata(resp, svc)
+				metadata := GetDomainMetadata(ctx, resp, svc)
 
 				// No token found.
 				retryErr := CheckForDCVTokenRetry(retry, metadata, statusresp)
+				if retryErr != nil {
+					logger.Debug(ctx, "DNS TXT token not available, retrying")
+				}
+
 				if retryErr == nil && IsDNSTxtTokenPresent(metadata) {
 					// All of the domains have the same token, so take the first.
-					log.Printf("setting token to %s", metadata[0].DcvToken.Token)
+					logger.Debug(ctx, "Found DNS TXT token")
 					d.Set("value", metadata[0].DcvToken.Token)
 					d.SetId(helper.GetUnixTimeStamp())
 				}
@@ -166,17 +170,13 @@ func CheckForDCVTokenRetry(
 		return nil
 	}
 
-	log.Println("token not availale")
-
 	if doRetry {
-		log.Println("retrying")
 		return resource.RetryableError(
 			errors.New("token not available"))
 	}
 
 	// Just exit if retry is not desired.
 	// The user will need to run refresh to try again.
-	log.Println("not retrying")
 	return nil
 }
 
@@ -6,7 +6,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"strings"
 	"time"
 
@@ -66,8 +65,6 @@ func DataSourceTargetCNAMERead(
 		return diag.Errorf("invalid wait_timeout: %v", err)
 	}
 
-	log.Printf("timeout: %v\n", timeout)
-
 	config, ok := m.(internal.ProviderConfig)
 	if !ok {
 		return diag.Errorf("failed to load configuration")
@@ -84,13 +81,18 @@ func DataSourceTargetCNAMERead(
 	}
 
 	// Call APIs.
-	log.Printf("[INFO] Retrieving certificate : ID: %d\n", certID)
+	logger.Info(ctx, "Retrieving certificate", map[string]any{
+		"certificate_id": certID,
+	})
 
 	params := certificate.NewCertificateGetParams()
 	params.ID = certID
 
 	retry := d.Get("wait_until_available").(bool)
-	log.Printf("wait_until_available: %t\n", retry)
+	logger.Debug(ctx, "Waiting for target CNAME", map[string]any{
+		"wait_until_available": retry,
+		"wait_timeout":         timeout.String(),
+	})
 
 	err = resource.RetryContext(
 		ctx,
@@ -127,6 +129,10 @@ func DataSourceTargetCNAMERead(
 
 				// No target cname found.
 				retryErr := CheckForCNAMERetry(retry, deployment)
+				if retryErr != nil {
+					logger.Debug(ctx, "Target CNAME not available, retrying")
+				}
+
 				if retryErr == nil && deployment != nil {
 					d.Set("value", deployment.HexURL)
 					d.SetId(helper.GetUnixTimeStamp())
@@ -151,17 +157,12 @@ func CheckForCNAMERetry(
 		return nil
 	}
 
-	log.Println("target cname not availale")
-
 	if doRetry {
-		log.Println("retrying")
 		return resource.RetryableError(
 			errors.New("target cname not available"))
 	}
 
 	// Just exit if retry is not desired.
 	// The user will need to run refresh to try again.
-	log.Println("not retrying")
-
 	return nil
 }
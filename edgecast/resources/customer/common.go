@@ -10,25 +10,54 @@ import (
 	"github.com/EdgeCast/ec-sdk-go/edgecast/customer"
 )
 
-// buildCustomerService builds the SDK Customer service to manage Customer
-// resources
+// logger writes to the customer logging subsystem.
+var logger = internal.Logger(internal.SubsystemCustomer)
+
+// customerAPI is the part of the SDK Customer service used by customer
+// resources, so that tests can replace it through
+// ProviderConfig.ServiceOverrides.
+type customerAPI interface {
+	AddCustomer(params customer.AddCustomerParams) (string, error)
+	GetCustomer(params customer.GetCustomerParams) (*customer.CustomerGetOK, error)
+	DeleteCustomer(params customer.DeleteCustomerParams) error
+
+	GetAvailableCustomerServices() (*[]customer.Service, error)
+	GetCustomerServices(
+		params customer.GetCustomerServicesParams,
+	) (*[]customer.Service, error)
+	UpdateCustomerServices(params customer.UpdateCustomerServicesParams) error
+
+	GetCustomerDeliveryRegion(
+		params customer.GetCustomerDeliveryRegionParams,
+	) (*customer.DeliveryRegion, error)
+	UpdateCustomerDeliveryRegion(
+		params customer.UpdateCustomerDeliveryRegionParams,
+	) error
+
+	GetCustomerAccessModules(
+		params customer.GetCustomerAccessModulesParams,
+	) (*[]customer.AccessModule, error)
+	UpdateCustomerAccessModule(
+		params customer.UpdateCustomerAccessModuleParams,
+	) error
+
+	AddCustomerUser(params customer.AddCustomerUserParams) (int, error)
+	GetCustomerUser(
+		params customer.GetCustomerUserParams,
+	) (*customer.CustomerUserGetOK, error)
+	UpdateCustomerUser(params customer.UpdateCustomerUserParams) error
+	DeleteCustomerUser(params customer.DeleteCustomerUserParams) error
+}
+
+// buildCustomerService returns the shared SDK Customer service to manage
+// Customer resources
 func buildCustomerService(
 	config internal.ProviderConfig,
-) (*customer.CustomerService, error) {
-
-	idsCredentials := edgecast.IDSCredentials{
-		ClientID:     config.IdsClientID,
-		ClientSecret: config.IdsClientSecret,
-		Scope:        config.IdsScope,
-	}
-
-	sdkConfig := edgecast.NewSDKConfig()
-	sdkConfig.APIToken = config.APIToken
-	sdkConfig.IDSCredentials = idsCredentials
-	sdkConfig.BaseAPIURL = *config.APIURL
-	sdkConfig.BaseAPIURLLegacy = *config.APIURLLegacy
-	sdkConfig.BaseIDSURL = *config.IdsURL
-	sdkConfig.UserAgent = config.UserAgent
-
-	return customer.New(sdkConfig)
+) (customerAPI, error) {
+	return internal.GetService(
+		config,
+		"customer",
+		func(c edgecast.SDKConfig) (customerAPI, error) {
+			return customer.New(c)
+		})
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
 	"github.com/EdgeCast/ec-sdk-go/edgecast/customer"
@@ -25,6 +26,7 @@ func ResourceCustomer() *schema.Resource {
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
+		Timeouts: internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"company_name": {
@@ -254,7 +256,7 @@ func ResourceCustomerCreate(
 	getCustomerParams.AccountNumber = accountNumber
 	customerObj, err := customerService.GetCustomer(*getCustomerParams)
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
 	// Enable Services defined for newly created Customer
@@ -324,7 +326,9 @@ func ResourceCustomerRead(
 	var diags diag.Diagnostics
 
 	accountNumber := d.Id()
-	fmt.Printf("GetCustomer>>[CustomerID]:%s", accountNumber)
+	logger.Info(ctx, "Retrieving customer", map[string]any{
+		"account_number": accountNumber,
+	})
 
 	// Initialize Customer Service
 	config := m.(internal.ProviderConfig)
@@ -474,7 +478,9 @@ func ResourceCustomerDelete(
 	var diags diag.Diagnostics
 
 	accountNumber := d.Id()
-	fmt.Printf("DeleteCustomer>>[CustomerID]:%s", accountNumber)
+	logger.Info(ctx, "Deleting customer", map[string]any{
+		"account_number": accountNumber,
+	})
 
 	// Initialize Customer Service
 	config := m.(internal.ProviderConfig)
@@ -6,7 +6,6 @@ package customer
 import (
 	"context"
 	"fmt"
-	"log"
 	"strconv"
 
 	"terraform-provider-edgecast/edgecast/helper"
@@ -25,6 +24,7 @@ func ResourceCustomerUser() *schema.Resource {
 		UpdateContext: ResourceCustomerUserUpdate,
 		DeleteContext: ResourceCustomerUserDelete,
 		Importer:      helper.Import(ResourceCustomerUserRead, "account_number", "id"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
@@ -73,10 +73,9 @@ func ResourceCustomerUserCreate(
 ) diag.Diagnostics {
 
 	accountNumber := d.Get("account_number").(string)
-	log.Printf(
-		"[INFO] Creating customer user for [Account Number]: %s",
-		accountNumber,
-	)
+	logger.Info(ctx, "Creating customer user", map[string]any{
+		"account_number": accountNumber,
+	})
 
 	// Initialize Customer Service
 	config := m.(internal.ProviderConfig)
@@ -91,8 +90,7 @@ func ResourceCustomerUserCreate(
 	getCustomerParams.AccountNumber = accountNumber
 	customerObj, err := customerService.GetCustomer(*getCustomerParams)
 	if err != nil {
-		d.SetId("")
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
 	// Call Add Customer User API
@@ -106,11 +104,10 @@ func ResourceCustomerUserCreate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Created [Customer User]: %d for [Account Number]: %s",
-		customerUserID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Created customer user", map[string]any{
+		"account_number":   accountNumber,
+		"customer_user_id": customerUserID,
+	})
 
 	d.SetId(strconv.Itoa(customerUserID))
 
@@ -130,11 +127,10 @@ func ResourceCustomerUserUpdate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Updating [Customer User]: %d for [Account Number]: %s",
-		customerUserID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Updating customer user", map[string]any{
+		"account_number":   accountNumber,
+		"customer_user_id": customerUserID,
+	})
 
 	// Initialize Customer Service
 	config := m.(internal.ProviderConfig)
@@ -204,10 +200,10 @@ func ResourceCustomerUserRead(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Retreiving [Customer User]: %d for [Account Number]: %s",
-		customerUserID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Retrieving customer user", map[string]any{
+		"account_number":   accountNumber,
+		"customer_user_id": customerUserID,
+	})
 
 	// Initialize Customer Service
 	config := m.(internal.ProviderConfig)
@@ -231,7 +227,7 @@ func ResourceCustomerUserRead(
 	customerUser, err := customerService.GetCustomerUser(*getCustUserParams)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
 	// Process special is_admin field
@@ -293,11 +289,10 @@ func ResourceCustomerUserDelete(
 		return diags
 	}
 
-	log.Printf(
-		"[INFO] Deleting [Customer User]: %d for [Account Number]: %s",
-		customerUserID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Deleting customer user", map[string]any{
+		"account_number":   accountNumber,
+		"customer_user_id": customerUserID,
+	})
 
 	// Initialize Customer Service
 	config := m.(internal.ProviderConfig)
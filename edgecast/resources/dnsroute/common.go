@@ -10,25 +10,64 @@ import (
 	"github.com/EdgeCast/ec-sdk-go/edgecast/routedns"
 )
 
-// buildRouteDNSService builds the SDK Route DNS service to manage DNS
+// logger writes to the DNS logging subsystem.
+var logger = internal.Logger(internal.SubsystemDNS)
+
+// routeDNSAPI is the part of the SDK Route DNS service used by DNS
+// resources, so that tests can replace it through
+// ProviderConfig.ServiceOverrides.
+type routeDNSAPI interface {
+	AddZone(params routedns.AddZoneParams) (*int, error)
+	GetZone(params routedns.GetZoneParams) (*routedns.ZoneGetOK, error)
+	UpdateZone(params routedns.UpdateZoneParams) error
+	DeleteZone(params routedns.DeleteZoneParams) error
+
+	AddGroup(params routedns.AddGroupParams) (*int, error)
+	GetGroup(params routedns.GetGroupParams) (*routedns.DnsRouteGroupOK, error)
+	UpdateGroup(params *routedns.UpdateGroupParams) error
+	DeleteGroup(params routedns.DeleteGroupParams) error
+
+	AddTSIG(params routedns.AddTSIGParams) (*int, error)
+	GetTSIG(params routedns.GetTSIGParams) (*routedns.TSIGGetOK, error)
+	UpdateTSIG(params routedns.UpdateTSIGParams) error
+	DeleteTSIG(params routedns.DeleteTSIGParams) error
+
+	AddMasterServerGroup(
+		params routedns.AddMasterServerGroupParams,
+	) (*routedns.MasterServerGroupAddGetOK, error)
+	GetMasterServerGroup(
+		params routedns.GetMasterServerGroupParams,
+	) (*routedns.MasterServerGroupAddGetOK, error)
+	UpdateMasterServerGroup(
+		params routedns.UpdateMasterServerGroupParams,
+	) error
+	DeleteMasterServerGroup(
+		params routedns.DeleteMasterServerGroupParams,
+	) error
+
+	AddSecondaryZoneGroup(
+		params routedns.AddSecondaryZoneGroupParams,
+	) (*routedns.SecondaryZoneGroupResponseOK, error)
+	GetSecondaryZoneGroup(
+		params routedns.GetSecondaryZoneGroupParams,
+	) (*routedns.SecondaryZoneGroupResponseOK, error)
+	UpdateSecondaryZoneGroup(
+		params routedns.UpdateSecondaryZoneGroupParams,
+	) error
+	DeleteSecondaryZoneGroup(
+		params routedns.DeleteSecondaryZoneGroupParams,
+	) error
+}
+
+// buildRouteDNSService returns the shared SDK Route DNS service to manage DNS
 // resources
 func buildRouteDNSService(
 	config internal.ProviderConfig,
-) (*routedns.RouteDNSService, error) {
-
-	idsCredentials := edgecast.IDSCredentials{
-		ClientID:     config.IdsClientID,
-		ClientSecret: config.IdsClientSecret,
-		Scope:        config.IdsScope,
-	}
-
-	sdkConfig := edgecast.NewSDKConfig()
-	sdkConfig.APIToken = config.APIToken
-	sdkConfig.IDSCredentials = idsCredentials
-	sdkConfig.BaseAPIURL = *config.APIURL
-	sdkConfig.BaseAPIURLLegacy = *config.APIURLLegacy
-	sdkConfig.BaseIDSURL = *config.IdsURL
-	sdkConfig.UserAgent = config.UserAgent
-
-	return routedns.New(sdkConfig)
+) (routeDNSAPI, error) {
+	return internal.GetService(
+		config,
+		"routedns",
+		func(c edgecast.SDKConfig) (routeDNSAPI, error) {
+			return routedns.New(c)
+		})
 }
@@ -256,14 +256,17 @@ func ResourceGroup() *schema.Resource {
 		UpdateContext: ResourceGroupUpdate,
 		DeleteContext: ResourceGroupDelete,
 		Importer:      helper.Import(ResourceGroupRead, "account_number", "id", "group_product_type"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				Description: `Account Number associated with the customer whose 
 				resources you wish to manage. This account number may be found 
-				in the upper right-hand corner of the MCC.`,
+				in the upper right-hand corner of the MCC. Defaults to the provider's
+				account_number.`,
 				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			"group_id": {
@@ -479,7 +482,7 @@ func ResourceGroupRead(
 	resp, err := routeDNSService.GetGroup(*params)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
 	// Update Terraform state with retrieved Group data
@@ -0,0 +1,226 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package dnsroute
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast/routedns"
+	"github.com/hashicorp/go-retryablehttp"
+)
+
+// Paths, relative to an account's DNS path, of the Route DNS endpoints that
+// list all of the account's objects of a kind. The SDK only retrieves objects
+// by ID.
+const (
+	listZonesPath               = "routezones"
+	listGroupsPath              = "routegroups"
+	listTSIGsPath               = "tsigs"
+	listSecondaryZoneGroupsPath = "secondarygroup"
+)
+
+// ListedObject is a Route DNS object as listed by the API.
+type ListedObject struct {
+	ID   int
+	Name string
+
+	// GroupProductType is the group_product_type of a group, i.e. failover or
+	// loadbalancing, and empty for other objects.
+	GroupProductType string
+}
+
+// Zones returns the primary zones of an account, named by their domain.
+func Zones(
+	config internal.ProviderConfig,
+	accountNumber string,
+) ([]ListedObject, error) {
+	var zones []struct {
+		ZoneID     int    `json:"ZoneId"`
+		DomainName string `json:"DomainName"`
+	}
+
+	err := newRouteListClient(config).send(
+		"GetAllZones",
+		accountNumber,
+		listZonesPath,
+		&zones)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ListedObject, 0, len(zones))
+	for _, z := range zones {
+		objects = append(objects, ListedObject{ID: z.ZoneID, Name: z.DomainName})
+	}
+
+	return objects, nil
+}
+
+// Groups returns the load balancing and failover groups of an account that
+// do not belong to a zone.
+func Groups(
+	config internal.ProviderConfig,
+	accountNumber string,
+) ([]ListedObject, error) {
+	var groups []struct {
+		GroupID          int                       `json:"GroupId"`
+		Name             string                    `json:"Name"`
+		GroupProductType routedns.GroupProductType `json:"GroupProductTypeId"`
+	}
+
+	err := newRouteListClient(config).send(
+		"GetAllGroups",
+		accountNumber,
+		listGroupsPath,
+		&groups)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ListedObject, 0, len(groups))
+	for _, g := range groups {
+		productType := ""
+		switch g.GroupProductType {
+		case routedns.LoadBalancing:
+			productType = "loadbalancing"
+		case routedns.Failover:
+			productType = "failover"
+		default:
+			continue
+		}
+
+		objects = append(objects, ListedObject{
+			ID:               g.GroupID,
+			Name:             g.Name,
+			GroupProductType: productType,
+		})
+	}
+
+	return objects, nil
+}
+
+// TSIGs returns the TSIG keys of an account, named by their alias.
+func TSIGs(
+	config internal.ProviderConfig,
+	accountNumber string,
+) ([]ListedObject, error) {
+	var keys []routedns.TSIGGetOK
+
+	err := newRouteListClient(config).send(
+		"GetAllTSIGs",
+		accountNumber,
+		listTSIGsPath,
+		&keys)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ListedObject, 0, len(keys))
+	for _, k := range keys {
+		objects = append(objects, ListedObject{ID: k.ID, Name: k.Alias})
+	}
+
+	return objects, nil
+}
+
+// SecondaryZoneGroups returns the secondary zone groups of an account. The
+// endpoint that retrieves a group by its id query parameter lists them all
+// when the parameter is omitted.
+func SecondaryZoneGroups(
+	config internal.ProviderConfig,
+	accountNumber string,
+) ([]ListedObject, error) {
+	var groups []routedns.SecondaryZoneGroupResponseOK
+
+	err := newRouteListClient(config).send(
+		"GetAllSecondaryZoneGroups",
+		accountNumber,
+		listSecondaryZoneGroupsPath,
+		&groups)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ListedObject, 0, len(groups))
+	for _, g := range groups {
+		objects = append(objects, ListedObject{ID: g.ID, Name: g.Name})
+	}
+
+	return objects, nil
+}
+
+// routeListClient sends the Route DNS list requests. Its HTTP client has the
+// provider's HTTP settings, see ProviderConfig.NewRetryableClient, and it
+// authenticates with the provider's API token, as the SDK's Route DNS
+// service does.
+type routeListClient struct {
+	baseURL   url.URL
+	userAgent string
+	token     string
+	client    *retryablehttp.Client
+}
+
+func newRouteListClient(config internal.ProviderConfig) *routeListClient {
+	c := config.NewSDKConfig()
+
+	return &routeListClient{
+		baseURL:   c.BaseAPIURLLegacy,
+		userAgent: c.UserAgent,
+		token:     config.APIToken,
+		client:    config.NewRetryableClient(),
+	}
+}
+
+// send GETs path, relative to the DNS path of accountNumber, and decodes the
+// response into out. op names the operation in errors.
+func (c *routeListClient) send(
+	op string,
+	accountNumber string,
+	path string,
+	out any,
+) error {
+	u := c.baseURL.JoinPath("v2/mcc/customers", accountNumber, "dns", path)
+
+	req, err := retryablehttp.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Authorization", "TOK:"+c.token)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Errors are worded like the SDK's, so that helpers such as
+	// helper.IsNotFound recognize them.
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"%s: sendRequest failed (HTTP StatusCode:%d): %s",
+			op,
+			resp.StatusCode,
+			body)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package dnsroute
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal"
+	"terraform-provider-edgecast/test/fakeapi"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestListedObjects(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	apiURL, err := url.Parse(s.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	config := internal.ProviderConfig{
+		APIToken:     fakeapi.APIToken,
+		APIURLLegacy: apiURL,
+	}
+
+	d := schema.TestResourceDataRaw(t, ResourceTsig().Schema, map[string]any{
+		"account_number": "ABCD",
+		"alias":          "tf-keys",
+		"key_name":       "key1",
+		"key_value":      "HFNASHDJJKQWHKJ1234",
+		"algorithm_name": "HMAC-SHA512",
+	})
+	if diags := ResourceTsigCreate(ctx, d, config); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	id, err := strconv.Atoi(d.Id())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		list func(internal.ProviderConfig, string) ([]ListedObject, error)
+		want []ListedObject
+	}{
+		{
+			name: "TSIG Keys",
+			list: TSIGs,
+			want: []ListedObject{{ID: id, Name: "tf-keys"}},
+		},
+		{
+			name: "Zones",
+			list: Zones,
+			want: []ListedObject{},
+		},
+		{
+			name: "Groups",
+			list: Groups,
+			want: []ListedObject{},
+		},
+		{
+			name: "Secondary Zone Groups",
+			list: SecondaryZoneGroups,
+			want: []ListedObject{},
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.list(config, "ABCD")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -5,8 +5,6 @@ package dnsroute
 
 import (
 	"context"
-	"encoding/json"
-	"log"
 	"strconv"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
@@ -25,14 +23,17 @@ func ResourceMasterServerGroup() *schema.Resource {
 		UpdateContext: ResourceMSGUpdate,
 		DeleteContext: ResourceMSGDelete,
 		Importer:      helper.Import(ResourceMSGRead, "account_number", "id"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				Description: `Account Number associated with the customer whose 
 				resources you wish to manage. This account number may be found 
-				in the upper right-hand corner of the MCC.`,
+				in the upper right-hand corner of the MCC. Defaults to the provider's
+				account_number.`,
 				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			"master_group_id": {
@@ -113,11 +114,10 @@ func ResourceMSGCreate(
 	}
 
 	// Call Add Master Server Group API
-	log.Printf(
-		"[INFO] Creating a new Master Server Group for Account '%s': %+v",
-		accountNumber,
-		masterServerGroup,
-	)
+	logger.Info(ctx, "Creating Master Server Group", map[string]any{
+		"account_number":      accountNumber,
+		"master_server_group": masterServerGroup,
+	})
 
 	params := routedns.NewAddMasterServerGroupParams()
 	params.AccountNumber = accountNumber
@@ -129,10 +129,9 @@ func ResourceMSGCreate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Create successful - New Master Server Group ID: %d",
-		resp.MasterGroupID,
-	)
+	logger.Info(ctx, "Created Master Server Group", map[string]any{
+		"master_group_id": resp.MasterGroupID,
+	})
 	d.SetId(strconv.Itoa(resp.MasterGroupID))
 
 	return ResourceMSGRead(ctx, d, m)
@@ -162,16 +161,20 @@ func ResourceMSGRead(
 	params.AccountNumber = accountNumber
 	params.MasterServerGroupID = groupID
 
-	log.Printf("[INFO] Retrieving Master Server Group by GroupID: %d", groupID)
+	logger.Info(ctx, "Retrieving Master Server Group", map[string]any{
+		"master_group_id": groupID,
+	})
 
 	resp, err := routeDNSService.GetMasterServerGroup(*params)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
 	// Update Terraform state with retrieved Master Server Group data
-	log.Printf("[INFO] Retrieved Master Server Group: %+v", resp)
+	logger.Debug(ctx, "Retrieved Master Server Group", map[string]any{
+		"master_server_group": resp,
+	})
 	msg := flattenMasterServers(*resp)
 	newId := strconv.Itoa(resp.MasterGroupID)
 
@@ -179,8 +182,6 @@ func ResourceMSGRead(
 	d.Set("account_number", accountNumber)
 	d.Set("master_group_id", resp.MasterGroupID)
 	d.Set("master_server_group_name", resp.Name)
-	jsonMsg, _ := json.Marshal(msg)
-	log.Printf("master_server_group>>ResourceMSGRead>>msg:%s", jsonMsg)
 	d.Set("masters", msg)
 
 	return diag.Diagnostics{}
@@ -248,10 +249,9 @@ func ResourceMSGDelete(
 	}
 
 	// Call Get Master Server Group API
-	log.Printf(
-		"[INFO] Retrieving Master Server Group by GroupID for deletion: %d",
-		msgID,
-	)
+	logger.Info(ctx, "Retrieving Master Server Group for deletion", map[string]any{
+		"master_group_id": msgID,
+	})
 
 	getParams := routedns.NewGetMasterServerGroupParams()
 	getParams.AccountNumber = accountNumber
@@ -5,7 +5,6 @@ package dnsroute
 
 import (
 	"context"
-	"log"
 	"strconv"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
@@ -24,14 +23,17 @@ func ResourceSecondaryZoneGroup() *schema.Resource {
 		UpdateContext: ResourceSecondaryZoneGroupUpdate,
 		DeleteContext: ResourceSecondaryZoneGroupDelete,
 		Importer:      helper.Import(ResourceSecondaryZoneGroupRead, "account_number", "id"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				Description: `Account Number associated with the customer whose 
 				resources you wish to manage. This account number may be found 
-				in the upper right-hand corner of the MCC.`,
+				in the upper right-hand corner of the MCC. Defaults to the provider's
+				account_number.`,
 				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			"name": {
@@ -179,11 +181,10 @@ func ResourceSecondaryZoneGroupCreate(
 	}
 
 	// Call Create Secondary Zone Group API
-	log.Printf(
-		"[INFO] Creating a new Secondary Zone Group for Account '%s': %+v",
-		accountNumber,
-		secondaryZoneGroup,
-	)
+	logger.Info(ctx, "Creating Secondary Zone Group", map[string]any{
+		"account_number":       accountNumber,
+		"secondary_zone_group": secondaryZoneGroup,
+	})
 	params := routedns.NewAddSecondaryZoneGroupParams()
 	params.AccountNumber = accountNumber
 	params.SecondaryZoneGroup = secondaryZoneGroup
@@ -195,10 +196,9 @@ func ResourceSecondaryZoneGroupCreate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Create successful - New Secondary Zone Group ID: %d",
-		resp.ID,
-	)
+	logger.Info(ctx, "Created Secondary Zone Group", map[string]any{
+		"secondary_zone_group_id": resp.ID,
+	})
 
 	d.SetId(strconv.Itoa(resp.ID))
 
@@ -228,18 +228,19 @@ func ResourceSecondaryZoneGroupRead(
 	params.AccountNumber = accountNumber
 	params.ID = secondaryZoneGroupID
 
-	log.Printf(
-		"[INFO] Retrieving Secondary Zone Group by zoneID: %d",
-		secondaryZoneGroupID,
-	)
+	logger.Info(ctx, "Retrieving Secondary Zone Group", map[string]any{
+		"secondary_zone_group_id": secondaryZoneGroupID,
+	})
 
 	resp, err := routeDNSService.GetSecondaryZoneGroup(*params)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
-	log.Printf("[INFO] Retrieved Secondary Zone Group: %+v", resp)
+	logger.Debug(ctx, "Retrieved Secondary Zone Group", map[string]any{
+		"secondary_zone_group": resp,
+	})
 
 	// Update Terraform state with retrieved Secondary Zone Group data
 	newID := strconv.Itoa(resp.ID)
@@ -5,7 +5,6 @@ package dnsroute
 
 import (
 	"context"
-	"log"
 	"strconv"
 	"strings"
 	"terraform-provider-edgecast/edgecast/helper"
@@ -24,14 +23,17 @@ func ResourceTsig() *schema.Resource {
 		UpdateContext: ResourceTsigUpdate,
 		DeleteContext: ResourceTsigDelete,
 		Importer:      helper.Import(ResourceTsigRead, "account_number", "id"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				Description: `Account Number associated with the customer whose 
 				resources you wish to manage. This account number may be found 
-				in the upper right-hand corner of the MCC.`,
+				in the upper right-hand corner of the MCC. Defaults to the provider's
+				account_number.`,
 				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			"alias": {
@@ -48,8 +50,9 @@ func ResourceTsig() *schema.Resource {
 				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			"key_value": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:      schema.TypeString,
+				Required:  true,
+				Sensitive: true,
 				Description: `Identifies a hash value through which our name 
 				servers will be authenticated to a master name server.`,
 				ValidateFunc: validation.StringIsNotWhiteSpace,
@@ -83,11 +86,10 @@ func ResourceTsigCreate(
 	// Construct TSIG Object
 	tsig := expandTSIG(d)
 
-	log.Printf(
-		"[INFO] Creating a new TSIG for Account '%s': %+v",
-		accountNumber,
-		tsig,
-	)
+	logger.Info(ctx, "Creating TSIG", map[string]any{
+		"account_number": accountNumber,
+		"tsig":           tsig,
+	})
 
 	// Call add TSIG API
 	params := routedns.NewAddTSIGParams()
@@ -101,7 +103,7 @@ func ResourceTsigCreate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Create successful - New TSIG ID: %d", tsigID)
+	logger.Info(ctx, "Created TSIG", map[string]any{"tsig_id": *tsigID})
 	d.SetId(strconv.Itoa(*tsigID))
 
 	return ResourceTsigRead(ctx, d, m)
@@ -122,7 +124,7 @@ func ResourceTsigRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	}
 
 	// Call get TSIG API
-	log.Printf("[INFO] Retrieving TSIG by TSIGID: %d", tsigID)
+	logger.Info(ctx, "Retrieving TSIG", map[string]any{"tsig_id": tsigID})
 	params := routedns.NewGetTSIGParams()
 	params.AccountNumber = accountNumber
 	params.TSIGID = tsigID
@@ -130,10 +132,10 @@ func ResourceTsigRead(ctx context.Context, d *schema.ResourceData, m interface{}
 	tsigObj, err := routeDNSService.GetTSIG(*params)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
-	log.Printf("[INFO] Retrieved TSIG %+v", tsigObj)
+	logger.Debug(ctx, "Retrieved TSIG", map[string]any{"tsig": tsigObj})
 	d.Set("alias", tsigObj.Alias)
 	d.Set("key_name", tsigObj.KeyName)
 	d.Set("key_value", tsigObj.KeyValue)
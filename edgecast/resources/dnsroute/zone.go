@@ -341,14 +341,17 @@ func ResourceZone() *schema.Resource {
 		UpdateContext: ResourceZoneUpdate,
 		DeleteContext: ResourceZoneDelete,
 		Importer:      helper.Import(ResourceZoneRead, "account_number", "id"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				Description: `Account Number associated with the customer whose 
 				resources you wish to manage. This account number may be found 
-				in the upper right-hand corner of the MCC.`,
+				in the upper right-hand corner of the MCC. Defaults to the provider's
+				account_number.`,
 				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			"zone_type": {
@@ -714,7 +717,7 @@ func ResourceZoneRead(
 	zoneObj, err := routeDNSService.GetZone(*params)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
 	// Update Terraform state with retrieved Zone data
@@ -0,0 +1,47 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package dnsroute
+
+import (
+	"context"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal/recorder"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestResourceZoneUpdate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rec := recorder.New(t, "dnsroute/zone_update")
+	zoneID := rec.Var("zone_id")
+	meta := rec.ProviderConfig()
+
+	d := schema.TestResourceDataRaw(t, ResourceZone().Schema, map[string]any{
+		"account_number": rec.Var("account_number"),
+	})
+	d.SetId(zoneID)
+
+	if diags := ResourceZoneRead(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	d = recorder.UpdateData(t, ResourceZone(), d, map[string]any{
+		"comment": "updated by test",
+	})
+
+	if diags := ResourceZoneUpdate(ctx, d, meta); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+
+	if d.Id() != zoneID {
+		t.Errorf("got ID %s, want %s", d.Id(), zoneID)
+	}
+
+	if got := d.Get("comment").(string); got != "updated by test" {
+		t.Errorf("got comment %q, want %q", got, "updated by test")
+	}
+}
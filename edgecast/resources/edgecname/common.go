@@ -10,25 +10,30 @@ import (
 	"github.com/EdgeCast/ec-sdk-go/edgecast/edgecname"
 )
 
-// buildEdgeCnameService builds the SDK Edge CNAME service to manage Edge CNAME
-// resources
-func buildEdgeCnameService(
-	config internal.ProviderConfig,
-) (*edgecname.EdgeCnameService, error) {
-
-	idsCredentials := edgecast.IDSCredentials{
-		ClientID:     config.IdsClientID,
-		ClientSecret: config.IdsClientSecret,
-		Scope:        config.IdsScope,
-	}
+// logger writes to the Edge CNAME logging subsystem.
+var logger = internal.Logger(internal.SubsystemEdgeCname)
 
-	sdkConfig := edgecast.NewSDKConfig()
-	sdkConfig.APIToken = config.APIToken
-	sdkConfig.IDSCredentials = idsCredentials
-	sdkConfig.BaseAPIURL = *config.APIURL
-	sdkConfig.BaseAPIURLLegacy = *config.APIURLLegacy
-	sdkConfig.BaseIDSURL = *config.IdsURL
-	sdkConfig.UserAgent = config.UserAgent
+// edgeCnameAPI is the part of the SDK Edge CNAME service used by Edge
+// CNAME resources, so that tests can replace it through
+// ProviderConfig.ServiceOverrides.
+type edgeCnameAPI interface {
+	AddEdgeCname(params edgecname.AddEdgeCnameParams) (*int, error)
+	GetEdgeCname(
+		params edgecname.GetEdgeCnameParams,
+	) (*edgecname.EdgeCnameGetOK, error)
+	UpdateEdgeCname(params edgecname.UpdateEdgeCnameParams) (*int, error)
+	DeleteEdgeCname(params edgecname.DeleteEdgeCnameParams) error
+}
 
-	return edgecname.New(sdkConfig)
+// buildEdgeCnameService returns the shared SDK Edge CNAME service to manage
+// Edge CNAME resources
+func buildEdgeCnameService(
+	config internal.ProviderConfig,
+) (edgeCnameAPI, error) {
+	return internal.GetService(
+		config,
+		"edgecname",
+		func(c edgecast.SDKConfig) (edgeCnameAPI, error) {
+			return edgecname.New(c)
+		})
 }
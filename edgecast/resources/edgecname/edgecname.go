@@ -6,7 +6,6 @@ package edgecname
 import (
 	"context"
 	"errors"
-	"log"
 	"strconv"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
@@ -25,6 +24,7 @@ func ResourceEdgeCname() *schema.Resource {
 		UpdateContext: ResourceEdgeCnameUpdate,
 		DeleteContext: ResourceEdgeCnameDelete,
 		Importer:      helper.Import(ResourceEdgeCnameRead, "account_number", "id"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 
 		CustomizeDiff: customdiff.ValidateChange(
 			"media_type_id",
@@ -53,8 +53,9 @@ func ResourceEdgeCname() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "Identifies your account. Find your account number in the upper right-hand corner of the MCC.",
+				Optional:     true,
+				Computed:     true,
+				Description:  "Identifies your account. Find your account number in the upper right-hand corner of the MCC. Defaults to the provider's `account_number`.",
 				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			"name": {
@@ -124,11 +125,10 @@ func ResourceEdgeCnameCreate(
 	}
 
 	// Call Add Edge CNAME API
-	log.Printf(
-		"[INFO] Creating Edge CNAME for Account '%s': %+v",
-		accountNumber,
-		edgecnameObj,
-	)
+	logger.Info(ctx, "Creating Edge CNAME", map[string]any{
+		"account_number": accountNumber,
+		"edge_cname":     edgecnameObj,
+	})
 
 	params := edgecname.NewAddEdgeCnameParams()
 	params.AccountNumber = accountNumber
@@ -141,7 +141,9 @@ func ResourceEdgeCnameCreate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Create successful - New Edge CNAME ID: %d", *edgeCnameID)
+	logger.Info(ctx, "Created Edge CNAME", map[string]any{
+		"edge_cname_id": *edgeCnameID,
+	})
 	d.SetId(strconv.Itoa(*edgeCnameID))
 
 	return ResourceEdgeCnameRead(ctx, d, m)
@@ -163,11 +165,10 @@ func ResourceEdgeCnameRead(
 	// Call Get EDGE CNAME API
 	edgecnameID, _ := strconv.Atoi(d.Id())
 
-	log.Printf(
-		"[INFO] Retriving Edge CNAME for Account '%s': CNAME ID: %v",
-		accountNumber,
-		edgecnameID,
-	)
+	logger.Info(ctx, "Retrieving Edge CNAME", map[string]any{
+		"account_number": accountNumber,
+		"edge_cname_id":  edgecnameID,
+	})
 
 	params := edgecname.NewGetEdgeCnameParams()
 	params.AccountNumber = accountNumber
@@ -175,10 +176,12 @@ func ResourceEdgeCnameRead(
 	edgecnameObj, err := edgecnameService.GetEdgeCname(*params)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
-	log.Printf("[INFO] Retrieved Edge CNAME: %+v", edgecnameObj)
+	logger.Debug(ctx, "Retrieved Edge CNAME", map[string]any{
+		"edge_cname": edgecnameObj,
+	})
 
 	// Update Terraform state with retrieved Edge CNAME data
 	d.Set("name", edgecnameObj.Name)
@@ -223,12 +226,11 @@ func ResourceEdgeCnameUpdate(
 	edgecnameObj.OriginID = d.Get("origin_id").(int)
 
 	// Call Update Edge CNAME API
-	log.Printf(
-		"[INFO] Updating Edge CNAME for Account '%s': Edge CNAME ID %d, Body %+v",
-		accountNumber,
-		edgecnameID,
-		edgecnameObj,
-	)
+	logger.Info(ctx, "Updating Edge CNAME", map[string]any{
+		"account_number": accountNumber,
+		"edge_cname_id":  edgecnameID,
+		"edge_cname":     edgecnameObj,
+	})
 
 	updateEdgecnameParams := edgecname.NewUpdateEdgeCnameParams()
 	updateEdgecnameParams.AccountNumber = accountNumber
@@ -239,7 +241,9 @@ func ResourceEdgeCnameUpdate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Updated Edge CNAME ID: %v", edgecnameID)
+	logger.Info(ctx, "Updated Edge CNAME", map[string]any{
+		"edge_cname_id": edgecnameID,
+	})
 
 	return ResourceEdgeCnameRead(ctx, d, m)
 }
@@ -268,11 +272,10 @@ func ResourceEdgeCnameDelete(
 	}
 
 	// Call Delete EDGE CNAME API
-	log.Printf(
-		"[INFO] Deleting Edge CNAME for Account '%s': CNAME ID: %v",
-		accountNumber,
-		edgecnameID,
-	)
+	logger.Info(ctx, "Deleting Edge CNAME", map[string]any{
+		"account_number": accountNumber,
+		"edge_cname_id":  edgecnameID,
+	})
 
 	deleteEdgecnameParams := edgecname.NewDeleteEdgeCnameParams()
 	deleteEdgecnameParams.AccountNumber = accountNumber
@@ -283,7 +286,9 @@ func ResourceEdgeCnameDelete(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Deleted Edge CNAME ID: %v", edgecnameID)
+	logger.Info(ctx, "Deleted Edge CNAME", map[string]any{
+		"edge_cname_id": edgecnameID,
+	})
 
 	d.SetId("")
 
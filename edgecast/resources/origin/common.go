@@ -10,25 +10,31 @@ import (
 	"github.com/EdgeCast/ec-sdk-go/edgecast/origin"
 )
 
-// buildOriginService builds the SDK Origin service to manage Origin
+// logger writes to the origin logging subsystem.
+var logger = internal.Logger(internal.SubsystemOrigin)
+
+// originAPI is the part of the SDK Origin service used by origin
+// resources, so that tests can replace it through
+// ProviderConfig.ServiceOverrides.
+type originAPI interface {
+	AddOrigin(params origin.AddOriginParams) (*int, error)
+	GetAllOrigins(
+		params origin.GetAllOriginsParams,
+	) (*[]origin.OriginGetOK, error)
+	GetOrigin(params origin.GetOriginParams) (*origin.OriginGetOK, error)
+	UpdateOrigin(params origin.UpdateOriginParams) (*int, error)
+	DeleteOrigin(params origin.DeleteOriginParams) error
+}
+
+// buildOriginService returns the shared SDK Origin service to manage Origin
 // resources
 func buildOriginService(
 	config internal.ProviderConfig,
-) (*origin.OriginService, error) {
-
-	idsCredentials := edgecast.IDSCredentials{
-		ClientID:     config.IdsClientID,
-		ClientSecret: config.IdsClientSecret,
-		Scope:        config.IdsScope,
-	}
-
-	sdkConfig := edgecast.NewSDKConfig()
-	sdkConfig.APIToken = config.APIToken
-	sdkConfig.IDSCredentials = idsCredentials
-	sdkConfig.BaseAPIURL = *config.APIURL
-	sdkConfig.BaseAPIURLLegacy = *config.APIURLLegacy
-	sdkConfig.BaseIDSURL = *config.IdsURL
-	sdkConfig.UserAgent = config.UserAgent
-
-	return origin.New(sdkConfig)
+) (originAPI, error) {
+	return internal.GetService(
+		config,
+		"origin",
+		func(c edgecast.SDKConfig) (originAPI, error) {
+			return origin.New(c)
+		})
 }
@@ -6,7 +6,6 @@ package origin
 import (
 	"context"
 	"errors"
-	"log"
 	"strconv"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
@@ -60,15 +59,23 @@ func ResourceOrigin() *schema.Resource {
 		ReadContext:   ResourceOriginRead,
 		UpdateContext: ResourceOriginUpdate,
 		DeleteContext: ResourceOriginDelete,
-		Importer:      helper.Import(ResourceOriginRead, "account_number", "id", "media_type_id"),
+		Importer: helper.ImportWithLookup(
+			ResourceOriginRead,
+			lookupOrigin,
+			"account_number",
+			"id",
+			"media_type_id"),
+		Timeouts: internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:     schema.TypeString,
-				Required: true,
+				Optional: true,
+				Computed: true,
 				Description: `Account Number associated with the customer whose 
 				origins you wish to manage. This account number may be found in 
-				the upper right-hand corner of the MCC.`,
+				the upper right-hand corner of the MCC. Defaults to the provider's
+				account_number.`,
 				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			"directory_name": {
@@ -237,7 +244,7 @@ func ResourceOriginCreate(
 		originObj.ShieldPOPs = *shieldPOPs
 	}
 
-	log.Printf("resourceOriginCreate>>origin object:%v\n", originObj)
+	logger.Debug(ctx, "Creating origin", map[string]any{"origin": originObj})
 
 	// Initialize Origin Service
 	originService, err := buildOriginService(config)
@@ -290,7 +297,7 @@ func ResourceOriginRead(
 	parsedResponse, err := originService.GetOrigin(*params)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
 	d.Set("directory_name", parsedResponse.DirectoryName)
@@ -379,7 +386,7 @@ func ResourceOriginUpdate(
 		originObj.ShieldPOPs = *shieldPOPs
 	}
 
-	log.Printf("resourceOriginUpdate>>origin object:%v\n", originObj)
+	logger.Debug(ctx, "Updating origin", map[string]any{"origin": originObj})
 
 	// Call Create Origin API
 	updateParams := origin.NewUpdateOriginParams()
@@ -512,3 +519,39 @@ func flattenShieldPOP(shieldPOPs []origin.ShieldPOP) []map[string]interface{} {
 	}
 	return flattened
 }
+
+// lookupOrigin returns the ID of the origin whose directory name is name,
+// among the origins of the imported media type.
+func lookupOrigin(
+	_ context.Context,
+	d *schema.ResourceData,
+	m interface{},
+	name string,
+) (string, error) {
+	accountNumber := d.Get("account_number").(string)
+	mediaTypeID := d.Get("media_type_id").(int)
+	if len(accountNumber) == 0 || mediaTypeID == 0 {
+		return "", errors.New(
+			"account_number and media_type_id are required to import an " +
+				"origin by name")
+	}
+
+	config := m.(internal.ProviderConfig)
+	originService, err := buildOriginService(config)
+	if err != nil {
+		return "", err
+	}
+
+	params := origin.NewGetAllOriginsParams()
+	params.AccountNumber = accountNumber
+	params.MediaTypeID = enums.Platform(mediaTypeID)
+
+	resp, err := originService.GetAllOrigins(*params)
+	if err != nil {
+		return "", err
+	}
+
+	return helper.FindIDByName("origin", name, *resp,
+		func(o origin.OriginGetOK) string { return o.DirectoryName },
+		func(o origin.OriginGetOK) string { return strconv.Itoa(o.ID) })
+}
@@ -8,30 +8,15 @@ import (
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
-	"github.com/EdgeCast/ec-sdk-go/edgecast"
 	"github.com/EdgeCast/ec-sdk-go/edgecast/originv3"
 )
 
-// buildOriginV3Service builds the SDK OriginV3 service to manage Origin Groups
-// and Origin resources.
+// buildOriginV3Service returns the shared SDK OriginV3 service to manage Origin
+// Groups and Origin resources.
 func buildOriginV3Service(
 	config internal.ProviderConfig,
 ) (*originv3.Service, error) {
-	idsCredentials := edgecast.IDSCredentials{
-		ClientID:     config.IdsClientID,
-		ClientSecret: config.IdsClientSecret,
-		Scope:        config.IdsScope,
-	}
-
-	sdkConfig := edgecast.NewSDKConfig()
-	sdkConfig.APIToken = config.APIToken
-	sdkConfig.IDSCredentials = idsCredentials
-	sdkConfig.BaseAPIURL = *config.APIURL
-	sdkConfig.BaseAPIURLLegacy = *config.APIURLLegacy
-	sdkConfig.BaseIDSURL = *config.IdsURL
-	sdkConfig.UserAgent = config.UserAgent
-
-	return originv3.New(sdkConfig)
+	return internal.GetService(config, "originv3", originv3.New)
 }
 
 // expandTLSSettings converts the Terraform representation of TLS Settings
@@ -5,14 +5,12 @@ package originv3
 
 import (
 	"context"
-	"log"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
 	"github.com/EdgeCast/ec-sdk-go/edgecast/originv3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/kr/pretty"
 )
 
 func DataSourceHostnameResolutionMethods() *schema.Resource {
@@ -67,9 +65,9 @@ func DataSourceHostnameResolutionMethodsRead(
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Retrieved Available Hostname Resolution Methods: %# v",
-		pretty.Formatter(resp))
+	logger.Debug(ctx, "Retrieved hostname resolution methods", map[string]any{
+		"response": resp,
+	})
 
 	flattened := flattenHostnameResolutionMethods(resp)
 	d.Set("items", flattened)
@@ -5,14 +5,12 @@ package originv3
 
 import (
 	"context"
-	"log"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
 	"github.com/EdgeCast/ec-sdk-go/edgecast/originv3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/kr/pretty"
 )
 
 func DataSourceOriginShieldPops() *schema.Resource {
@@ -121,9 +119,9 @@ func DataSourceOriginShieldPopsRead(
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Retrieved Origin Shield Pops: %# v",
-		pretty.Formatter(resp))
+	logger.Debug(ctx, "Retrieved origin shield POPs", map[string]any{
+		"response": resp,
+	})
 
 	flattened := flattenOriginShieldEdgeNodes(resp)
 	d.Set("items", flattened)
@@ -5,14 +5,12 @@ package originv3
 
 import (
 	"context"
-	"log"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
 	"github.com/EdgeCast/ec-sdk-go/edgecast/originv3"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/kr/pretty"
 )
 
 func DataSourceProtocolTypes() *schema.Resource {
@@ -62,9 +60,9 @@ func DataSourceProtocolTypesRead(
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Retrieved Protocol Types: %# v",
-		pretty.Formatter(resp))
+	logger.Debug(ctx, "Retrieved protocol types", map[string]any{
+		"response": resp,
+	})
 
 	flattened := flattenProtocolTypes(resp)
 	d.Set("items", flattened)
@@ -7,7 +7,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"strconv"
 	"sync"
 	"terraform-provider-edgecast/edgecast/helper"
@@ -18,9 +17,11 @@ import (
 	"github.com/ahmetalpbalkan/go-linq"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/kr/pretty"
 )
 
+// logger writes to the origin v3 logging subsystem.
+var logger = internal.Logger(internal.SubsystemOriginV3)
+
 func ResourceOriginGrpHttpLarge() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: ResourceOriginGroupCreate,
@@ -28,6 +29,7 @@ func ResourceOriginGrpHttpLarge() *schema.Resource {
 		UpdateContext: ResourceOriginGroupUpdate,
 		DeleteContext: ResourceOriginGroupDelete,
 		Importer:      helper.Import(ResourceOriginGroupRead, "id"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 		Schema:        GetOriginGrpHttpLargeSchema(),
 	}
 }
@@ -69,15 +71,17 @@ func ResourceOriginGroupCreate(
 	if err != nil {
 		return helper.CreationError(d, err)
 	}
-	log.Printf("[INFO] origin group created: %# v\n", pretty.Formatter(cresp))
-	log.Printf("[INFO] origin group id: %d\n", cresp.Id)
+	logger.Info(ctx, "Created origin group", map[string]any{
+		"origin_group_id": cresp.Id,
+		"response":        cresp,
+	})
 
 	grpID := cresp.Id
 
 	failoverOrders := make([]originv3.FailoverOrder, 0)
 
 	mlock := &sync.Mutex{}
-	wg := sync.WaitGroup{}
+	wg := config.NewWorkerGroup()
 	if len(originGroupState.Origins) > 0 {
 		errs := make([]error, 0)
 		for _, origin := range originGroupState.Origins {
@@ -96,15 +100,13 @@ func ResourceOriginGroupCreate(
 			originFailoverOrder := origin.FailoverOrder
 
 			// Spin up a worker to call the api.
-			wg.Add(1)
-
-			go func(params originv3.AddOriginParams) {
-				defer wg.Done()
-
+			wg.Go(func() {
 				resp, err := svc.Common.AddOrigin(params)
 				if err == nil {
 					mlock.Lock()
-					log.Printf("[INFO] origin created: %# v\n", pretty.Formatter(resp))
+					logger.Info(ctx, "Created origin", map[string]any{
+						"origin_id": *resp.Id,
+					})
 					failoverOrder := originv3.FailoverOrder{
 						Id:            *resp.Id,
 						Host:          *resp.Host,
@@ -117,7 +119,7 @@ func ResourceOriginGroupCreate(
 					errs = append(errs, err)
 					mlock.Unlock()
 				}
-			}(params)
+			})
 		}
 		// Wait for all api workers to finish.
 		wg.Wait()
@@ -175,16 +177,20 @@ func ResourceOriginGroupRead(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Retrieving origin group : ID: %d\n", grpID)
+	logger.Info(ctx, "Retrieving origin group", map[string]any{
+		"origin_group_id": grpID,
+	})
 	// call APIs
 	params := originv3.NewGetHttpLargeGroupParams()
 	params.GroupId = int32(grpID)
 
 	resp, err := svc.HttpLargeOnly.GetHttpLargeGroup(params)
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
-	log.Printf("[INFO] Retrieved origin group: %# v\n", pretty.Formatter(resp))
+	logger.Debug(ctx, "Retrieved origin group", map[string]any{
+		"origin_group": resp,
+	})
 
 	originsParams := originv3.NewGetOriginsByGroupParams()
 	originsParams.GroupId = int32(grpID)
@@ -194,7 +200,9 @@ func ResourceOriginGroupRead(
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	log.Printf("[INFO] Retrieved origins: %# v\n", pretty.Formatter(originsResp))
+	logger.Debug(ctx, "Retrieved origins", map[string]any{
+		"origins": originsResp,
+	})
 
 	// Write TF state.
 	err = setHttpLargeOriginGroupState(d, resp, originsResp)
@@ -231,11 +239,13 @@ func ResourceOriginGroupUpdate(
 	}
 	originGroupState.ID = int32(grpID)
 
-	log.Printf("[INFO] Updating origin group : ID: %d\n", grpID)
+	logger.Info(ctx, "Updating origin group", map[string]any{
+		"origin_group_id": grpID,
+	})
 	errs := make([]error, 0)
 	failoverOrders := make([]originv3.FailoverOrder, 0)
 	mlock := &sync.Mutex{}
-	wg := sync.WaitGroup{}
+	wg := config.NewWorkerGroup()
 
 	updateParams := originv3.NewUpdateHttpLargeGroupParams()
 	updateParams.GroupId = int32(grpID)
@@ -250,19 +260,18 @@ func ResourceOriginGroupUpdate(
 		}
 
 	// Spin up a worker to call the api.
-	wg.Add(1)
-	go func(updateParams originv3.UpdateHttpLargeGroupParams) {
-		defer wg.Done()
-
+	wg.Go(func() {
 		_, err = svc.HttpLargeOnly.UpdateHttpLargeGroup(updateParams)
 		if err == nil {
-			log.Printf("[INFO] Updated origin group info: ID: %d\n", grpID)
+			logger.Info(ctx, "Updated origin group info", map[string]any{
+				"origin_group_id": grpID,
+			})
 		} else {
 			mlock.Lock()
 			errs = append(errs, err)
 			mlock.Unlock()
 		}
-	}(updateParams)
+	})
 
 	//update Origins
 	if d.HasChange("origin") {
@@ -276,6 +285,12 @@ func ResourceOriginGroupUpdate(
 		toDelete := getOriginsToDelete(newOrigins, oldOrigins)
 		toUpdate := getOriginsToUpdate(newOrigins, oldOrigins)
 
+		logger.Debug(ctx, "Computed origin changes", map[string]any{
+			"to_add":    toAdd,
+			"to_delete": toDelete,
+			"to_update": toUpdate,
+		})
+
 		//add new origins
 		if len(toAdd) > 0 {
 			for _, v := range toAdd {
@@ -292,14 +307,13 @@ func ResourceOriginGroupUpdate(
 				}
 				originFailoverOrder := v.FailoverOrder
 
-				wg.Add(1)
-				go func(params originv3.AddOriginParams) {
-					defer wg.Done()
-
+				wg.Go(func() {
 					resp, err := svc.Common.AddOrigin(params)
 					if err == nil {
 						mlock.Lock()
-						log.Printf("[INFO] Added origin: ID: %d\n", *resp.Id)
+						logger.Info(ctx, "Added origin", map[string]any{
+							"origin_id": *resp.Id,
+						})
 						failoverOrder := originv3.FailoverOrder{
 							Id:            *resp.Id,
 							Host:          *resp.Host,
@@ -312,7 +326,7 @@ func ResourceOriginGroupUpdate(
 						errs = append(errs, err)
 						mlock.Unlock()
 					}
-				}(params)
+				})
 			}
 		}
 
@@ -322,19 +336,18 @@ func ResourceOriginGroupUpdate(
 				params.Id = v.ID
 				params.MediaType = enums.HttpLarge.String()
 
-				wg.Add(1)
-				go func(params originv3.DeleteOriginParams) {
-					defer wg.Done()
-
+				wg.Go(func() {
 					err := svc.Common.DeleteOrigin(params)
 					if err == nil {
-						log.Printf("[INFO] Deleted origin: ID: %d\n", params.Id)
+						logger.Info(ctx, "Deleted origin", map[string]any{
+							"origin_id": params.Id,
+						})
 					} else {
 						mlock.Lock()
 						errs = append(errs, err)
 						mlock.Unlock()
 					}
-				}(params)
+				})
 			}
 		}
 
@@ -354,14 +367,13 @@ func ResourceOriginGroupUpdate(
 				}
 				originFailoverOrder := v.FailoverOrder
 
-				wg.Add(1)
-				go func(params originv3.UpdateOriginParams) {
-					defer wg.Done()
-
+				wg.Go(func() {
 					resp, err := svc.Common.UpdateOrigin(params)
 					if err == nil {
 						mlock.Lock()
-						log.Printf("[INFO] Updated origin: ID: %d\n", params.Id)
+						logger.Info(ctx, "Updated origin", map[string]any{
+							"origin_id": params.Id,
+						})
 						failoverOrder := originv3.FailoverOrder{
 							Id:            *resp.Id,
 							Host:          *resp.Host,
@@ -374,7 +386,7 @@ func ResourceOriginGroupUpdate(
 						errs = append(errs, err)
 						mlock.Unlock()
 					}
-				}(params)
+				})
 			}
 		}
 	}
@@ -397,7 +409,9 @@ func ResourceOriginGroupUpdate(
 	if len(errs) > 0 {
 		return helper.DiagsFromErrors("error updating origin group", errs)
 	}
-	log.Printf("[INFO] Updated origin group ID: %v", grpID)
+	logger.Info(ctx, "Updated origin group", map[string]any{
+		"origin_group_id": grpID,
+	})
 
 	return ResourceOriginGroupRead(ctx, d, m)
 }
@@ -431,7 +445,9 @@ func ResourceOriginGroupDelete(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Deleted origin group id: %v", grpID)
+	logger.Info(ctx, "Deleted origin group", map[string]any{
+		"origin_group_id": grpID,
+	})
 	d.SetId("")
 
 	return diag.Diagnostics{}
@@ -549,7 +565,6 @@ func getOriginsToAdd(
 		return (c.(*OriginState))
 	}).ToSlice(&toAdd)
 
-	log.Printf("[INFO] toadd: %# v\n", pretty.Formatter(toAdd))
 	return toAdd
 }
 
@@ -565,7 +580,6 @@ func getOriginsToDelete(
 			func(c interface{}) interface{} { return c.(*OriginState).ID },
 		).ToSlice(&toDelete)
 
-	log.Printf("[INFO] toadd: %# v\n", pretty.Formatter(toDelete))
 	return toDelete
 }
 
@@ -582,7 +596,6 @@ func getOriginsToUpdate(
 		return (c.(*OriginState))
 	}).ToSlice(&toUpdate)
 
-	log.Printf("[INFO] toupdate: %# v\n", pretty.Formatter(toUpdate))
 	return toUpdate
 }
 
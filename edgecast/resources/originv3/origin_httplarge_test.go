@@ -4,9 +4,12 @@
 package originv3
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
+	"terraform-provider-edgecast/edgecast/internal/recorder"
+
 	"github.com/EdgeCast/ec-sdk-go/edgecast/originv3"
 	"github.com/go-test/deep"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -919,3 +922,38 @@ func TestGetOriginToUpdate(t *testing.T) {
 		}
 	}
 }
+
+func TestResourceOriginGroupUpdate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	rec := recorder.New(t, "originv3/origin_group_update")
+	groupID := rec.Var("group_id")
+	meta := rec.ProviderConfig()
+
+	d := schema.TestResourceDataRaw(
+		t,
+		ResourceOriginGrpHttpLarge().Schema,
+		map[string]any{})
+	d.SetId(groupID)
+
+	if diags := ResourceOriginGroupRead(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	d = recorder.UpdateData(t, ResourceOriginGrpHttpLarge(), d, map[string]any{
+		"host_header": "updated.example.com",
+	})
+
+	if diags := ResourceOriginGroupUpdate(ctx, d, meta); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+
+	if got := d.Get("host_header").(string); got != "updated.example.com" {
+		t.Errorf("got host_header %q, want %q", got, "updated.example.com")
+	}
+
+	if len(d.Get("origin").([]any)) == 0 {
+		t.Error("expected origins to be set")
+	}
+}
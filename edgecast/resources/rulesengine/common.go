@@ -10,23 +10,43 @@ import (
 	"github.com/EdgeCast/ec-sdk-go/edgecast/rulesengine"
 )
 
-// buildRulesEngineService builds the SDK Rules Engine service to manage Rule
-// resources
-func buildRulesEngineService(
-	config internal.ProviderConfig,
-) (*rulesengine.RulesEngineService, error) {
+// logger writes to the Rules Engine logging subsystem.
+var logger = internal.Logger(internal.SubsystemRulesEngine)
+
+// rulesEngineAPI is the part of the SDK Rules Engine service used by Rules
+// Engine resources, so that tests can replace it through
+// ProviderConfig.ServiceOverrides.
+type rulesEngineAPI interface {
+	AddPolicy(
+		params rulesengine.AddPolicyParams,
+	) (*rulesengine.PolicyResponse, error)
+
+	GetPolicy(
+		params rulesengine.GetPolicyParams,
+	) (map[string]interface{}, error)
 
-	idsCredentials := edgecast.IDSCredentials{
-		ClientID:     config.IdsClientID,
-		ClientSecret: config.IdsClientSecret,
-		Scope:        config.IdsScope,
-	}
+	SubmitDeployRequest(
+		params rulesengine.SubmitDeployRequestParams,
+	) (*rulesengine.DeployRequestOK, error)
 
-	sdkConfig := edgecast.NewSDKConfig()
-	sdkConfig.IDSCredentials = idsCredentials
-	sdkConfig.BaseAPIURL = *config.APIURL
-	sdkConfig.BaseIDSURL = *config.IdsURL
-	sdkConfig.UserAgent = config.UserAgent
+	GetDeployRequest(
+		params getDeployRequestParams,
+	) (*rulesengine.DeployRequestOK, error)
 
-	return rulesengine.New(sdkConfig)
+	GetDeployRequests(
+		params listDeployRequestsParams,
+	) ([]deployRequestSummary, error)
+}
+
+// buildRulesEngineService returns the shared SDK Rules Engine service to manage
+// Rule resources
+func buildRulesEngineService(
+	config internal.ProviderConfig,
+) (rulesEngineAPI, error) {
+	return internal.GetService(
+		config,
+		"rulesengine",
+		func(c edgecast.SDKConfig) (rulesEngineAPI, error) {
+			return newRulesEngineService(c, config)
+		})
 }
@@ -0,0 +1,507 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package rulesengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/rulesengine"
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+// Final deploy request states. Deploy requests in any other state, e.g.
+// submitted or pending_review, are still in progress.
+const (
+	deployStateDeployed = "deployed"
+	deployStateRejected = "rejected"
+	deployStateCanceled = "canceled"
+)
+
+// getDeployRequestParams identifies a deploy request, and the customer on
+// whose behalf it is retrieved.
+type getDeployRequestParams struct {
+	ID             string
+	AccountNumber  string
+	CustomerUserID string
+	PortalTypeID   string
+	OwnerID        string
+}
+
+// listDeployRequestsParams identifies the customer whose deploy requests are
+// listed.
+type listDeployRequestsParams struct {
+	AccountNumber  string
+	CustomerUserID string
+	PortalTypeID   string
+	OwnerID        string
+}
+
+// deployRequestSummary is a deploy request as listed by the API, with only the
+// fields needed to find the policy it deployed.
+type deployRequestSummary struct {
+	ID          string    `json:"id"`
+	State       string    `json:"state"`
+	Environment string    `json:"environment"`
+	CreatedAt   time.Time `json:"created_at"`
+	Policies    struct {
+		ID       string `json:"id"`
+		Platform string `json:"platform"`
+	} `json:"policies"`
+}
+
+// rulesEngineService is the SDK Rules Engine service, with the deploy request
+// lookups that the SDK does not provide.
+type rulesEngineService struct {
+	*rulesengine.RulesEngineService
+	deployRequests *deployRequestClient
+}
+
+func newRulesEngineService(
+	c edgecast.SDKConfig,
+	config internal.ProviderConfig,
+) (*rulesEngineService, error) {
+	svc, err := rulesengine.New(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rulesEngineService{
+		RulesEngineService: svc,
+		deployRequests:     newDeployRequestClient(c, config),
+	}, nil
+}
+
+// SDKService returns the wrapped SDK service, so that the provider's HTTP
+// settings are applied to it.
+func (s *rulesEngineService) SDKService() any {
+	return s.RulesEngineService
+}
+
+// GetDeployRequest returns a deploy request, including its state.
+func (s *rulesEngineService) GetDeployRequest(
+	params getDeployRequestParams,
+) (*rulesengine.DeployRequestOK, error) {
+	return s.deployRequests.get(params)
+}
+
+// GetDeployRequests returns the deploy requests of a customer.
+func (s *rulesEngineService) GetDeployRequests(
+	params listDeployRequestsParams,
+) ([]deployRequestSummary, error) {
+	return s.deployRequests.list(params)
+}
+
+// deployRequestClient retrieves deploy requests from the Rules Engine API. Its
+// HTTP client has the provider's HTTP settings, see
+// ProviderConfig.NewRetryableClient, and it authenticates with the token of
+// the SDK's services, see ProviderConfig.AuthorizationProvider.
+type deployRequestClient struct {
+	baseURL   url.URL
+	userAgent string
+	client    *retryablehttp.Client
+	auth      internal.AuthorizationProvider
+}
+
+func newDeployRequestClient(
+	c edgecast.SDKConfig,
+	config internal.ProviderConfig,
+) *deployRequestClient {
+	return &deployRequestClient{
+		baseURL:   c.BaseAPIURL,
+		userAgent: c.UserAgent,
+		client:    config.NewRetryableClient(),
+		auth:      config.AuthorizationProvider(),
+	}
+}
+
+func (c *deployRequestClient) get(
+	params getDeployRequestParams,
+) (*rulesengine.DeployRequestOK, error) {
+	deployRequest := &rulesengine.DeployRequestOK{}
+	err := c.send(
+		"GetDeployRequest",
+		c.baseURL.JoinPath("rules-engine/v1.1/deploy-requests", params.ID),
+		listDeployRequestsParams{
+			AccountNumber:  params.AccountNumber,
+			CustomerUserID: params.CustomerUserID,
+			PortalTypeID:   params.PortalTypeID,
+			OwnerID:        params.OwnerID,
+		},
+		deployRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	return deployRequest, nil
+}
+
+// maxDeployRequestPages limits the pages of deploy requests that are listed,
+// in case the API keeps linking to further pages.
+const maxDeployRequestPages = 1000
+
+// list returns all deploy requests of a customer, following the API's links
+// from each page of deploy requests to the next.
+func (c *deployRequestClient) list(
+	params listDeployRequestsParams,
+) ([]deployRequestSummary, error) {
+	var items []deployRequestSummary
+
+	u := c.baseURL.JoinPath("rules-engine/v1.1/deploy-requests")
+	seen := make(map[string]bool)
+
+	for page := 0; page < maxDeployRequestPages; page++ {
+		seen[u.String()] = true
+
+		var collection struct {
+			Items []deployRequestSummary `json:"@items"`
+			Links collectionLinks        `json:"@links"`
+		}
+
+		err := c.send("GetDeployRequests", u, params, &collection)
+		if err != nil {
+			return nil, err
+		}
+
+		items = append(items, collection.Items...)
+
+		next, ok := collection.Links.next()
+		if !ok || len(collection.Items) == 0 {
+			return items, nil
+		}
+
+		nextURL, err := u.Parse(next)
+		if err != nil {
+			return nil, fmt.Errorf("GetDeployRequests: next page: %w", err)
+		}
+
+		if seen[nextURL.String()] {
+			return items, nil
+		}
+
+		u = nextURL
+	}
+
+	return nil, fmt.Errorf(
+		"GetDeployRequests: more than %d pages of deploy requests",
+		maxDeployRequestPages)
+}
+
+// collectionLinks holds the links of a page of a collection. They are listed
+// either by relation, e.g. {"next": {"href": "..."}}, or as an array, e.g.
+// [{"rel": "next", "href": "..."}].
+type collectionLinks map[string]string
+
+func (l *collectionLinks) UnmarshalJSON(b []byte) error {
+	type link struct {
+		Rel  string `json:"rel"`
+		Href string `json:"href"`
+		ID   string `json:"@id"`
+	}
+
+	links := make(collectionLinks)
+
+	var list []link
+	if err := json.Unmarshal(b, &list); err == nil {
+		for _, v := range list {
+			links[v.Rel] = firstNonEmpty(v.Href, v.ID)
+		}
+
+		*l = links
+		return nil
+	}
+
+	var byRel map[string]json.RawMessage
+	if err := json.Unmarshal(b, &byRel); err != nil {
+		return err
+	}
+
+	for rel, raw := range byRel {
+		var v link
+		if err := json.Unmarshal(raw, &v); err == nil {
+			links[rel] = firstNonEmpty(v.Href, v.ID)
+			continue
+		}
+
+		var href string
+		if err := json.Unmarshal(raw, &href); err == nil {
+			links[rel] = href
+		}
+	}
+
+	*l = links
+	return nil
+}
+
+// next returns the link to the next page, if any.
+func (l collectionLinks) next() (string, bool) {
+	next := l["next"]
+	return next, len(next) > 0
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if len(v) > 0 {
+			return v
+		}
+	}
+
+	return ""
+}
+
+// send GETs u on behalf of the customer in params and decodes the response
+// into out. op names the operation in errors.
+func (c *deployRequestClient) send(
+	op string,
+	u *url.URL,
+	params listDeployRequestsParams,
+	out any,
+) error {
+	headers, err := portalsHeaders(
+		params.AccountNumber,
+		params.CustomerUserID,
+		params.PortalTypeID,
+		params.OwnerID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	authorization, err := c.auth.GetAuthorizationHeader()
+	if err != nil {
+		return fmt.Errorf("%s: retrieving IDS token: %w", op, err)
+	}
+
+	req, err := retryablehttp.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	req.Header.Set("Authorization", authorization)
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Errors are worded like the SDK's, so that helpers such as
+	// helper.IsNotFound recognize them.
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf(
+			"%s: sendRequest failed (HTTP StatusCode:%d): %s",
+			op,
+			resp.StatusCode,
+			body)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// portalsHeaders returns the headers that identify the customer of a Rules
+// Engine request, as the SDK sends them.
+func portalsHeaders(
+	accountNumber string,
+	customerUserID string,
+	portalTypeID string,
+	ownerID string,
+) (map[string]string, error) {
+	headers := make(map[string]string)
+
+	if len(accountNumber) > 0 {
+		// account number hex string -> customer ID
+		customerID, err := strconv.ParseInt(accountNumber, 16, 64)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing Hex account number: %w", err)
+		}
+		headers["Portals_CustomerId"] = strconv.FormatInt(customerID, 10)
+	}
+
+	if len(customerUserID) > 0 {
+		headers["Portals_UserId"] = customerUserID
+	}
+
+	if len(portalTypeID) > 0 {
+		headers["Portals_PortalTypeId"] = portalTypeID
+	}
+
+	if len(ownerID) > 0 {
+		headers["x-owner-id"] = ownerID
+	}
+
+	return headers, nil
+}
+
+// waitForDeploy polls a deploy request until it is deployed, rejected or
+// canceled, and returns its last state. Deploy requests that are not deployed
+// are returned as errors.
+func waitForDeploy(
+	ctx context.Context,
+	svc rulesEngineAPI,
+	params getDeployRequestParams,
+	timeout time.Duration,
+) (string, error) {
+	// The last poll may still be running when the timeout expires.
+	var mu sync.Mutex
+	var lastState string
+
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		deployRequest, err := svc.GetDeployRequest(params)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+
+		state := deployRequest.State
+		mu.Lock()
+		lastState = state
+		mu.Unlock()
+
+		logger.Debug(ctx, "Checked deploy request", map[string]any{
+			"deploy_request_id": params.ID,
+			"state":             state,
+		})
+
+		switch state {
+		case deployStateDeployed:
+			return nil
+		case deployStateRejected, deployStateCanceled:
+			return resource.NonRetryableError(fmt.Errorf(
+				"deploy request %s was %s", params.ID, state))
+		default:
+			return resource.RetryableError(fmt.Errorf(
+				"deploy request %s is %s", params.ID, state))
+		}
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	return lastState, err
+}
+
+// activePolicyID returns the ID of the policy most recently deployed to
+// environment on platform, according to deployRequests, or "" if none was.
+func activePolicyID(
+	deployRequests []deployRequestSummary,
+	environment string,
+	platform string,
+) string {
+	var latest *deployRequestSummary
+	for i := range deployRequests {
+		r := &deployRequests[i]
+		if r.State != deployStateDeployed ||
+			r.Environment != environment ||
+			r.Policies.Platform != platform ||
+			len(r.Policies.ID) == 0 {
+			continue
+		}
+
+		// Requests submitted in the same second are listed in order.
+		if latest == nil || !r.CreatedAt.Before(latest.CreatedAt) {
+			latest = r
+		}
+	}
+
+	if latest == nil {
+		return ""
+	}
+
+	return latest.Policies.ID
+}
+
+// DeployedPolicy is the policy most recently deployed to an environment and
+// platform.
+type DeployedPolicy struct {
+	ID          string
+	Name        string
+	Environment string
+	Platform    string
+}
+
+// DeployedPolicies returns the policies in effect for a customer, one for each
+// environment and platform that a policy was deployed to. The API cannot list
+// policies, so they are found through the customer's deploy requests.
+func DeployedPolicies(
+	config internal.ProviderConfig,
+	accountNumber string,
+) ([]DeployedPolicy, error) {
+	svc, err := buildRulesEngineService(config)
+	if err != nil {
+		return nil, fmt.Errorf("DeployedPolicies: buildRulesEngineService: %w", err)
+	}
+
+	deployRequests, err := svc.GetDeployRequests(
+		listDeployRequestsParams{AccountNumber: accountNumber})
+	if err != nil {
+		return nil, err
+	}
+
+	type target struct{ environment, platform string }
+
+	var targets []target
+	seen := make(map[target]bool)
+	for _, r := range deployRequests {
+		t := target{r.Environment, r.Policies.Platform}
+		if !seen[t] {
+			seen[t] = true
+			targets = append(targets, t)
+		}
+	}
+
+	var policies []DeployedPolicy
+	for _, t := range targets {
+		id := activePolicyID(deployRequests, t.environment, t.platform)
+		if len(id) == 0 {
+			continue
+		}
+
+		policyID, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, fmt.Errorf("parsing policy ID %q: %w", id, err)
+		}
+
+		params := rulesengine.NewGetPolicyParams()
+		params.AccountNumber = accountNumber
+		params.PolicyID = policyID
+
+		policy, err := svc.GetPolicy(*params)
+		if err != nil {
+			return nil, err
+		}
+
+		policies = append(policies, DeployedPolicy{
+			ID:          id,
+			Name:        toString(policy["name"]),
+			Environment: t.environment,
+			Platform:    t.platform,
+		})
+	}
+
+	return policies, nil
+}
@@ -7,7 +7,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 	"reflect"
 	"strconv"
 	"strings"
@@ -18,6 +17,7 @@ import (
 
 	"github.com/EdgeCast/ec-sdk-go/edgecast/rulesengine"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -28,13 +28,28 @@ const (
 	jsonkeyMatches    string = "matches"
 )
 
+// Destroy modes, which determine what happens to the deployed policy when the
+// resource is destroyed.
+const (
+	destroyModePlaceholder = "placeholder"
+	destroyModeRollback    = "rollback"
+	destroyModeAbandon     = "abandon"
+)
+
 func ResourceRulesEngineV4Policy() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: ResourcePolicyCreate,
 		ReadContext:   ResourcePolicyRead,
 		UpdateContext: ResourcePolicyUpdate,
 		DeleteContext: ResourcePolicyDelete,
-		Importer:      helper.Import(ResourcePolicyRead, "account_number", "id", "portaltypeid", "customeruserid", "ownerid"),
+		Importer:      helper.Import(ResourcePolicyRead, "account_number", "id", "portaltypeid", "customeruserid", "ownerid", "deploy_to"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
+		CustomizeDiff: customdiff.All(
+			policyChangesCustomizeDiff,
+			previousPolicyCustomizeDiff),
+		ValidateRawResourceConfigFuncs: []schema.ValidateRawResourceConfigFunc{
+			validateRuleBlocks,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"customeruserid": {
@@ -50,7 +65,8 @@ func ResourceRulesEngineV4Policy() *schema.Resource {
 			"account_number": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Reserved for future use.",
+				Computed:    true,
+				Description: "Identifies the account whose policy is managed. Defaults to the provider's `account_number`.",
 			},
 			"ownerid": {
 				Type:        schema.TypeString,
@@ -71,18 +87,78 @@ func ResourceRulesEngineV4Policy() *schema.Resource {
 				Description: "Indicates the system-defined ID for the policy's deploy request.",
 				Computed:    true,
 			},
+			"deploy_status": {
+				Type:     schema.TypeString,
+				Computed: true,
+				Description: "Indicates the state of the policy's deploy request, e.g. `deployed`. " +
+					"Creating or updating the policy waits until its deploy request is " +
+					"deployed, rejected or canceled, within the create or update timeout.",
+			},
+			"destroy_mode": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Default:  destroyModePlaceholder,
+				Description: "Determines what is deployed when the resource is destroyed. Valid values are: \n\n" +
+					"        placeholder | rollback | abandon\n\n" +
+					"`placeholder` deploys an empty placeholder policy, `rollback` redeploys the " +
+					"policy identified by `previous_policy_id` and `abandon` leaves the deployed " +
+					"policy in place. Defaults to `placeholder`. Changing it, or " +
+					"`previous_policy_id`, alone does not deploy the policy again.",
+				ValidateFunc: validation.StringInSlice(
+					[]string{
+						destroyModePlaceholder,
+						destroyModeRollback,
+						destroyModeAbandon,
+					},
+					false),
+			},
+			"previous_policy_id": {
+				Type:     schema.TypeString,
+				Optional: true,
+				Computed: true,
+				Description: "Identifies the policy that `rollback` redeploys. Defaults to the " +
+					"policy that was deployed to the same environment and platform when the " +
+					"resource was created, or when `deploy_to` or the platform last changed.",
+			},
 			"policy": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Defines the policy, in JSON format, that will be deployed.",
-				StateFunc:   cleanPolicyForTerrafomState,
+				Type:         schema.TypeString,
+				Optional:     true,
+				ExactlyOneOf: []string{"policy", "rule"},
+				Description: "Defines the policy, in JSON format, that will be deployed. " +
+					"Either `policy` or `rule` blocks must be set.",
+				StateFunc: cleanPolicyForTerrafomState,
 				ValidateFunc: validation.All(
 					validation.StringIsNotWhiteSpace,
 					validation.StringIsJSON,
 					helper.StringIsNotEmptyJSON,
+					validatePolicyCatalog,
 				),
 				DiffSuppressFunc: policyDiffSuppress,
 			},
+			"platform": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				RequiredWith:  []string{"rule"},
+				ConflictsWith: []string{"policy"},
+				Description: "Identifies the platform of the policy defined by `rule` " +
+					"blocks, e.g. `http_large`.",
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+			},
+			"description": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				ConflictsWith: []string{"policy"},
+				Description:   "Describes the policy defined by `rule` blocks.",
+			},
+			"rule": ruleSchema(),
+			"policy_changes": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+				Description: "Lists the rules added (`+`), removed (`-`) and changed (`~`) " +
+					"by the last planned change to the policy, with the matches and " +
+					"features that changed. Rules are identified by name.",
+			},
 		},
 	}
 }
@@ -93,24 +169,21 @@ func ResourcePolicyCreate(
 	d *schema.ResourceData,
 	m interface{},
 ) diag.Diagnostics {
-	policy := d.Get("policy").(string)
-
 	// messy - needs improvement - unmarshalling json, modifying, then
 	// marshalling back to string state must always be locked
-	policyMap := make(map[string]interface{})
-	err := json.Unmarshal([]byte(policy), &policyMap)
+	policyMap, err := readPolicyConfig(d)
 	if err != nil {
 		return diag.Errorf("error reading policy: %s", err.Error())
 	}
 
 	policyMap["state"] = "locked"
 	if policyMap["name"] != nil {
-		log.Printf(`
-		[WARN] Please remove policy name, "%s" in the policy.
-		It will be ignored. 
-		The policy name would be auto-generated by the provider with following format.
-		tf-[customer_account_id]-[deploy_to]-[platform]-[timestamp(utc)]`,
-			policyMap["name"])
+		logger.Warn(ctx,
+			"Please remove the policy name from the policy. It will be "+
+				"ignored. The policy name is generated by the provider in "+
+				"the format tf-[customer_account_id]-[deploy_to]-[platform]-"+
+				"[timestamp(utc)].",
+			map[string]any{"policy_name": policyMap["name"]})
 	}
 	policyMap["name"] = fmt.Sprintf("tf-%s-%s-%s-%d",
 		d.Get("account_number").(string),
@@ -123,9 +196,16 @@ func ResourcePolicyCreate(
 		return diag.FromErr(err)
 	}
 
-	policy = string(policyBytes)
+	policy := string(policyBytes)
 
-	err = addPolicy(policy, false, d, m)
+	if len(d.Id()) == 0 {
+		err = recordPreviousPolicy(ctx, toString(policyMap["platform"]), d, m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	err = addPolicy(ctx, policy, false, d, m)
 
 	if err != nil {
 		return diag.FromErr(err)
@@ -141,17 +221,20 @@ func ResourcePolicyRead(
 	m interface{},
 ) diag.Diagnostics {
 
-	policy, err := getPolicy(m, d)
-	log.Printf("[INFO] policy : %+v", policy)
-
+	policy, err := getPolicy(ctx, m, d)
 	if err != nil {
-		d.SetId("")
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
 	// set id to policy id from body
 	d.SetId(policy["id"].(string))
 
+	// Policies managed before destroy_mode was added have none in state. Fill
+	// in its default so that they do not plan a change to it.
+	if len(d.Get("destroy_mode").(string)) == 0 {
+		d.Set("destroy_mode", destroyModePlaceholder)
+	}
+
 	// Remove unneeded policy and rule metadata - this metadata interferes with
 	// terraform diffs
 	err = cleanPolicy(policy)
@@ -171,12 +254,24 @@ func ResourcePolicyRead(
 	}
 
 	policyAsString := string(jsonBytes)
-	log.Printf(
-		"[INFO] Successfully retrieved policy %s: %s",
-		d.Id(),
-		policyAsString)
+	logger.Debug(ctx, "Retrieved policy", map[string]any{
+		"policy_id": d.Id(),
+		"policy":    policyAsString,
+	})
+
+	if !usesRuleBlocks(d) {
+		d.Set("policy", policyAsString)
+		return diag.Diagnostics{}
+	}
 
-	d.Set("policy", policyAsString)
+	rules, err := flattenRules(policy["rules"])
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error reading policy rules: %w", err))
+	}
+
+	d.Set("platform", policy["platform"])
+	d.Set("description", toString(policy["description"]))
+	d.Set("rule", rules)
 
 	return diag.Diagnostics{}
 }
@@ -188,19 +283,63 @@ func ResourcePolicyUpdate(
 	d *schema.ResourceData,
 	m interface{},
 ) diag.Diagnostics {
+	// destroy_mode and previous_policy_id only take effect on destroy, so
+	// changing nothing else does not deploy the policy again.
+	if !d.HasChangesExcept("destroy_mode", "previous_policy_id") {
+		return ResourcePolicyRead(ctx, d, m)
+	}
+
+	// Moving the policy to another environment or platform takes over from
+	// the policy deployed there, see previousPolicyCustomizeDiff.
+	if deployTargetChanged(d) &&
+		!previousPolicyConfigured(d.GetRawConfig()) {
+		policyMap, err := readPolicyConfig(d)
+		if err != nil {
+			return diag.Errorf("error reading policy: %s", err.Error())
+		}
+
+		d.Set("previous_policy_id", "")
+		err = recordPreviousPolicy(ctx, toString(policyMap["platform"]), d, m)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
 	return ResourcePolicyCreate(ctx, d, m)
 }
 
-// ResourcePolicyDelete creates a new empty placeholder policy and deploys it to
-// a target platform instead of actual deletion.
+// ResourcePolicyDelete deploys a policy in place of the resource's, depending on
+// destroy_mode, since policies cannot actually be deleted.
 func ResourcePolicyDelete(
 	ctx context.Context,
 	d *schema.ResourceData,
 	m interface{},
 ) diag.Diagnostics {
+	switch d.Get("destroy_mode").(string) {
+	case destroyModeAbandon:
+		logger.Info(ctx, "Leaving the deployed policy in place", map[string]any{
+			"policy_id": d.Id(),
+		})
+		d.SetId("")
+		return diag.Diagnostics{}
+	case destroyModeRollback:
+		previousID := d.Get("previous_policy_id").(string)
+		if len(previousID) == 0 {
+			return diag.Errorf(
+				"no previous policy is known to roll back to: set " +
+					"previous_policy_id, or set destroy_mode to placeholder or " +
+					"abandon")
+		}
+
+		if err := rollbackPolicy(ctx, previousID, d, m); err != nil {
+			return diag.FromErr(err)
+		}
+		return diag.Diagnostics{}
+	}
+
 	// We will retrieve a fresh copy of the policy to prevent
 	// sending an empty policy to the wrong platform
-	policy, err := getPolicy(m, d)
+	policy, err := getPolicy(ctx, m, d)
 
 	if err != nil {
 		return diag.FromErr(err)
@@ -219,7 +358,7 @@ func ResourcePolicyDelete(
 		platform,
 		timestamp)
 
-	err = addPolicy(emptyPolicyJSON, true, d, m)
+	err = addPolicy(ctx, emptyPolicyJSON, true, d, m)
 
 	if err != nil {
 		return diag.FromErr(err)
@@ -356,6 +495,7 @@ func getDeployRequestData(
 }
 
 func getPolicy(
+	ctx context.Context,
 	m interface{},
 	d *schema.ResourceData,
 ) (map[string]interface{}, error) {
@@ -372,7 +512,9 @@ func getPolicy(
 			fmt.Errorf("error parsing Policy ID from state file: %v", err)
 	}
 
-	log.Printf("[INFO] Retrieving policy %d", policyID)
+	logger.Info(ctx, "Retrieving policy", map[string]any{
+		"policy_id": policyID,
+	})
 
 	// Initialize Rules Engine Service
 	rulesengineService, err := buildRulesEngineService(config)
@@ -393,6 +535,7 @@ func getPolicy(
 }
 
 func addPolicy(
+	ctx context.Context,
 	policy string,
 	isEmptyPolicy bool,
 	d *schema.ResourceData,
@@ -400,6 +543,7 @@ func addPolicy(
 ) error {
 	// Retrieve data needed by API calls
 	config := m.(internal.ProviderConfig)
+	existingID := d.Id()
 	accountNumber := d.Get("account_number").(string)
 	customerUserID := d.Get("customeruserid").(string)
 	portalTypeID := d.Get("portaltypeid").(string) // 1=MCC 2=PCC 3=WCC 4=UCC
@@ -432,44 +576,33 @@ func addPolicy(
 
 	if !isEmptyPolicy {
 		d.SetId(parsedResponse.ID)
-		d.Set("policy", policy)
+		if !usesRuleBlocks(d) {
+			d.Set("policy", policy)
+		}
 	}
 
-	deployRequest := getDeployRequestData(d, policyID)
-	log.Printf(
-		"[INFO] Deploying new policy for Account %s: %+v",
-		accountNumber,
-		deployRequest,
-	)
-
-	// Call Submit Deploy Request API
-	deployRequestParams := rulesengine.NewSubmitDeployRequestParams()
-	deployRequestParams.AccountNumber = accountNumber
-	deployRequestParams.CustomerUserID = customerUserID
-	deployRequestParams.PortalTypeID = portalTypeID
-	deployRequestParams.DeployRequest = *deployRequest
-	deployRequestParams.OwnerID = ownerID
-
-	deployResponse, deployErr := rulesengineService.SubmitDeployRequest(
-		*deployRequestParams,
-	)
-
-	if deployErr != nil {
-		log.Printf(
-			"[WARN] Deploying new policy for Account %s failed",
-			accountNumber)
-		return fmt.Errorf("addPolicy: %v\n%v", policyParams, deployErr)
+	// Wait for the policy to go live, so that dependent resources only use it
+	// once it is in effect.
+	timeout := d.Timeout(schema.TimeoutUpdate)
+	if isEmptyPolicy {
+		timeout = d.Timeout(schema.TimeoutDelete)
+	} else if len(existingID) == 0 {
+		timeout = d.Timeout(schema.TimeoutCreate)
 	}
 
-	log.Printf(
-		"[INFO] Successfully deployed new policy for Account %s: %+v",
-		accountNumber,
-		deployResponse)
+	err = deployPolicy(
+		ctx,
+		rulesengineService,
+		policyID,
+		!isEmptyPolicy,
+		timeout,
+		d)
+	if err != nil {
+		return fmt.Errorf("addPolicy: %w", err)
+	}
 
 	if isEmptyPolicy {
 		d.SetId("") // indicates "delete" happened
-	} else {
-		d.Set("deploy_request_id", deployResponse.ID)
 	}
 
 	return nil
@@ -545,3 +678,176 @@ func deleteEmptyRuleNames(policy map[string]interface{}) {
 	// replace with cleaned rules
 	policy["rules"] = cleanedRules
 }
+
+// deployPolicy submits a deploy request for a policy and waits until it is
+// deployed. The deploy request's ID and state are saved if track is true.
+func deployPolicy(
+	ctx context.Context,
+	svc rulesEngineAPI,
+	policyID int,
+	track bool,
+	timeout time.Duration,
+	d *schema.ResourceData,
+) error {
+	accountNumber := d.Get("account_number").(string)
+	customerUserID := d.Get("customeruserid").(string)
+	portalTypeID := d.Get("portaltypeid").(string) // 1=MCC 2=PCC 3=WCC 4=UCC
+	ownerID := d.Get("ownerid").(string)
+
+	deployRequest := getDeployRequestData(d, policyID)
+	logger.Info(ctx, "Deploying policy", map[string]any{
+		"account_number": accountNumber,
+		"deploy_request": deployRequest,
+	})
+
+	// Call Submit Deploy Request API
+	deployRequestParams := rulesengine.NewSubmitDeployRequestParams()
+	deployRequestParams.AccountNumber = accountNumber
+	deployRequestParams.CustomerUserID = customerUserID
+	deployRequestParams.PortalTypeID = portalTypeID
+	deployRequestParams.DeployRequest = *deployRequest
+	deployRequestParams.OwnerID = ownerID
+
+	deployResponse, err := svc.SubmitDeployRequest(*deployRequestParams)
+	if err != nil {
+		logger.Warn(ctx, "Deploying policy failed", map[string]any{
+			"account_number": accountNumber,
+			"policy_id":      policyID,
+		})
+		return fmt.Errorf("submitting deploy request for policy %d: %w", policyID, err)
+	}
+
+	logger.Info(ctx, "Submitted deploy request", map[string]any{
+		"account_number":  accountNumber,
+		"deploy_response": deployResponse,
+	})
+
+	if track {
+		d.Set("deploy_request_id", deployResponse.ID)
+	}
+
+	state, err := waitForDeploy(
+		ctx,
+		svc,
+		getDeployRequestParams{
+			ID:             deployResponse.ID,
+			AccountNumber:  accountNumber,
+			CustomerUserID: customerUserID,
+			PortalTypeID:   portalTypeID,
+			OwnerID:        ownerID,
+		},
+		timeout)
+
+	if track && len(state) > 0 {
+		d.Set("deploy_status", state)
+	}
+
+	if err != nil {
+		logger.Warn(ctx, "Deploying policy failed", map[string]any{
+			"account_number":    accountNumber,
+			"deploy_request_id": deployResponse.ID,
+			"state":             state,
+		})
+		return fmt.Errorf("waiting for deploy: %w", err)
+	}
+
+	return nil
+}
+
+// rollbackPolicy redeploys the policy that was deployed before the resource's.
+func rollbackPolicy(
+	ctx context.Context,
+	previousID string,
+	d *schema.ResourceData,
+	m interface{},
+) error {
+	policyID, err := strconv.Atoi(previousID)
+	if err != nil {
+		return fmt.Errorf("error parsing previous_policy_id: %w", err)
+	}
+
+	rulesengineService, err := buildRulesEngineService(m.(internal.ProviderConfig))
+	if err != nil {
+		return fmt.Errorf("rollbackPolicy: buildRulesEngineService: %w", err)
+	}
+
+	logger.Info(ctx, "Rolling back to the previous policy", map[string]any{
+		"policy_id":          d.Id(),
+		"previous_policy_id": policyID,
+	})
+
+	err = deployPolicy(
+		ctx,
+		rulesengineService,
+		policyID,
+		false,
+		d.Timeout(schema.TimeoutDelete),
+		d)
+	if err != nil {
+		return fmt.Errorf("rollbackPolicy: %w", err)
+	}
+
+	d.SetId("") // indicates "delete" happened
+
+	return nil
+}
+
+// recordPreviousPolicy sets previous_policy_id to the policy deployed to the
+// resource's environment and platform before the resource takes over, unless
+// it is already set. Failing to find it is only an error for rollback, which
+// has nothing to redeploy without it.
+func recordPreviousPolicy(
+	ctx context.Context,
+	platform string,
+	d *schema.ResourceData,
+	m interface{},
+) error {
+	if len(d.Get("previous_policy_id").(string)) > 0 {
+		return nil
+	}
+
+	isRollback := d.Get("destroy_mode").(string) == destroyModeRollback
+
+	rulesengineService, err := buildRulesEngineService(m.(internal.ProviderConfig))
+	if err != nil {
+		return fmt.Errorf("recordPreviousPolicy: buildRulesEngineService: %w", err)
+	}
+
+	deployRequests, err := rulesengineService.GetDeployRequests(
+		listDeployRequestsParams{
+			AccountNumber:  d.Get("account_number").(string),
+			CustomerUserID: d.Get("customeruserid").(string),
+			PortalTypeID:   d.Get("portaltypeid").(string),
+			OwnerID:        d.Get("ownerid").(string),
+		})
+	if err != nil {
+		if isRollback {
+			return fmt.Errorf("error looking up the policy to roll back to: %w", err)
+		}
+
+		logger.Warn(ctx, "Looking up the previously deployed policy failed", map[string]any{
+			"error": err.Error(),
+		})
+		return nil
+	}
+
+	previousID := activePolicyID(
+		deployRequests,
+		d.Get("deploy_to").(string),
+		platform)
+	if len(previousID) == 0 && isRollback {
+		return fmt.Errorf(
+			"no policy is deployed to %s for platform %s to roll back to: set "+
+				"previous_policy_id, or set destroy_mode to placeholder or abandon",
+			d.Get("deploy_to").(string),
+			platform)
+	}
+
+	logger.Info(ctx, "Recorded the previously deployed policy", map[string]any{
+		"previous_policy_id": previousID,
+	})
+
+	d.Set("previous_policy_id", previousID)
+
+	return nil
+}
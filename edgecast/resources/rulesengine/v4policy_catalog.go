@@ -0,0 +1,354 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package rulesengine
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// catalogJSON lists the match and feature types of the Rules Engine, so that
+// policies can be validated without calling the API. It must be updated as
+// types are added to the Rules Engine.
+//
+//go:embed catalog.json
+var catalogJSON []byte
+
+// catalogEntry describes a match or feature type.
+type catalogEntry struct {
+	// Platforms lists the platforms that support the type. All platforms do
+	// if it is empty.
+	Platforms []string `json:"platforms"`
+
+	// Values lists the values allowed for properties, keyed by property name
+	// with "_" in place of "-".
+	Values map[string][]string `json:"values"`
+}
+
+type policyCatalog struct {
+	Platforms []string                `json:"platforms"`
+	Matches   map[string]catalogEntry `json:"matches"`
+	Features  map[string]catalogEntry `json:"features"`
+}
+
+var catalog = loadCatalog()
+
+func loadCatalog() policyCatalog {
+	var c policyCatalog
+	if err := json.Unmarshal(catalogJSON, &c); err != nil {
+		panic(fmt.Errorf("loading the Rules Engine catalog: %w", err))
+	}
+
+	return c
+}
+
+// policyPath names the parts of a policy in validation errors, as they are
+// named in the policy JSON or in rule blocks.
+type policyPath struct {
+	rules    string
+	matches  string
+	features string
+}
+
+var (
+	jsonPath   = policyPath{"rules", jsonkeyMatches, jsonKeyFeatures}
+	blocksPath = policyPath{"rule", "match", "feature"}
+)
+
+// validatePolicyCatalog is a ValidateFunc that checks the policy JSON against
+// the catalog.
+func validatePolicyCatalog(v interface{}, k string) ([]string, []error) {
+	policy := make(map[string]interface{})
+	if err := json.Unmarshal([]byte(v.(string)), &policy); err != nil {
+		// StringIsJSON reports invalid JSON.
+		return nil, nil
+	}
+
+	var errs []error
+	for _, err := range validatePolicy(policy, jsonPath) {
+		errs = append(errs, fmt.Errorf("%s: %w", k, err))
+	}
+
+	return nil, errs
+}
+
+// validateRuleBlocks checks the policy defined by rule blocks against the
+// catalog. It needs the platform as well as the blocks, so it validates the
+// raw configuration. Configuration that is not yet known is skipped.
+func validateRuleBlocks(
+	ctx context.Context,
+	req schema.ValidateResourceConfigFuncRequest,
+	resp *schema.ValidateResourceConfigFuncResponse,
+) {
+	config := req.RawConfig
+	if config.IsNull() || !config.IsKnown() {
+		return
+	}
+
+	rules := config.GetAttr("rule")
+	if rules.IsNull() || !rules.IsWhollyKnown() {
+		return
+	}
+
+	b, err := ctyjson.Marshal(rules, rules.Type())
+	if err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.FromErr(err)...)
+		return
+	}
+
+	var rawRules []interface{}
+	if err := json.Unmarshal(b, &rawRules); err != nil {
+		resp.Diagnostics = append(resp.Diagnostics, diag.FromErr(err)...)
+		return
+	}
+
+	platform := ""
+	if v := config.GetAttr("platform"); v.IsKnown() && !v.IsNull() &&
+		v.Type() == cty.String {
+		platform = v.AsString()
+	}
+
+	policy := expandPolicy(platform, "", rawRules)
+	for _, err := range validatePolicy(policy, blocksPath) {
+		resp.Diagnostics = append(resp.Diagnostics, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Invalid Rules Engine policy",
+			Detail:   err.Error(),
+		})
+	}
+}
+
+// validatePolicy returns an error for each match or feature in policy that
+// is not in the catalog, is not supported on the policy's platform or has a
+// value the catalog does not allow. Errors start with the path of the match
+// or feature, e.g. rules[0].matches[1] in rule "redirects". Types that are
+// not in the catalog are suggested the closest type that is.
+func validatePolicy(
+	policy map[string]interface{},
+	path policyPath,
+) []error {
+	var errs []error
+
+	platform := toString(policy["platform"])
+	if len(platform) > 0 && !contains(catalog.Platforms, platform) {
+		errs = append(errs, fmt.Errorf(
+			"platform %q is not supported, expected one of: %s",
+			platform,
+			strings.Join(catalog.Platforms, ", ")))
+		platform = ""
+	}
+
+	for i, rule := range toMaps(policy["rules"]) {
+		prefix := fmt.Sprintf("%s[%d]", path.rules, i)
+
+		suffix := ""
+		if name := toString(rule["name"]); len(name) > 0 {
+			suffix = fmt.Sprintf(" in rule %q", name)
+		}
+
+		validateMatches(
+			prefix,
+			suffix,
+			platform,
+			rule[jsonkeyMatches],
+			path,
+			&errs)
+	}
+
+	return errs
+}
+
+// validateMatches validates matches at prefix, e.g. rules[0], and their
+// features and nested matches. suffix follows the path in errors.
+func validateMatches(
+	prefix string,
+	suffix string,
+	platform string,
+	matches interface{},
+	path policyPath,
+	errs *[]error,
+) {
+	for i, match := range toMaps(matches) {
+		matchPath := fmt.Sprintf("%s.%s[%d]", prefix, path.matches, i)
+
+		matchType := toString(match[jsonKeyType])
+		// The API returns select.first-match as match.select.first-match.
+		if matchType == "match.select.first-match" {
+			matchType = "select.first-match"
+		}
+
+		validateType(
+			matchPath+suffix,
+			"match",
+			matchType,
+			platform,
+			match,
+			catalog.Matches,
+			errs)
+
+		for j, feature := range toMaps(match[jsonKeyFeatures]) {
+			validateType(
+				fmt.Sprintf("%s.%s[%d]%s", matchPath, path.features, j, suffix),
+				"feature",
+				toString(feature[jsonKeyType]),
+				platform,
+				feature,
+				catalog.Features,
+				errs)
+		}
+
+		validateMatches(
+			matchPath,
+			suffix,
+			platform,
+			match[jsonkeyMatches],
+			path,
+			errs)
+	}
+}
+
+// validateType checks a match or feature against its catalog entry. location
+// starts its errors.
+func validateType(
+	location string,
+	kind string,
+	itemType string,
+	platform string,
+	item map[string]interface{},
+	entries map[string]catalogEntry,
+	errs *[]error,
+) {
+	entry, ok := entries[itemType]
+	if !ok {
+		err := fmt.Errorf("%s: unknown %s type %q", location, kind, itemType)
+		if suggestion := closestType(itemType, entries); len(suggestion) > 0 {
+			err = fmt.Errorf("%w, did you mean %q?", err, suggestion)
+		}
+
+		*errs = append(*errs, err)
+		return
+	}
+
+	if len(platform) > 0 && len(entry.Platforms) > 0 &&
+		!contains(entry.Platforms, platform) {
+		*errs = append(*errs, fmt.Errorf(
+			"%s: %s type %q is not supported on platform %q, only on: %s",
+			location,
+			kind,
+			itemType,
+			platform,
+			strings.Join(entry.Platforms, ", ")))
+	}
+
+	for _, property := range sortedPropertyNames(entry.Values) {
+		allowed := entry.Values[property]
+		for k, v := range item {
+			if strings.ReplaceAll(k, "-", "_") != property {
+				continue
+			}
+
+			for _, value := range strings.Fields(joinValue(v)) {
+				if !contains(allowed, value) {
+					*errs = append(*errs, fmt.Errorf(
+						"%s: %s is %q, expected one of: %s",
+						location,
+						k,
+						value,
+						strings.Join(allowed, ", ")))
+				}
+			}
+		}
+	}
+}
+
+// joinValue returns a JSON value as a string, with arrays joined by spaces as
+// standardizeMatchFeature does.
+func joinValue(v interface{}) string {
+	values, ok := v.([]interface{})
+	if !ok {
+		return toString(v)
+	}
+
+	s := make([]string, 0, len(values))
+	for _, value := range values {
+		s = append(s, toString(value))
+	}
+
+	return strings.Join(s, " ")
+}
+
+// closestType returns the type in entries that is the fewest edits away from
+// t, if it is close enough to be a likely typo.
+func closestType(t string, entries map[string]catalogEntry) string {
+	const maxDistance = 3
+
+	closest := ""
+	best := maxDistance + 1
+	for candidate := range entries {
+		d := editDistance(t, candidate)
+		if d < best || (d == best && candidate < closest) {
+			closest, best = candidate, d
+		}
+	}
+
+	if best > maxDistance {
+		return ""
+	}
+
+	return closest
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+func sortedPropertyNames(values map[string][]string) []string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
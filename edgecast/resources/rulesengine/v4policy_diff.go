@@ -0,0 +1,376 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package rulesengine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// policyChangesCustomizeDiff lists the rules added, removed and changed by a
+// planned policy update in policy_changes, so that they show in the plan
+// instead of only the whole policy JSON.
+func policyChangesCustomizeDiff(
+	ctx context.Context,
+	d *schema.ResourceDiff,
+	m interface{},
+) error {
+	if !d.HasChanges("policy", "rule", "platform", "description") {
+		return nil
+	}
+
+	if !d.NewValueKnown("policy") || !d.NewValueKnown("rule") {
+		return d.SetNewComputed("policy_changes")
+	}
+
+	oldPolicy, newPolicy, err := policyChange(d)
+	if err != nil {
+		return fmt.Errorf("error comparing policies: %w", err)
+	}
+
+	// A change that leaves the rules as they are, e.g. moving the policy
+	// from JSON to rule blocks, clears the changes listed by an earlier plan.
+	changes := diffPolicies(oldPolicy, newPolicy)
+	lines := make([]interface{}, 0, len(changes))
+	for _, change := range changes {
+		lines = append(lines, change)
+	}
+
+	return d.SetNew("policy_changes", lines)
+}
+
+// previousPolicyCustomizeDiff plans previous_policy_id to be looked up again
+// when the environment or platform that the policy is deployed to changes,
+// unless previous_policy_id is set in the configuration. The policy to roll
+// back to is the one deployed there before the resource.
+func previousPolicyCustomizeDiff(
+	ctx context.Context,
+	d *schema.ResourceDiff,
+	m interface{},
+) error {
+	if len(d.Id()) == 0 || !deployTargetChanged(d) ||
+		previousPolicyConfigured(d.GetRawConfig()) {
+		return nil
+	}
+
+	return d.SetNewComputed("previous_policy_id")
+}
+
+// deployTargetChanged reports whether a change moves the policy to another
+// environment or platform.
+func deployTargetChanged(
+	d interface {
+		GetChange(key string) (interface{}, interface{})
+	},
+) bool {
+	oldEnvironment, newEnvironment := d.GetChange("deploy_to")
+	oldPlatform, newPlatform := d.GetChange("platform")
+	oldJSON, newJSON := d.GetChange("policy")
+
+	return oldEnvironment.(string) != newEnvironment.(string) ||
+		policyPlatform(oldPlatform.(string), oldJSON.(string)) !=
+			policyPlatform(newPlatform.(string), newJSON.(string))
+}
+
+// previousPolicyConfigured reports whether previous_policy_id is set in the
+// resource's configuration.
+func previousPolicyConfigured(config cty.Value) bool {
+	if config.IsNull() || !config.IsKnown() || !config.Type().IsObjectType() ||
+		!config.Type().HasAttribute("previous_policy_id") {
+		return false
+	}
+
+	return !config.GetAttr("previous_policy_id").IsNull()
+}
+
+// policyPlatform returns the platform of a policy defined either by rule
+// blocks and the platform attribute, or by the policy JSON.
+func policyPlatform(platform string, policyJSON string) string {
+	if len(platform) > 0 {
+		return platform
+	}
+
+	var policy struct {
+		Platform string `json:"platform"`
+	}
+	if err := json.Unmarshal([]byte(policyJSON), &policy); err != nil {
+		return ""
+	}
+
+	return policy.Platform
+}
+
+// policyChange returns the policy in state and the planned policy, normalized
+// by cleanPolicy. Either may be defined by the policy JSON or by rule blocks.
+func policyChange(
+	d *schema.ResourceDiff,
+) (map[string]interface{}, map[string]interface{}, error) {
+	oldJSON, newJSON := d.GetChange("policy")
+	oldRules, newRules := d.GetChange("rule")
+	oldPlatform, newPlatform := d.GetChange("platform")
+	oldDescription, newDescription := d.GetChange("description")
+
+	oldPolicy, err := normalizePolicy(
+		oldJSON.(string),
+		oldPlatform.(string),
+		oldDescription.(string),
+		oldRules.([]interface{}))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	newPolicy, err := normalizePolicy(
+		newJSON.(string),
+		newPlatform.(string),
+		newDescription.(string),
+		newRules.([]interface{}))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return oldPolicy, newPolicy, nil
+}
+
+// normalizePolicy returns the policy defined by rules or, if there are none,
+// by policyJSON, cleaned by cleanPolicy. An empty policy has no rules.
+func normalizePolicy(
+	policyJSON string,
+	platform string,
+	description string,
+	rules []interface{},
+) (map[string]interface{}, error) {
+	var policy map[string]interface{}
+	switch {
+	case len(rules) > 0:
+		policy = expandPolicy(platform, description, rules)
+	case len(policyJSON) > 0:
+		if err := json.Unmarshal([]byte(policyJSON), &policy); err != nil {
+			return nil, err
+		}
+	default:
+		return map[string]interface{}{}, nil
+	}
+
+	if _, ok := policy["rules"].([]interface{}); !ok {
+		policy["rules"] = []interface{}{}
+	}
+
+	if err := cleanPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	// Round trip through JSON so that policies from both styles hold the same
+	// types.
+	b, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	normalized := make(map[string]interface{})
+	if err := json.Unmarshal(b, &normalized); err != nil {
+		return nil, err
+	}
+
+	return normalized, nil
+}
+
+// diffPolicies describes the differences between two normalized policies, one
+// line per rule added (+), removed (-) or changed (~). Rules are identified by
+// name, or by position if they have no name or share it with another rule.
+// Matches and features are compared by position within a rule.
+func diffPolicies(oldPolicy, newPolicy map[string]interface{}) []string {
+	var changes []string
+
+	// A new policy has nothing to compare its platform and description with.
+	props := changedProperties(oldPolicy, newPolicy, "name", "rules")
+	if len(oldPolicy) > 0 && len(props) > 0 {
+		changes = append(changes, fmt.Sprintf(
+			"~ policy changed (%s)", strings.Join(props, ", ")))
+	}
+
+	oldKeys, oldRules := keyRules(toMaps(oldPolicy["rules"]))
+	newKeys, newRules := keyRules(toMaps(newPolicy["rules"]))
+
+	for _, key := range oldKeys {
+		if _, ok := newRules[key]; !ok {
+			changes = append(changes, "- "+key)
+		}
+	}
+
+	for _, key := range newKeys {
+		newRule := newRules[key]
+		oldRule, ok := oldRules[key]
+		if !ok {
+			changes = append(changes, "+ "+key)
+			continue
+		}
+
+		if props := changedProperties(oldRule, newRule, "name", jsonkeyMatches); len(props) > 0 {
+			changes = append(changes, fmt.Sprintf(
+				"~ %s changed (%s)", key, strings.Join(props, ", ")))
+		}
+
+		var details []string
+		diffMatches(
+			"",
+			toMaps(oldRule[jsonkeyMatches]),
+			toMaps(newRule[jsonkeyMatches]),
+			&details)
+
+		for _, detail := range details {
+			changes = append(changes, fmt.Sprintf("~ %s: %s", key, detail))
+		}
+	}
+
+	return changes
+}
+
+// keyRules returns the keys of rules in order, and the rules by key.
+func keyRules(
+	rules []map[string]interface{},
+) ([]string, map[string]map[string]interface{}) {
+	counts := make(map[string]int)
+	for _, rule := range rules {
+		counts[toString(rule["name"])]++
+	}
+
+	keys := make([]string, 0, len(rules))
+	byKey := make(map[string]map[string]interface{}, len(rules))
+	for i, rule := range rules {
+		name := toString(rule["name"])
+
+		key := fmt.Sprintf("rule %q", name)
+		if len(name) == 0 || counts[name] > 1 {
+			key = fmt.Sprintf("rule %d", i+1)
+		}
+
+		keys = append(keys, key)
+		byKey[key] = rule
+	}
+
+	return keys, byKey
+}
+
+// diffMatches adds a description of each match, feature and nested match that
+// differs between oldMatches and newMatches to changes.
+func diffMatches(
+	prefix string,
+	oldMatches []map[string]interface{},
+	newMatches []map[string]interface{},
+	changes *[]string,
+) {
+	for i := 0; i < len(oldMatches) || i < len(newMatches); i++ {
+		var oldMatch, newMatch map[string]interface{}
+		if i < len(oldMatches) {
+			oldMatch = oldMatches[i]
+		}
+
+		if i < len(newMatches) {
+			newMatch = newMatches[i]
+		}
+
+		label := prefix + describeItem("match", i, oldMatch, newMatch)
+		if !diffItem(label, oldMatch, newMatch, changes) {
+			continue
+		}
+
+		oldFeatures := toMaps(oldMatch[jsonKeyFeatures])
+		newFeatures := toMaps(newMatch[jsonKeyFeatures])
+		for j := 0; j < len(oldFeatures) || j < len(newFeatures); j++ {
+			var oldFeature, newFeature map[string]interface{}
+			if j < len(oldFeatures) {
+				oldFeature = oldFeatures[j]
+			}
+
+			if j < len(newFeatures) {
+				newFeature = newFeatures[j]
+			}
+
+			diffItem(
+				label+" > "+describeItem("feature", j, oldFeature, newFeature),
+				oldFeature,
+				newFeature,
+				changes)
+		}
+
+		diffMatches(
+			label+" > ",
+			toMaps(oldMatch[jsonkeyMatches]),
+			toMaps(newMatch[jsonkeyMatches]),
+			changes)
+	}
+}
+
+// diffItem adds a description of how a match or feature changed to changes.
+// It returns true if the item is in both a and b, so that its children should
+// be compared too.
+func diffItem(
+	label string,
+	a map[string]interface{},
+	b map[string]interface{},
+	changes *[]string,
+) bool {
+	switch {
+	case a == nil:
+		*changes = append(*changes, label+" added")
+		return false
+	case b == nil:
+		*changes = append(*changes, label+" removed")
+		return false
+	}
+
+	props := changedProperties(a, b, jsonKeyFeatures, jsonkeyMatches)
+	if len(props) > 0 {
+		*changes = append(*changes, fmt.Sprintf(
+			"%s changed (%s)", label, strings.Join(props, ", ")))
+	}
+
+	return true
+}
+
+// describeItem returns e.g. "match 1 (match.always)" for the i-th match or
+// feature, using its planned type if there is one.
+func describeItem(kind string, i int, a, b map[string]interface{}) string {
+	itemType := toString(b[jsonKeyType])
+	if len(itemType) == 0 {
+		itemType = toString(a[jsonKeyType])
+	}
+
+	return fmt.Sprintf("%s %d (%s)", kind, i+1, itemType)
+}
+
+// changedProperties returns the names of the properties that differ between
+// a and b in order, apart from those in ignore.
+func changedProperties(a, b map[string]interface{}, ignore ...string) []string {
+	skip := make(map[string]bool, len(ignore))
+	for _, key := range ignore {
+		skip[key] = true
+	}
+
+	keys := make(map[string]bool)
+	for key := range a {
+		keys[key] = true
+	}
+
+	for key := range b {
+		keys[key] = true
+	}
+
+	var changed []string
+	for key := range keys {
+		if !skip[key] && !reflect.DeepEqual(a[key], b[key]) {
+			changed = append(changed, key)
+		}
+	}
+	sort.Strings(changed)
+
+	return changed
+}
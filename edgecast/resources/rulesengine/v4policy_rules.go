@@ -0,0 +1,371 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package rulesengine
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// maxMatchDepth is the number of levels of match blocks that may be nested,
+// since Terraform schemas cannot be recursive.
+const maxMatchDepth = 3
+
+const (
+	jsonKeyType  string = "type"
+	jsonKeyValue string = "value"
+)
+
+// reservedParameters are the match and feature properties that have their own
+// arguments or blocks, and so may not be set through parameters.
+var reservedParameters = map[string]bool{
+	jsonKeyType:     true,
+	jsonKeyValue:    true,
+	jsonKeyFeatures: true,
+	jsonkeyMatches:  true,
+}
+
+var (
+	matchTypeRegexp   = regexp.MustCompile(`^(match|select)\.[a-z0-9.-]+$`)
+	featureTypeRegexp = regexp.MustCompile(`^feature\.[a-z0-9.-]+$`)
+)
+
+// ruleSchema returns the schema of rule blocks, which define a policy in HCL
+// instead of the policy JSON.
+func ruleSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeList,
+		Optional:     true,
+		ExactlyOneOf: []string{"policy", "rule"},
+		Description: "Defines a rule of the policy, as an alternative to `policy`. " +
+			"Rules are evaluated in the order they are defined.",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"name": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Defines the name of the rule.",
+				},
+				"description": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Description: "Describes the rule.",
+				},
+				"match": matchSchema(maxMatchDepth, true),
+			},
+		},
+	}
+}
+
+// matchSchema returns the schema of match blocks that may hold depth levels
+// of matches, including their own.
+func matchSchema(depth int, required bool) *schema.Schema {
+	s := map[string]*schema.Schema{
+		"type": {
+			Type:     schema.TypeString,
+			Required: true,
+			Description: "Identifies the match, e.g. `match.always` or " +
+				"`match.request.request-header.literal`.",
+			ValidateFunc: validation.StringMatch(
+				matchTypeRegexp,
+				"must be a match type such as match.always or select.first-match"),
+		},
+		"value":      valueSchema(),
+		"parameters": parametersSchema(),
+		"feature": {
+			Type:        schema.TypeList,
+			Optional:    true,
+			Description: "Defines a feature applied to requests that satisfy the match.",
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"type": {
+						Type:        schema.TypeString,
+						Required:    true,
+						Description: "Identifies the feature, e.g. `feature.comment`.",
+						ValidateFunc: validation.StringMatch(
+							featureTypeRegexp,
+							"must be a feature type such as feature.comment"),
+					},
+					"value":      valueSchema(),
+					"parameters": parametersSchema(),
+				},
+			},
+		},
+	}
+
+	if depth > 1 {
+		s["match"] = matchSchema(depth-1, false)
+	}
+
+	description := "Defines a match of the rule."
+	if !required {
+		description = "Defines a match nested in this match."
+	}
+
+	return &schema.Schema{
+		Type:        schema.TypeList,
+		Required:    required,
+		Optional:    !required,
+		Description: description,
+		Elem:        &schema.Resource{Schema: s},
+	}
+}
+
+func valueSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Description: "Defines the value of the match or feature. Lists of values " +
+			"are separated by spaces.",
+	}
+}
+
+func parametersSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeMap,
+		Optional: true,
+		Elem:     &schema.Schema{Type: schema.TypeString},
+		Description: "Defines the other properties of the match or feature, e.g. " +
+			"`code` or `destination`. Use `_` in place of `-` in property names. " +
+			"The values `true` and `false` are sent as booleans.",
+		ValidateFunc: validateParameters,
+	}
+}
+
+func validateParameters(v interface{}, k string) ([]string, []error) {
+	var errs []error
+	for name := range v.(map[string]interface{}) {
+		if reservedParameters[name] {
+			errs = append(errs, fmt.Errorf(
+				"%s: %q cannot be set through parameters", k, name))
+		}
+	}
+
+	return nil, errs
+}
+
+// usesRuleBlocks reports whether the policy is defined with rule blocks
+// instead of the policy JSON. Imported policies use the policy JSON.
+func usesRuleBlocks(d *schema.ResourceData) bool {
+	_, ok := d.GetOk("rule")
+	return ok
+}
+
+// readPolicyConfig returns the policy defined in the configuration, from
+// either the policy JSON or the rule blocks.
+func readPolicyConfig(d *schema.ResourceData) (map[string]interface{}, error) {
+	if usesRuleBlocks(d) {
+		return expandPolicy(
+			d.Get("platform").(string),
+			d.Get("description").(string),
+			d.Get("rule").([]interface{})), nil
+	}
+
+	policyMap := make(map[string]interface{})
+	err := json.Unmarshal([]byte(d.Get("policy").(string)), &policyMap)
+	if err != nil {
+		return nil, err
+	}
+
+	return policyMap, nil
+}
+
+// expandPolicy returns the policy defined by the platform, description and
+// rule blocks, in the form of the policy JSON.
+func expandPolicy(
+	platform string,
+	description string,
+	rules []interface{},
+) map[string]interface{} {
+	policy := map[string]interface{}{
+		"platform": platform,
+		"rules":    expandRules(rules),
+	}
+
+	if len(description) > 0 {
+		policy["description"] = description
+	}
+
+	return policy
+}
+
+// expandRules returns the rules defined by rule blocks. Null values, as in raw
+// configuration, are treated as unset.
+func expandRules(rules []interface{}) []interface{} {
+	expanded := make([]interface{}, 0, len(rules))
+	for _, rule := range toMaps(rules) {
+		ruleMap := map[string]interface{}{
+			jsonkeyMatches: expandMatches(rule["match"]),
+		}
+
+		for _, key := range []string{"name", "description"} {
+			if v := toString(rule[key]); len(v) > 0 {
+				ruleMap[key] = v
+			}
+		}
+
+		expanded = append(expanded, ruleMap)
+	}
+
+	return expanded
+}
+
+func expandMatches(matches interface{}) []interface{} {
+	expanded := make([]interface{}, 0)
+	for _, match := range toMaps(matches) {
+		matchMap := expandMatchFeature(match)
+
+		if features := toMaps(match["feature"]); len(features) > 0 {
+			expandedFeatures := make([]interface{}, 0, len(features))
+			for _, feature := range features {
+				expandedFeatures = append(
+					expandedFeatures,
+					expandMatchFeature(feature))
+			}
+
+			matchMap[jsonKeyFeatures] = expandedFeatures
+		}
+
+		// The deepest match blocks have no nested matches.
+		if children := toMaps(match["match"]); len(children) > 0 {
+			matchMap[jsonkeyMatches] = expandMatches(children)
+		}
+
+		expanded = append(expanded, matchMap)
+	}
+
+	return expanded
+}
+
+// expandMatchFeature returns the type, value and parameters of a match or
+// feature block.
+func expandMatchFeature(block map[string]interface{}) map[string]interface{} {
+	expanded := map[string]interface{}{jsonKeyType: toString(block["type"])}
+	if v := toString(block["value"]); len(v) > 0 {
+		expanded[jsonKeyValue] = v
+	}
+
+	parameters, _ := block["parameters"].(map[string]interface{})
+	for k, v := range parameters {
+		switch v {
+		case "true", "false":
+			expanded[k] = v == "true"
+		default:
+			expanded[k] = v
+		}
+	}
+
+	return expanded
+}
+
+// flattenRules returns rule blocks for the rules of a policy cleaned by
+// cleanPolicy.
+func flattenRules(rules interface{}) ([]interface{}, error) {
+	flattened := make([]interface{}, 0)
+	for _, rule := range toMaps(rules) {
+		matches, err := flattenMatches(rule[jsonkeyMatches], maxMatchDepth)
+		if err != nil {
+			return nil, err
+		}
+
+		flattened = append(flattened, map[string]interface{}{
+			"name":        toString(rule["name"]),
+			"description": toString(rule["description"]),
+			"match":       matches,
+		})
+	}
+
+	return flattened, nil
+}
+
+func flattenMatches(matches interface{}, depth int) ([]interface{}, error) {
+	flattened := make([]interface{}, 0)
+	for _, match := range toMaps(matches) {
+		block := flattenMatchFeature(match)
+
+		features := make([]interface{}, 0)
+		for _, feature := range toMaps(match[jsonKeyFeatures]) {
+			features = append(features, flattenMatchFeature(feature))
+		}
+		block["feature"] = features
+
+		children := toMaps(match[jsonkeyMatches])
+		if len(children) > 0 {
+			if depth == 1 {
+				return nil, fmt.Errorf(
+					"matches are nested more than %d levels deep, which rule "+
+						"blocks do not support; use policy instead",
+					maxMatchDepth)
+			}
+
+			flattenedChildren, err := flattenMatches(children, depth-1)
+			if err != nil {
+				return nil, err
+			}
+			block["match"] = flattenedChildren
+		}
+
+		flattened = append(flattened, block)
+	}
+
+	return flattened, nil
+}
+
+// flattenMatchFeature returns the type, value and parameters of a match or
+// feature.
+func flattenMatchFeature(m map[string]interface{}) map[string]interface{} {
+	parameters := make(map[string]interface{})
+	for k, v := range m {
+		if !reservedParameters[k] {
+			parameters[k] = toString(v)
+		}
+	}
+
+	return map[string]interface{}{
+		"type":       toString(m[jsonKeyType]),
+		"value":      toString(m[jsonKeyValue]),
+		"parameters": parameters,
+	}
+}
+
+// toMaps returns the objects in a JSON array, which is []interface{} when
+// unmarshaled and []map[string]interface{} once cleaned.
+func toMaps(v interface{}) []map[string]interface{} {
+	switch items := v.(type) {
+	case []map[string]interface{}:
+		return items
+	case []interface{}:
+		maps := make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				maps = append(maps, m)
+			}
+		}
+		return maps
+	default:
+		return nil
+	}
+}
+
+// toString returns a JSON value as a string, as held by string arguments.
+func toString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	default:
+		b, _ := json.Marshal(val)
+		return string(b)
+	}
+}
@@ -1,11 +1,27 @@
 package rulesengine
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"terraform-provider-edgecast/edgecast/internal"
+
+	"github.com/EdgeCast/ec-sdk-go/edgecast/rulesengine"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 )
 
 type JSONMap map[string]any
@@ -207,3 +223,1370 @@ func Test_cleanPolicy(t *testing.T) {
 		})
 	}
 }
+
+// mockRulesEngine is an in-memory rulesEngineAPI.
+type mockRulesEngine struct {
+	policies map[int]map[string]any
+	deploys  []rulesengine.SubmitDeployRequest
+	nextID   int
+
+	// deployErr, if set, is returned by SubmitDeployRequest.
+	deployErr error
+
+	// deployStates lists the states that GetDeployRequest returns in turn,
+	// after which deploy requests are deployed.
+	deployStates []string
+
+	// deployRequests is returned by GetDeployRequests, or listErr if set.
+	deployRequests []deployRequestSummary
+	listErr        error
+}
+
+func newMockRulesEngine() *mockRulesEngine {
+	return &mockRulesEngine{policies: make(map[int]map[string]any)}
+}
+
+func (m *mockRulesEngine) AddPolicy(
+	params rulesengine.AddPolicyParams,
+) (*rulesengine.PolicyResponse, error) {
+	policy := make(map[string]any)
+	if err := json.Unmarshal([]byte(params.PolicyAsString), &policy); err != nil {
+		return nil, err
+	}
+
+	m.nextID++
+	id := strconv.Itoa(m.nextID)
+	policy["id"] = id
+	policy["@type"] = "Policy"
+	m.policies[m.nextID] = policy
+
+	return &rulesengine.PolicyResponse{ID: id}, nil
+}
+
+func (m *mockRulesEngine) GetPolicy(
+	params rulesengine.GetPolicyParams,
+) (map[string]interface{}, error) {
+	policy, ok := m.policies[params.PolicyID]
+	if !ok {
+		return nil, errors.New("sendRequest failed (HTTP StatusCode:404): ")
+	}
+
+	// Return a copy, since reads clean the policy in place.
+	b, err := json.Marshal(policy)
+	if err != nil {
+		return nil, err
+	}
+
+	copied := make(map[string]any)
+	if err := json.Unmarshal(b, &copied); err != nil {
+		return nil, err
+	}
+
+	return copied, nil
+}
+
+func (m *mockRulesEngine) SubmitDeployRequest(
+	params rulesengine.SubmitDeployRequestParams,
+) (*rulesengine.DeployRequestOK, error) {
+	if m.deployErr != nil {
+		return nil, m.deployErr
+	}
+
+	m.deploys = append(m.deploys, params.DeployRequest)
+
+	return &rulesengine.DeployRequestOK{
+		ID:    "deploy-" + strconv.Itoa(len(m.deploys)),
+		State: "submitted",
+	}, nil
+}
+
+func (m *mockRulesEngine) GetDeployRequest(
+	params getDeployRequestParams,
+) (*rulesengine.DeployRequestOK, error) {
+	state := deployStateDeployed
+	if len(m.deployStates) > 0 {
+		state, m.deployStates = m.deployStates[0], m.deployStates[1:]
+	}
+
+	return &rulesengine.DeployRequestOK{ID: params.ID, State: state}, nil
+}
+
+func (m *mockRulesEngine) GetDeployRequests(
+	params listDeployRequestsParams,
+) ([]deployRequestSummary, error) {
+	if m.listErr != nil {
+		return nil, m.listErr
+	}
+
+	return m.deployRequests, nil
+}
+
+func testPolicyData(t *testing.T) *schema.ResourceData {
+	t.Helper()
+
+	return schema.TestResourceDataRaw(
+		t,
+		ResourceRulesEngineV4Policy().Schema,
+		map[string]any{
+			"account_number": "ABCD",
+			"deploy_to":      "staging",
+			"policy": `{
+				"platform": "http_large",
+				"rules": [{
+					"name": "rule 1",
+					"matches": [{
+						"type": "match.always",
+						"features": [{"type": "feature.comment", "value": "test"}]
+					}]
+				}]
+			}`,
+		})
+}
+
+func TestResourcePolicyLifecycle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := newMockRulesEngine()
+	config := internal.ProviderConfig{
+		ServiceOverrides: map[string]any{"rulesengine": mock},
+	}
+	d := testPolicyData(t)
+
+	if diags := ResourcePolicyCreate(ctx, d, config); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	if d.Id() != "1" || d.Get("deploy_request_id") != "deploy-1" ||
+		d.Get("deploy_status") != "deployed" {
+		t.Errorf("create: unexpected state: id %q, deploy_request_id %v, "+
+			"deploy_status %v",
+			d.Id(), d.Get("deploy_request_id"), d.Get("deploy_status"))
+	}
+
+	policy := mock.policies[1]
+	if policy["state"] != "locked" ||
+		!strings.HasPrefix(policy["name"].(string), "tf-ABCD-staging-http_large-") {
+		t.Errorf("create: unexpected policy sent: %v", policy)
+	}
+
+	want := []rulesengine.SubmitDeployRequest{{
+		Message:     "Auto-submitted policy",
+		PolicyID:    1,
+		Environment: "staging",
+	}}
+	if !reflect.DeepEqual(mock.deploys, want) {
+		t.Errorf("create: expected deploys %+v, got %+v", want, mock.deploys)
+	}
+
+	if diags := ResourcePolicyDelete(ctx, d, config); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+
+	if len(d.Id()) != 0 {
+		t.Errorf("delete: expected the ID to be cleared, got %q", d.Id())
+	}
+
+	// Policies cannot be deleted, so a placeholder for the same platform is
+	// deployed in its place.
+	placeholder := mock.policies[2]
+	if placeholder == nil || placeholder["platform"] != "http_large" {
+		t.Fatalf("delete: expected a placeholder policy, got %v", placeholder)
+	}
+
+	if len(mock.deploys) != 2 || mock.deploys[1].PolicyID != 2 {
+		t.Errorf("delete: expected the placeholder to be deployed, got %+v",
+			mock.deploys)
+	}
+}
+
+func testDeployRequests() []deployRequestSummary {
+	deployed := func(
+		id string,
+		environment string,
+		platform string,
+		createdAt string,
+	) deployRequestSummary {
+		r := deployRequestSummary{
+			ID:          "deploy-" + id,
+			State:       deployStateDeployed,
+			Environment: environment,
+		}
+		r.CreatedAt, _ = time.Parse(time.RFC3339, createdAt)
+		r.Policies.ID = id
+		r.Policies.Platform = platform
+		return r
+	}
+
+	rejected := deployed("8", "staging", "http_large", "2023-03-01T00:00:00Z")
+	rejected.State = deployStateRejected
+
+	return []deployRequestSummary{
+		deployed("5", "staging", "http_large", "2023-01-01T00:00:00Z"),
+		deployed("7", "staging", "http_large", "2023-02-01T00:00:00Z"),
+		rejected,
+		deployed("9", "production", "http_large", "2023-04-01T00:00:00Z"),
+		deployed("10", "staging", "http_small", "2023-05-01T00:00:00Z"),
+	}
+}
+
+func Test_activePolicyID(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name        string
+		environment string
+		platform    string
+		want        string
+	}{
+		{
+			name:        "Latest Deployed",
+			environment: "staging",
+			platform:    "http_large",
+			want:        "7",
+		},
+		{
+			name:        "Other Environment",
+			environment: "production",
+			platform:    "http_large",
+			want:        "9",
+		},
+		{
+			name:        "None Deployed",
+			environment: "production",
+			platform:    "adn",
+			want:        "",
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			got := activePolicyID(testDeployRequests(), c.environment, c.platform)
+			if got != c.want {
+				t.Errorf("expected %q, got %q", c.want, got)
+			}
+		})
+	}
+}
+
+func TestDeployedPolicies(t *testing.T) {
+	t.Parallel()
+
+	mock := newMockRulesEngine()
+	mock.deployRequests = testDeployRequests()
+	for _, id := range []int{7, 9, 10} {
+		mock.policies[id] = map[string]any{"name": fmt.Sprintf("tf-%d", id)}
+	}
+
+	config := internal.ProviderConfig{
+		ServiceOverrides: map[string]any{"rulesengine": mock},
+	}
+
+	got, err := DeployedPolicies(config, "ABCD")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []DeployedPolicy{
+		{ID: "7", Name: "tf-7", Environment: "staging", Platform: "http_large"},
+		{ID: "9", Name: "tf-9", Environment: "production", Platform: "http_large"},
+		{ID: "10", Name: "tf-10", Environment: "staging", Platform: "http_small"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestResourcePolicyDelete_DestroyMode(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name           string
+		destroyMode    string
+		previousID     string
+		deployRequests []deployRequestSummary
+		listErr        error
+		expectErr      bool
+		wantPreviousID string
+		// deleteMode, if set, replaces destroyMode before the resource is
+		// deleted.
+		deleteMode      string
+		expectDeleteErr bool
+		wantDeployed    []int
+	}{
+		{
+			name:           "Placeholder",
+			destroyMode:    destroyModePlaceholder,
+			deployRequests: testDeployRequests(),
+			wantPreviousID: "7",
+			wantDeployed:   []int{2},
+		},
+		{
+			name:           "Rollback",
+			destroyMode:    destroyModeRollback,
+			deployRequests: testDeployRequests(),
+			wantPreviousID: "7",
+			wantDeployed:   []int{7},
+		},
+		{
+			name:           "Rollback To Configured Policy",
+			destroyMode:    destroyModeRollback,
+			previousID:     "5",
+			deployRequests: testDeployRequests(),
+			wantPreviousID: "5",
+			wantDeployed:   []int{5},
+		},
+		{
+			name:        "Rollback Without Previous Policy",
+			destroyMode: destroyModeRollback,
+			expectErr:   true,
+		},
+		{
+			name:            "Rollback Without Previous Policy At Delete",
+			destroyMode:     destroyModePlaceholder,
+			wantPreviousID:  "",
+			deleteMode:      destroyModeRollback,
+			expectDeleteErr: true,
+			wantDeployed:    []int{},
+		},
+		{
+			name:         "Abandon",
+			destroyMode:  destroyModeAbandon,
+			wantDeployed: []int{},
+		},
+		{
+			name:         "Lookup Error",
+			destroyMode:  destroyModePlaceholder,
+			listErr:      errors.New("sendRequest failed (HTTP StatusCode:500): "),
+			wantDeployed: []int{2},
+		},
+		{
+			name:        "Lookup Error With Rollback",
+			destroyMode: destroyModeRollback,
+			listErr:     errors.New("sendRequest failed (HTTP StatusCode:500): "),
+			expectErr:   true,
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			ctx := context.Background()
+			mock := newMockRulesEngine()
+			mock.deployRequests = c.deployRequests
+			mock.listErr = c.listErr
+			config := internal.ProviderConfig{
+				ServiceOverrides: map[string]any{"rulesengine": mock},
+			}
+
+			d := testPolicyData(t)
+			d.Set("destroy_mode", c.destroyMode)
+			d.Set("previous_policy_id", c.previousID)
+
+			diags := ResourcePolicyCreate(ctx, d, config)
+			if diags.HasError() != c.expectErr {
+				t.Fatalf("expected error %t, got %v", c.expectErr, diags)
+			}
+
+			if c.expectErr {
+				if len(mock.policies) != 0 {
+					t.Errorf("expected no policy to be added, got %v", mock.policies)
+				}
+				return
+			}
+
+			if got := d.Get("previous_policy_id"); got != c.wantPreviousID {
+				t.Errorf("expected previous_policy_id %q, got %q",
+					c.wantPreviousID, got)
+			}
+
+			if len(c.deleteMode) > 0 {
+				d.Set("destroy_mode", c.deleteMode)
+			}
+
+			diags = ResourcePolicyDelete(ctx, d, config)
+			if diags.HasError() != c.expectDeleteErr {
+				t.Fatalf("delete: expected error %t, got %v",
+					c.expectDeleteErr, diags)
+			}
+
+			if !c.expectDeleteErr && len(d.Id()) != 0 {
+				t.Errorf("delete: expected the ID to be cleared, got %q", d.Id())
+			}
+
+			deployed := make([]int, 0)
+			for _, deploy := range mock.deploys[1:] {
+				deployed = append(deployed, deploy.PolicyID)
+			}
+
+			if !reflect.DeepEqual(deployed, c.wantDeployed) {
+				t.Errorf("delete: expected policies %v to be deployed, got %v",
+					c.wantDeployed, deployed)
+			}
+		})
+	}
+}
+
+// TestResourcePolicy_DestroyModeUpgrade checks that policies managed before
+// destroy_mode was added plan no change once refreshed.
+func TestResourcePolicy_DestroyModeUpgrade(t *testing.T) {
+	t.Parallel()
+
+	const policy = `{"platform":"http_large","rules":[{"matches":[{"features":[{"type":"feature.comment","value":"a"}],"type":"match.always"}],"name":"rule 1"}]}`
+
+	ctx := context.Background()
+	r := ResourceRulesEngineV4Policy()
+
+	stored := map[string]any{}
+	if err := json.Unmarshal([]byte(policy), &stored); err != nil {
+		t.Fatal(err)
+	}
+	stored["id"] = "1"
+
+	mock := newMockRulesEngine()
+	mock.policies[1] = stored
+
+	config := internal.ProviderConfig{
+		ServiceOverrides: map[string]any{"rulesengine": mock},
+	}
+
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":             "1",
+			"account_number": "ABCD",
+			"deploy_to":      "staging",
+			"policy":         policy,
+		},
+	}
+	raw := terraform.NewResourceConfigRaw(map[string]any{
+		"account_number": "ABCD",
+		"deploy_to":      "staging",
+		"policy":         policy,
+	})
+
+	destroyModeDiff := func(state *terraform.InstanceState) *terraform.ResourceAttrDiff {
+		t.Helper()
+
+		diff, err := r.Diff(ctx, state, raw, config)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if diff == nil {
+			return nil
+		}
+
+		return diff.Attributes["destroy_mode"]
+	}
+
+	if destroyModeDiff(state) == nil {
+		t.Fatal("expected a destroy_mode diff before the state is refreshed")
+	}
+
+	d := r.Data(state)
+	if diags := ResourcePolicyRead(ctx, d, config); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	if got := d.Get("destroy_mode"); got != destroyModePlaceholder {
+		t.Errorf("expected destroy_mode %q, got %q", destroyModePlaceholder, got)
+	}
+
+	if got := destroyModeDiff(d.State()); got != nil {
+		t.Errorf("expected no destroy_mode diff, got %+v", got)
+	}
+}
+
+// TestResourcePolicyUpdate_DestroyModeOnly checks that changing destroy_mode
+// alone does not deploy the policy again.
+func TestResourcePolicyUpdate_DestroyModeOnly(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := ResourceRulesEngineV4Policy()
+	mock := newMockRulesEngine()
+	config := internal.ProviderConfig{
+		ServiceOverrides: map[string]any{"rulesengine": mock},
+	}
+
+	d := testPolicyData(t)
+	if diags := ResourcePolicyCreate(ctx, d, config); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	raw := map[string]any{
+		"account_number": "ABCD",
+		"deploy_to":      "staging",
+		"policy":         d.Get("policy"),
+		"destroy_mode":   destroyModeAbandon,
+	}
+
+	state := d.State()
+	diff, err := r.Diff(ctx, state, terraform.NewResourceConfigRaw(raw), config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff == nil || diff.Attributes["destroy_mode"] == nil {
+		t.Fatalf("expected a destroy_mode diff, got %+v", diff)
+	}
+
+	state, diags := r.Apply(ctx, state, diff, config)
+	if diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+
+	if got := state.Attributes["destroy_mode"]; got != destroyModeAbandon {
+		t.Errorf("expected destroy_mode %q, got %q", destroyModeAbandon, got)
+	}
+
+	if len(mock.policies) != 1 || len(mock.deploys) != 1 {
+		t.Errorf("expected only the created policy to be deployed, got "+
+			"policies %v and deploys %+v", mock.policies, mock.deploys)
+	}
+}
+
+// TestResourcePolicyUpdate_DeployTarget checks that previous_policy_id is
+// looked up again when the policy moves to another environment.
+func TestResourcePolicyUpdate_DeployTarget(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := ResourceRulesEngineV4Policy()
+	mock := newMockRulesEngine()
+	mock.deployRequests = testDeployRequests()
+	config := internal.ProviderConfig{
+		ServiceOverrides: map[string]any{"rulesengine": mock},
+	}
+
+	d := testPolicyData(t)
+	if diags := ResourcePolicyCreate(ctx, d, config); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	if got := d.Get("previous_policy_id"); got != "7" {
+		t.Fatalf("expected previous_policy_id %q, got %q", "7", got)
+	}
+
+	state := d.State()
+	diff, err := r.Diff(
+		ctx,
+		state,
+		terraform.NewResourceConfigRaw(map[string]any{
+			"account_number": "ABCD",
+			"deploy_to":      "production",
+			"policy":         d.Get("policy"),
+		}),
+		config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if diff == nil || diff.Attributes["previous_policy_id"] == nil {
+		t.Fatalf("expected a previous_policy_id diff, got %+v", diff)
+	}
+
+	state, diags := r.Apply(ctx, state, diff, config)
+	if diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+
+	if got := state.Attributes["previous_policy_id"]; got != "9" {
+		t.Errorf("expected previous_policy_id %q, got %q", "9", got)
+	}
+}
+
+func TestResourcePolicyCreate_DeployError(t *testing.T) {
+	t.Parallel()
+
+	mock := newMockRulesEngine()
+	mock.deployErr = errors.New("sendRequest failed (HTTP StatusCode:400): ")
+	config := internal.ProviderConfig{
+		ServiceOverrides: map[string]any{"rulesengine": mock},
+	}
+
+	diags := ResourcePolicyCreate(context.Background(), testPolicyData(t), config)
+	if !diags.HasError() {
+		t.Fatal("expected an error, but got none")
+	}
+}
+
+func testRuleBlockPolicyData(t *testing.T) *schema.ResourceData {
+	t.Helper()
+
+	return schema.TestResourceDataRaw(
+		t,
+		ResourceRulesEngineV4Policy().Schema,
+		map[string]any{
+			"account_number": "ABCD",
+			"deploy_to":      "staging",
+			"platform":       "http_large",
+			"rule": []any{
+				map[string]any{
+					"name": "rule 1",
+					"match": []any{
+						map[string]any{
+							"type": "match.always",
+							"feature": []any{
+								map[string]any{
+									"type":  "feature.comment",
+									"value": "test",
+								},
+							},
+						},
+					},
+				},
+				map[string]any{
+					"description": "redirects",
+					"match": []any{
+						map[string]any{
+							"type": "select.first-match",
+							"match": []any{
+								map[string]any{
+									"type":  "match.request.request-header.literal",
+									"value": "a b",
+									"parameters": map[string]any{
+										"name":        "X-Test",
+										"ignore_case": "true",
+									},
+									"feature": []any{
+										map[string]any{
+											"type": "feature.url.url-redirect",
+											"parameters": map[string]any{
+												"code":   "301",
+												"source": "/a",
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+}
+
+func TestResourcePolicyLifecycle_RuleBlocks(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	mock := newMockRulesEngine()
+	config := internal.ProviderConfig{
+		ServiceOverrides: map[string]any{"rulesengine": mock},
+	}
+	d := testRuleBlockPolicyData(t)
+	wantRules := d.Get("rule")
+
+	if diags := ResourcePolicyCreate(ctx, d, config); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	policy := mock.policies[1]
+	delete(policy, "id")
+	delete(policy, "@type")
+	delete(policy, "name")
+
+	want := map[string]any{
+		"platform": "http_large",
+		"state":    "locked",
+		"rules": []any{
+			map[string]any{
+				"name": "rule 1",
+				"matches": []any{
+					map[string]any{
+						"type": "match.always",
+						"features": []any{
+							map[string]any{
+								"type":  "feature.comment",
+								"value": "test",
+							},
+						},
+					},
+				},
+			},
+			map[string]any{
+				"description": "redirects",
+				"matches": []any{
+					map[string]any{
+						"type": "select.first-match",
+						"matches": []any{
+							map[string]any{
+								"type":        "match.request.request-header.literal",
+								"value":       "a b",
+								"name":        "X-Test",
+								"ignore_case": true,
+								"features": []any{
+									map[string]any{
+										"type":   "feature.url.url-redirect",
+										"code":   "301",
+										"source": "/a",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(policy, want) {
+		t.Errorf("create: expected policy %v, got %v", JSONMap(want), JSONMap(policy))
+	}
+
+	if got := d.Get("policy").(string); len(got) != 0 {
+		t.Errorf("expected policy to be left unset, got %s", got)
+	}
+
+	if got := d.Get("rule"); !reflect.DeepEqual(got, wantRules) {
+		t.Errorf("read: expected rules %v, got %v", wantRules, got)
+	}
+
+	if got := d.Get("platform"); got != "http_large" {
+		t.Errorf("read: expected platform http_large, got %v", got)
+	}
+}
+
+func Test_flattenRules(t *testing.T) {
+	t.Parallel()
+
+	nest := func(depth int) []any {
+		match := map[string]any{"type": "match.always"}
+		for i := 1; i < depth; i++ {
+			match = map[string]any{
+				"type":    "select.first-match",
+				"matches": []any{match},
+			}
+		}
+
+		return []any{map[string]any{"matches": []any{match}}}
+	}
+
+	tests := []struct {
+		name  string
+		rules []any
+		// want is the expanded rule blocks, if they differ from rules.
+		want    []any
+		wantErr bool
+	}{
+		{
+			name:  "Nested To Max Depth",
+			rules: nest(maxMatchDepth),
+		},
+		{
+			name:    "Nested Too Deep",
+			rules:   nest(maxMatchDepth + 1),
+			wantErr: true,
+		},
+		{
+			name: "Non-String Values",
+			rules: []any{map[string]any{
+				"matches": []any{map[string]any{
+					"type":        "match.always",
+					"ignore_case": false,
+					"duration":    float64(30),
+				}},
+			}},
+			want: []any{map[string]any{
+				"matches": []any{map[string]any{
+					"type":        "match.always",
+					"ignore_case": false,
+					"duration":    "30",
+				}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			rules, err := flattenRules(tt.rules)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, but got none")
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := tt.want
+			if want == nil {
+				want = tt.rules
+			}
+
+			got := expandRules(rules)
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("expected %v, got %v", want, got)
+			}
+		})
+	}
+}
+
+func Test_diffPolicies(t *testing.T) {
+	t.Parallel()
+
+	oldPolicy := `{
+		"name": "tf-ABCD-staging-http_large-1",
+		"platform": "http_large",
+		"rules": [
+			{"name": "kept", "matches": [{"type": "match.always",
+				"features": [{"type": "feature.comment", "value": "a"}]}]},
+			{"name": "removed", "matches": [{"type": "match.always"}]},
+			{"name": "changed", "description": "old", "matches": [
+				{"type": "select.first-match", "matches": [
+					{"type": "match.request.request-header.literal", "value": "a",
+						"features": [
+							{"type": "feature.url.url-redirect", "code": "301"},
+							{"type": "feature.comment", "value": "gone"}
+						]}
+				]}
+			]}
+		]
+	}`
+
+	tests := []struct {
+		name      string
+		oldPolicy string
+		newPolicy string
+		want      []string
+	}{
+		{
+			name:      "New Policy",
+			newPolicy: oldPolicy,
+			want: []string{
+				`+ rule "kept"`,
+				`+ rule "removed"`,
+				`+ rule "changed"`,
+			},
+		},
+		{
+			name:      "Only Name Changed",
+			oldPolicy: oldPolicy,
+			newPolicy: strings.Replace(oldPolicy, "tf-ABCD-staging-http_large-1", "other", 1),
+		},
+		{
+			name:      "Arrays And Dashes Normalized",
+			oldPolicy: `{"rules": [{"matches": [{"type": "match.always", "ignore_case": "true", "value": "a b"}]}]}`,
+			newPolicy: `{"rules": [{"matches": [{"type": "match.always", "ignore-case": "true", "value": ["a", "b"]}]}]}`,
+		},
+		{
+			name:      "Rules Added, Removed And Changed",
+			oldPolicy: oldPolicy,
+			newPolicy: `{
+				"platform": "adn",
+				"rules": [
+					{"name": "kept", "matches": [{"type": "match.always",
+						"features": [{"type": "feature.comment", "value": "a"}]}]},
+					{"name": "changed", "description": "new", "matches": [
+						{"type": "select.first-match", "matches": [
+							{"type": "match.request.request-header.literal", "value": "b",
+								"features": [
+									{"type": "feature.url.url-redirect", "code": "302"}
+								]}
+						]},
+						{"type": "match.always"}
+					]},
+					{"name": "added", "matches": [{"type": "match.always"}]}
+				]
+			}`,
+			want: []string{
+				"~ policy changed (platform)",
+				`- rule "removed"`,
+				`~ rule "changed" changed (description)`,
+				`~ rule "changed": match 1 (select.first-match) > match 1 (match.request.request-header.literal) changed (value)`,
+				`~ rule "changed": match 1 (select.first-match) > match 1 (match.request.request-header.literal) > feature 1 (feature.url.url-redirect) changed (code)`,
+				`~ rule "changed": match 1 (select.first-match) > match 1 (match.request.request-header.literal) > feature 2 (feature.comment) removed`,
+				`~ rule "changed": match 2 (match.always) added`,
+				`+ rule "added"`,
+			},
+		},
+		{
+			name:      "Unnamed Rules By Position",
+			oldPolicy: `{"rules": [{"matches": [{"type": "match.always"}]}]}`,
+			newPolicy: `{"rules": [{"name": "", "matches": [{"type": "match.always", "value": "x"}]}]}`,
+			want:      []string{"~ rule 1: match 1 (match.always) changed (value)"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			oldPolicy, err := normalizePolicy(tt.oldPolicy, "", "", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			newPolicy, err := normalizePolicy(tt.newPolicy, "", "", nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := diffPolicies(oldPolicy, newPolicy)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected changes\n%s\ngot\n%s",
+					strings.Join(tt.want, "\n"), strings.Join(got, "\n"))
+			}
+		})
+	}
+}
+
+func TestResourcePolicyDiff_PolicyChanges(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := ResourceRulesEngineV4Policy()
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":             "1",
+			"account_number": "ABCD",
+			"deploy_to":      "staging",
+			"policy":         `{"platform":"http_large","rules":[{"matches":[{"features":[{"type":"feature.comment","value":"a"}],"type":"match.always"}],"name":"rule 1"}]}`,
+		},
+	}
+
+	// The policy is moved to rule blocks, changing the comment.
+	config := terraform.NewResourceConfigRaw(map[string]any{
+		"account_number": "ABCD",
+		"deploy_to":      "staging",
+		"platform":       "http_large",
+		"rule": []any{map[string]any{
+			"name": "rule 1",
+			"match": []any{map[string]any{
+				"type": "match.always",
+				"feature": []any{map[string]any{
+					"type":  "feature.comment",
+					"value": "b",
+				}},
+			}},
+		}},
+	})
+
+	diff, err := r.Diff(ctx, state, config, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := diff.Attributes["policy_changes.0"]
+	want := `~ rule "rule 1": match 1 (match.always) > feature 1 (feature.comment) changed (value)`
+	if got == nil || got.New != want || diff.Attributes["policy_changes.#"].New != "1" {
+		t.Errorf("expected policy_changes [%q], got %+v", want, diff.Attributes)
+	}
+}
+
+func TestResourcePolicyDiff_PolicyChangesCleared(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	r := ResourceRulesEngineV4Policy()
+	state := &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":               "1",
+			"account_number":   "ABCD",
+			"deploy_to":        "staging",
+			"policy":           `{"platform":"http_large","rules":[{"matches":[{"features":[{"type":"feature.comment","value":"a"}],"type":"match.always"}],"name":"rule 1"}]}`,
+			"policy_changes.#": "1",
+			"policy_changes.0": `+ rule "rule 1"`,
+		},
+	}
+
+	// The policy is moved to rule blocks without changing its rules.
+	config := terraform.NewResourceConfigRaw(map[string]any{
+		"account_number": "ABCD",
+		"deploy_to":      "staging",
+		"platform":       "http_large",
+		"rule": []any{map[string]any{
+			"name": "rule 1",
+			"match": []any{map[string]any{
+				"type": "match.always",
+				"feature": []any{map[string]any{
+					"type":  "feature.comment",
+					"value": "a",
+				}},
+			}},
+		}},
+	})
+
+	diff, err := r.Diff(ctx, state, config, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := diff.Attributes["policy_changes.#"]
+	if got == nil || got.New != "0" {
+		t.Errorf("expected policy_changes to be cleared, got %+v", diff.Attributes)
+	}
+}
+
+func Test_validatePolicy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		policy     string
+		wantErrors []string
+	}{
+		{
+			name: "Valid",
+			policy: `{"platform": "http_large", "rules": [{"matches": [
+				{"type": "match.request.request-method.literal", "value": "GET POST",
+					"features": [{"type": "feature.url.url-redirect", "code": "301"}]},
+				{"type": "match.select.first-match", "matches": [
+					{"type": "match.request.request-scheme.literal", "value": ["https"]}
+				]}
+			]}]}`,
+		},
+		{
+			name: "Unknown Types",
+			policy: `{"platform": "http_large", "rules": [
+				{"matches": [{"type": "match.always"}]},
+				{"name": "typos", "matches": [
+					{"type": "match.alway"},
+					{"type": "select.first-match", "matches": [{"type": "match.always",
+						"features": [{"type": "feature.coment"}, {"type": "feature.unheard-of"}]}]}
+				]}
+			]}`,
+			wantErrors: []string{
+				`rules[1].matches[0] in rule "typos": unknown match type "match.alway", did you mean "match.always"?`,
+				`rules[1].matches[1].matches[0].features[0] in rule "typos": unknown feature type "feature.coment", did you mean "feature.comment"?`,
+				`rules[1].matches[1].matches[0].features[1] in rule "typos": unknown feature type "feature.unheard-of"`,
+			},
+		},
+		{
+			name: "Platform Support",
+			policy: `{"platform": "adn", "rules": [{"matches": [{"type": "match.always",
+				"features": [{"type": "feature.caching.h264-support", "enabled": true}]}]}]}`,
+			wantErrors: []string{
+				`rules[0].matches[0].features[0]: feature type "feature.caching.h264-support" is not supported on platform "adn", only on: http_large`,
+			},
+		},
+		{
+			name:   "Unknown Platform",
+			policy: `{"platform": "http_huge", "rules": []}`,
+			wantErrors: []string{
+				`platform "http_huge" is not supported, expected one of: adn, http_large, http_small`,
+			},
+		},
+		{
+			name: "Values",
+			policy: `{"platform": "http_large", "rules": [{"matches": [
+				{"type": "match.request.request-method.literal", "value": "GET FETCH",
+					"features": [{"type": "feature.url.url-redirect", "code": 303}]}
+			]}]}`,
+			wantErrors: []string{
+				`rules[0].matches[0]: value is "FETCH", expected one of: CONNECT, DELETE, GET, HEAD, OPTIONS, POST, PUT, TRACE`,
+				`rules[0].matches[0].features[0]: code is "303", expected one of: 301, 302, 307, 308`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			policy := make(map[string]any)
+			if err := json.Unmarshal([]byte(tt.policy), &policy); err != nil {
+				t.Fatal(err)
+			}
+
+			var gotErrors []string
+			for _, err := range validatePolicy(policy, jsonPath) {
+				gotErrors = append(gotErrors, err.Error())
+			}
+
+			if !reflect.DeepEqual(gotErrors, tt.wantErrors) {
+				t.Errorf("expected errors\n%s\ngot\n%s",
+					strings.Join(tt.wantErrors, "\n"),
+					strings.Join(gotErrors, "\n"))
+			}
+		})
+	}
+}
+
+func Test_validatePolicyCatalog(t *testing.T) {
+	t.Parallel()
+
+	warnings, errs := validatePolicyCatalog(
+		`{"platform": "http_large", "rules": [{"matches": [{"type": "match.alwys"}]}]}`,
+		"policy")
+
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %q", warnings)
+	}
+
+	var got []string
+	for _, err := range errs {
+		got = append(got, err.Error())
+	}
+
+	want := []string{`policy: rules[0].matches[0]: unknown match type "match.alwys", did you mean "match.always"?`}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected errors %q, got %q", want, got)
+	}
+}
+
+func Test_validateRuleBlocks(t *testing.T) {
+	t.Parallel()
+
+	ty := ResourceRulesEngineV4Policy().CoreConfigSchema().ImpliedType()
+	config, err := ctyjson.Unmarshal([]byte(`{
+		"deploy_to": "staging",
+		"platform": "adn",
+		"rule": [{"name": "r", "match": [{
+			"type": "match.always",
+			"feature": [{"type": "feature.caching.partial-cache-sharing"}],
+			"match": [{"type": "match.request.request-header.litral"}]
+		}]}]
+	}`), ty)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp schema.ValidateResourceConfigFuncResponse
+	validateRuleBlocks(
+		context.Background(),
+		schema.ValidateResourceConfigFuncRequest{RawConfig: config},
+		&resp)
+
+	var got []string
+	for _, d := range resp.Diagnostics {
+		severity := "error"
+		if d.Severity == diag.Warning {
+			severity = "warning"
+		}
+
+		got = append(got, severity+": "+d.Detail)
+	}
+
+	want := []string{
+		`error: rule[0].match[0].feature[0] in rule "r": feature type "feature.caching.partial-cache-sharing" is not supported on platform "adn", only on: http_large`,
+		`error: rule[0].match[0].match[0] in rule "r": unknown match type "match.request.request-header.litral", did you mean "match.request.request-header.literal"?`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected diagnostics\n%s\ngot\n%s",
+			strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func Test_catalogCoversPlaceholder(t *testing.T) {
+	t.Parallel()
+
+	// Deleting a policy deploys a placeholder, which must pass validation.
+	placeholder := make(map[string]any)
+	err := json.Unmarshal(
+		[]byte(fmt.Sprintf(emptyPolicyFormat, "now", "http_large", "now")),
+		&placeholder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if errs := validatePolicy(placeholder, jsonPath); len(errs) > 0 {
+		t.Errorf("unexpected errors: %v", errs)
+	}
+}
+
+func TestResourcePolicyCreate_WaitForDeploy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		states     []string
+		wantStatus string
+		wantErr    bool
+	}{
+		{
+			name:       "Deployed After Review",
+			states:     []string{"pending_review", "approved"},
+			wantStatus: "deployed",
+		},
+		{
+			name:       "Rejected",
+			states:     []string{"submitted", "rejected"},
+			wantStatus: "rejected",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			mock := newMockRulesEngine()
+			mock.deployStates = tt.states
+			config := internal.ProviderConfig{
+				ServiceOverrides: map[string]any{"rulesengine": mock},
+			}
+			d := testPolicyData(t)
+
+			diags := ResourcePolicyCreate(context.Background(), d, config)
+			if diags.HasError() != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, diags)
+			}
+
+			if len(mock.deployStates) != 0 {
+				t.Errorf("expected every state to be polled, %v left",
+					mock.deployStates)
+			}
+
+			if got := d.Get("deploy_status"); got != tt.wantStatus {
+				t.Errorf("expected deploy_status %q, got %q", tt.wantStatus, got)
+			}
+
+			// A failed deploy leaves the policy in state, to be replaced on
+			// the next apply.
+			if d.Id() != "1" {
+				t.Errorf("expected ID 1, got %q", d.Id())
+			}
+		})
+	}
+}
+
+// TestRulesEngineService_SharedIDSToken checks that deploy requests are
+// retrieved with the token of the SDK's services rather than one of their
+// own.
+func TestRulesEngineService_SharedIDSToken(t *testing.T) {
+	t.Parallel()
+
+	var tokens int32
+	ids := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&tokens, 1)
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"access_token":"abc","expires_in":300}`))
+		}))
+	defer ids.Close()
+
+	var unauthorized int32
+	api := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer abc" {
+				atomic.AddInt32(&unauthorized, 1)
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":"1","@items":[]}`))
+		}))
+	defer api.Close()
+
+	idsURL, _ := url.Parse(ids.URL)
+	apiURL, _ := url.Parse(api.URL)
+	config := internal.ProviderConfig{
+		IdsClientID:     "id",
+		IdsClientSecret: "secret",
+		IdsScope:        "ec.rules",
+		IdsURL:          idsURL,
+		APIURL:          apiURL,
+		Services:        internal.NewServiceRegistry(),
+	}
+
+	svc, err := buildRulesEngineService(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	params := rulesengine.NewGetPolicyParams()
+	params.PolicyID = 1
+	if _, err := svc.GetPolicy(*params); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.GetDeployRequests(listDeployRequestsParams{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, err := svc.GetDeployRequest(getDeployRequestParams{ID: "1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&tokens); got != 1 {
+		t.Errorf("got %d token requests, want 1", got)
+	}
+
+	if got := atomic.LoadInt32(&unauthorized); got != 0 {
+		t.Errorf("got %d unauthorized API calls, want 0", got)
+	}
+}
+
+func TestRulesEngineService_DeployRequestPages(t *testing.T) {
+	t.Parallel()
+
+	// Pages link to the next one either by relation or as an array.
+	pages := map[string]string{
+		"": `{"@items":[{"id":"1"},{"id":"2"}],
+			"@links":{"next":{"href":"/rules-engine/v1.1/deploy-requests?page=2"}}}`,
+		"2": `{"@items":[{"id":"3"}],
+			"@links":[{"rel":"next","href":"deploy-requests?page=3"}]}`,
+		"3": `{"@items":[{"id":"4"}],"@links":{"first":{"href":"?page=1"}}}`,
+	}
+
+	var requests int32
+	api := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+
+			page, ok := pages[r.URL.Query().Get("page")]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(page))
+		}))
+	defer api.Close()
+
+	apiURL, _ := url.Parse(api.URL)
+	config := internal.ProviderConfig{
+		APIURL: apiURL,
+		ServiceOverrides: map[string]any{
+			"rulesengine": &rulesEngineService{
+				deployRequests: &deployRequestClient{
+					baseURL: *apiURL,
+					client:  internal.ProviderConfig{}.NewRetryableClient(),
+					auth:    staticAuthorization{},
+				},
+			},
+		},
+	}
+
+	svc, err := buildRulesEngineService(config)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := svc.GetDeployRequests(listDeployRequestsParams{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ids := make([]string, 0, len(got))
+	for _, r := range got {
+		ids = append(ids, r.ID)
+	}
+
+	if want := []string{"1", "2", "3", "4"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("expected deploy requests %v, got %v", want, ids)
+	}
+
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("got %d requests, want 3", got)
+	}
+}
+
+type staticAuthorization struct{}
+
+func (staticAuthorization) GetAuthorizationHeader() (string, error) {
+	return "Bearer token", nil
+}
@@ -6,7 +6,6 @@ package waf
 import (
 	"context"
 	"fmt"
-	"log"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
@@ -23,13 +22,19 @@ func ResourceAccessRule() *schema.Resource {
 		ReadContext:   ResourceAccessRuleRead,
 		UpdateContext: ResourceAccessRuleUpdate,
 		DeleteContext: ResourceAccessRuleDelete,
-		Importer:      helper.Import(ResourceAccessRuleRead, "account_number", "id"),
+		Importer: helper.ImportWithLookup(
+			ResourceAccessRuleRead,
+			lookupAccessRule,
+			"account_number",
+			"id"),
+		Timeouts: internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Identifies your account. Find your account number in the upper right-hand corner of the MCC.",
+				Computed:    true,
+				Description: "Identifies your account. Find your account number in the upper right-hand corner of the MCC. Defaults to the provider's `account_number`.",
 			},
 			"allowed_http_methods": {
 				Type:        schema.TypeList,
@@ -303,10 +308,12 @@ func ResourceAccessRuleCreate(
 		return diags
 	}
 
-	log.Printf(
-		"[INFO] Creating WAF Access Rule for Account >> %s",
-		accessRule.CustomerID)
-	helper.LogInstanceAsPrettyJson("[DEBUG] ACCESSRULE", accessRule)
+	logger.Info(ctx, "Creating WAF Access Rule", map[string]any{
+		"account_number": accessRule.CustomerID,
+	})
+	logger.Debug(ctx, "WAF Access Rule request", map[string]any{
+		"access_rule": accessRule,
+	})
 
 	config := m.(internal.ProviderConfig)
 	wafService, err := buildWAFService(config)
@@ -326,7 +333,9 @@ func ResourceAccessRuleCreate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Successfully created WAF Access Rule: %+v", resp)
+	logger.Info(ctx, "Created WAF Access Rule", map[string]any{
+		"response": resp,
+	})
 	d.SetId(resp)
 
 	return ResourceAccessRuleRead(ctx, d, m)
@@ -349,10 +358,10 @@ func ResourceAccessRuleRead(
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Reading WAF Access Rule '%s' for Account >> %s",
-		ruleID,
-		accountNumber)
+	logger.Info(ctx, "Reading WAF Access Rule", map[string]any{
+		"rule_id":        ruleID,
+		"account_number": accountNumber,
+	})
 
 	params := access.NewGetAccessRuleParams()
 	params.AccessRuleID = ruleID
@@ -360,11 +369,12 @@ func ResourceAccessRuleRead(
 	resp, err := wafService.Access.GetAccessRule(params)
 
 	if err != nil {
-		d.SetId("")
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
-	helper.LogInstanceAsPrettyJson("[INFO] Retrieved Rule", resp)
+	logger.Debug(ctx, "Retrieved WAF Access Rule", map[string]any{
+		"access_rule": resp,
+	})
 
 	d.SetId(resp.ID)
 	d.Set("account_number", accountNumber)
@@ -405,11 +415,13 @@ func ResourceAccessRuleUpdate(
 		return diags
 	}
 
-	helper.LogInstanceAsPrettyJson("[DEBUG] ACCESSRULE", accessRule)
-	log.Printf(
-		"[INFO] Updating WAF Access Rule '%s' for Account >> %s",
-		ruleID,
-		accessRule.CustomerID)
+	logger.Debug(ctx, "WAF Access Rule request", map[string]any{
+		"access_rule": accessRule,
+	})
+	logger.Info(ctx, "Updating WAF Access Rule", map[string]any{
+		"rule_id":        ruleID,
+		"account_number": accessRule.CustomerID,
+	})
 
 	config := m.(internal.ProviderConfig)
 	wafService, err := buildWAFService(config)
@@ -428,7 +440,9 @@ func ResourceAccessRuleUpdate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Successfully updated WAF Access Rule: %+v", ruleID)
+	logger.Info(ctx, "Updated WAF Access Rule", map[string]any{
+		"rule_id": ruleID,
+	})
 
 	return ResourceAccessRuleRead(ctx, d, m)
 }
@@ -442,10 +456,10 @@ func ResourceAccessRuleDelete(
 	accountNumber := d.Get("account_number").(string)
 	ruleID := d.Id()
 
-	log.Printf(
-		"[INFO] Deleting WAF Access Rule '%s' for Account >> %s",
-		ruleID,
-		accountNumber)
+	logger.Info(ctx, "Deleting WAF Access Rule", map[string]any{
+		"rule_id":        ruleID,
+		"account_number": accountNumber,
+	})
 
 	config := m.(internal.ProviderConfig)
 	wafService, err := buildWAFService(config)
@@ -3,11 +3,18 @@
 package waf
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"reflect"
 	"terraform-provider-edgecast/edgecast/helper"
+	"terraform-provider-edgecast/edgecast/internal"
 	"testing"
 
+	sdkwaf "github.com/EdgeCast/ec-sdk-go/edgecast/waf"
 	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/access"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func TestExpandAccessControls(t *testing.T) {
@@ -180,3 +187,221 @@ func TestFlattenAccessControls(t *testing.T) {
 		}
 	}
 }
+
+// mockAccessClient is an in-memory access.ClientService.
+type mockAccessClient struct {
+	rules  map[string]access.AccessRule
+	nextID int
+
+	// err, if set, is returned by every call.
+	err error
+}
+
+func newMockAccessClient() *mockAccessClient {
+	return &mockAccessClient{rules: make(map[string]access.AccessRule)}
+}
+
+func (c *mockAccessClient) AddAccessRule(
+	params access.AddAccessRuleParams,
+) (string, error) {
+	if c.err != nil {
+		return "", c.err
+	}
+
+	c.nextID++
+	id := fmt.Sprint(c.nextID)
+	c.rules[id] = params.AccessRule
+
+	return id, nil
+}
+
+func (c *mockAccessClient) GetAllAccessRules(
+	params access.GetAllAccessRulesParams,
+) (*[]access.AccessRuleGetAllOK, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	rules := make([]access.AccessRuleGetAllOK, 0, len(c.rules))
+	for id, rule := range c.rules {
+		rules = append(rules, access.AccessRuleGetAllOK{ID: id, Name: rule.Name})
+	}
+
+	return &rules, nil
+}
+
+func (c *mockAccessClient) GetAccessRule(
+	params access.GetAccessRuleParams,
+) (*access.AccessRuleGetOK, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	rule, ok := c.rules[params.AccessRuleID]
+	if !ok {
+		return nil, errors.New("sendRequest failed (HTTP StatusCode:404): ")
+	}
+
+	return &access.AccessRuleGetOK{ID: params.AccessRuleID, AccessRule: rule}, nil
+}
+
+func (c *mockAccessClient) UpdateAccessRule(
+	params access.UpdateAccessRuleParams,
+) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	if _, ok := c.rules[params.AccessRuleID]; !ok {
+		return errors.New("sendRequest failed (HTTP StatusCode:404): ")
+	}
+
+	c.rules[params.AccessRuleID] = params.AccessRule
+
+	return nil
+}
+
+func (c *mockAccessClient) DeleteAccessRule(
+	params access.DeleteAccessRuleParams,
+) error {
+	if c.err != nil {
+		return c.err
+	}
+
+	delete(c.rules, params.AccessRuleID)
+
+	return nil
+}
+
+func testAccessRuleConfig(client access.ClientService) internal.ProviderConfig {
+	return internal.ProviderConfig{
+		ServiceOverrides: map[string]any{
+			"waf": &sdkwaf.WafService{Access: client},
+		},
+	}
+}
+
+func testAccessRuleData(t *testing.T) *schema.ResourceData {
+	t.Helper()
+
+	return schema.TestResourceDataRaw(t, ResourceAccessRule().Schema, map[string]any{
+		"account_number":                "ABCD",
+		"name":                          "Access Rule #1",
+		"response_header_name":          "x-rule",
+		"allowed_http_methods":          []any{"GET", "POST"},
+		"allowed_request_content_types": []any{"application/json"},
+		"disallowed_extensions":         []any{".bat"},
+		"disallowed_headers":            []any{"x-reserved"},
+		"ip": []any{map[string]any{
+			"blacklist": []any{"10.10.10.114"},
+		}},
+	})
+}
+
+func TestResourceAccessRuleLifecycle(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client := newMockAccessClient()
+	config := testAccessRuleConfig(client)
+	d := testAccessRuleData(t)
+
+	if diags := ResourceAccessRuleCreate(ctx, d, config); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	rule, ok := client.rules[d.Id()]
+	if !ok {
+		t.Fatalf("create: expected rule %q to be added", d.Id())
+	}
+
+	if rule.CustomerID != "ABCD" || rule.Name != "Access Rule #1" {
+		t.Errorf("create: unexpected rule sent: %+v", rule)
+	}
+
+	if got := d.Get("response_header_name"); got != "x-rule" {
+		t.Errorf("create: expected state to be read back, got %v", got)
+	}
+
+	d.Set("name", "Access Rule #2")
+	if diags := ResourceAccessRuleUpdate(ctx, d, config); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+
+	if got := client.rules[d.Id()].Name; got != "Access Rule #2" {
+		t.Errorf("update: expected the new name to be sent, got %q", got)
+	}
+
+	id := d.Id()
+	if diags := ResourceAccessRuleDelete(ctx, d, config); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+
+	if len(client.rules) != 0 || len(d.Id()) != 0 {
+		t.Errorf("delete: expected the rule and ID to be removed")
+	}
+
+	d.SetId(id)
+	if diags := ResourceAccessRuleRead(ctx, d, config); diags.HasError() {
+		t.Fatalf("read after delete: %v", diags)
+	}
+
+	if len(d.Id()) != 0 {
+		t.Errorf("read after delete: expected the ID to be cleared, got %q", d.Id())
+	}
+}
+
+func TestResourceAccessRuleErrors(t *testing.T) {
+	t.Parallel()
+
+	apiErr := errors.New("sendRequest failed (HTTP StatusCode:500): ")
+
+	tests := []struct {
+		name   string
+		op     func(context.Context, *schema.ResourceData, any) diag.Diagnostics
+		wantID string
+	}{
+		{
+			name:   "create clears the ID",
+			op:     ResourceAccessRuleCreate,
+			wantID: "",
+		},
+		{
+			name:   "read keeps the ID",
+			op:     ResourceAccessRuleRead,
+			wantID: "1",
+		},
+		{
+			name:   "update keeps the ID",
+			op:     ResourceAccessRuleUpdate,
+			wantID: "1",
+		},
+		{
+			name:   "delete keeps the ID",
+			op:     ResourceAccessRuleDelete,
+			wantID: "1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			client := newMockAccessClient()
+			client.err = apiErr
+
+			d := testAccessRuleData(t)
+			d.SetId("1")
+
+			diags := tt.op(context.Background(), d, testAccessRuleConfig(client))
+			if !diags.HasError() {
+				t.Fatal("expected an error, but got none")
+			}
+
+			if d.Id() != tt.wantID {
+				t.Errorf("expected ID %q, got %q", tt.wantID, d.Id())
+			}
+		})
+	}
+}
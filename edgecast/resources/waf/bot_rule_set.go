@@ -7,7 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
@@ -30,9 +30,9 @@ func ResourceBotRuleSetCreate(ctx context.Context,
 
 	accountNumber := d.Get("account_number").(string)
 
-	log.Printf(
-		"[INFO] Creating WAF Bot Rule Set for Account >> %s",
-		accountNumber)
+	logger.Info(ctx, "Creating WAF Bot Rule Set", map[string]any{
+		"account_number": accountNumber,
+	})
 
 	botRuleSet := bot.BotRuleSet{
 		Name: d.Get("name").(string),
@@ -45,8 +45,9 @@ func ResourceBotRuleSetCreate(ctx context.Context,
 
 	botRuleSet.Directives = *directive
 
-	log.Printf("[DEBUG] Name: %+v\n", botRuleSet.Name)
-	log.Printf("[DEBUG] Directive(s): %+v\n", botRuleSet.Directives)
+	logger.Debug(ctx, "WAF Bot Rule Set request", map[string]any{
+		"bot_rule_set": botRuleSet,
+	})
 
 	params := bot.NewAddBotRuleSetParams()
 	params.AccountNumber = accountNumber
@@ -57,7 +58,9 @@ func ResourceBotRuleSetCreate(ctx context.Context,
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] %+v", resp)
+	logger.Info(ctx, "Created WAF Bot Rule Set", map[string]any{
+		"response": resp,
+	})
 
 	d.SetId(resp)
 
@@ -75,10 +78,10 @@ func ResourceBotRuleSetRead(ctx context.Context,
 	accountNumber := d.Get("account_number").(string)
 	botRuleSetID := d.Id()
 
-	log.Printf("[INFO] Retrieving Bot Rule Set '%s' for account number %s",
-		botRuleSetID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Retrieving WAF Bot Rule Set", map[string]any{
+		"bot_rule_set_id": botRuleSetID,
+		"account_number":  accountNumber,
+	})
 
 	wafService, err := buildWAFService(config)
 
@@ -92,14 +95,13 @@ func ResourceBotRuleSetRead(ctx context.Context,
 	resp, err := wafService.Bot.GetBotRuleSet(params)
 
 	if err != nil {
-		d.SetId("")
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
-	log.Printf(
-		"[INFO] Successfully retrieved Bot Rule Set '%s': %+v",
-		botRuleSetID,
-		resp)
+	logger.Debug(ctx, "Retrieved WAF Bot Rule Set", map[string]any{
+		"bot_rule_set_id": botRuleSetID,
+		"bot_rule_set":    resp,
+	})
 
 	d.SetId(resp.ID)
 	d.Set("account_number", accountNumber)
@@ -120,10 +122,10 @@ func ResourceBotRuleSetUpdate(ctx context.Context,
 	accountNumber := d.Get("account_number").(string)
 	botRuleSetID := d.Id()
 
-	log.Printf("[INFO] Updating WAF Bot Rule '%s' for Account >> %s",
-		botRuleSetID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Updating WAF Bot Rule Set", map[string]any{
+		"bot_rule_set_id": botRuleSetID,
+		"account_number":  accountNumber,
+	})
 
 	botRuleSet := bot.BotRuleSet{}
 	botRuleSet.Name = d.Get("name").(string)
@@ -134,8 +136,9 @@ func ResourceBotRuleSetUpdate(ctx context.Context,
 	}
 	botRuleSet.Directives = *directives
 
-	log.Printf("[DEBUG] Name: %+v\n", botRuleSet.Name)
-	log.Printf("[DEBUG] Directives: %+v\n", botRuleSet.Directives)
+	logger.Debug(ctx, "WAF Bot Rule Set request", map[string]any{
+		"bot_rule_set": botRuleSet,
+	})
 
 	config := m.(internal.ProviderConfig)
 
@@ -153,9 +156,9 @@ func ResourceBotRuleSetUpdate(ctx context.Context,
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Successfully updated WAF Bot Rule Set: %+v",
-		botRuleSet)
+	logger.Info(ctx, "Updated WAF Bot Rule Set", map[string]any{
+		"bot_rule_set_id": botRuleSetID,
+	})
 
 	return ResourceBotRuleSetRead(ctx, d, m)
 }
@@ -170,10 +173,10 @@ func ResourceBotRuleSetDelete(ctx context.Context,
 	accountNumber := d.Get("account_number").(string)
 	botRuleSetID := d.Id()
 
-	log.Printf("[INFO] Deleting WAF Bot Rule Set ID %s for Account >> %s",
-		botRuleSetID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Deleting WAF Bot Rule Set", map[string]any{
+		"bot_rule_set_id": botRuleSetID,
+		"account_number":  accountNumber,
+	})
 
 	config := m.(internal.ProviderConfig)
 
@@ -190,8 +193,9 @@ func ResourceBotRuleSetDelete(ctx context.Context,
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Successfully deleted WAF Bot Rule Set: %+v", botRuleSetID)
+	logger.Info(ctx, "Deleted WAF Bot Rule Set", map[string]any{
+		"bot_rule_set_id": botRuleSetID,
+	})
 
 	d.SetId("")
 
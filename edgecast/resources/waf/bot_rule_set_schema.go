@@ -5,6 +5,7 @@ package waf
 
 import (
 	"terraform-provider-edgecast/edgecast/helper"
+	"terraform-provider-edgecast/edgecast/internal"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
@@ -16,13 +17,19 @@ func ResourceBotRuleSet() *schema.Resource {
 		ReadContext:   ResourceBotRuleSetRead,
 		UpdateContext: ResourceBotRuleSetUpdate,
 		DeleteContext: ResourceBotRuleSetDelete,
-		Importer:      helper.Import(ResourceBotRuleSetRead, "account_number", "id"),
+		Importer: helper.ImportWithLookup(
+			ResourceBotRuleSetRead,
+			lookupBotRuleSet,
+			"account_number",
+			"id"),
+		Timeouts: internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:         schema.TypeString,
-				Required:     true,
-				Description:  "Identifies your account. Find your account number in the upper right-hand corner of the MCC.",
+				Optional:     true,
+				Computed:     true,
+				Description:  "Identifies your account. Find your account number in the upper right-hand corner of the MCC. Defaults to the provider's `account_number`.",
 				ValidateFunc: validation.StringIsNotWhiteSpace,
 			},
 			"id": {
@@ -9,30 +9,20 @@ import (
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
-	"github.com/EdgeCast/ec-sdk-go/edgecast"
 	sdkwaf "github.com/EdgeCast/ec-sdk-go/edgecast/waf"
 	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules"
 )
 
-// buildWAFService builds the SDK WAF service to managed WAF resources
+// logger writes to the WAF logging subsystem.
+var logger = internal.Logger(internal.SubsystemWAF)
+
+// buildWAFService returns the shared SDK WAF service to manage WAF resources.
+// Each of its rule clients is an interface, so tests can substitute mocks by
+// passing a WafService through ProviderConfig.ServiceOverrides.
 func buildWAFService(
 	config internal.ProviderConfig,
 ) (*sdkwaf.WafService, error) {
-	idsCredentials := edgecast.IDSCredentials{
-		ClientID:     config.IdsClientID,
-		ClientSecret: config.IdsClientSecret,
-		Scope:        config.IdsScope,
-	}
-
-	sdkConfig := edgecast.NewSDKConfig()
-	sdkConfig.APIToken = config.APIToken
-	sdkConfig.IDSCredentials = idsCredentials
-	sdkConfig.BaseAPIURL = *config.APIURL
-	sdkConfig.BaseAPIURLLegacy = *config.APIURLLegacy
-	sdkConfig.BaseIDSURL = *config.IdsURL
-	sdkConfig.UserAgent = config.UserAgent
-
-	return sdkwaf.New(sdkConfig)
+	return internal.GetService(config, "waf", sdkwaf.New)
 }
 
 func expandSecRule(attr interface{}) (*rules.SecRule, error) {
@@ -7,7 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
 	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/custom"
@@ -30,7 +30,9 @@ func ResourceCustomRuleSetCreate(ctx context.Context,
 
 	accountNumber := d.Get("account_number").(string)
 
-	log.Printf("[INFO] Creating WAF Rate Rule for Account >> %s", accountNumber)
+	logger.Info(ctx, "Creating WAF Custom Rule Set", map[string]any{
+		"account_number": accountNumber,
+	})
 
 	customRuleSet := custom.CustomRuleSet{
 		Name: d.Get("name").(string),
@@ -43,8 +45,9 @@ func ResourceCustomRuleSetCreate(ctx context.Context,
 
 	customRuleSet.Directives = *directive
 
-	log.Printf("[DEBUG] Name: %+v\n", customRuleSet.Name)
-	log.Printf("[DEBUG] Directive(s): %+v\n", customRuleSet.Directives)
+	logger.Debug(ctx, "WAF Custom Rule Set request", map[string]any{
+		"custom_rule_set": customRuleSet,
+	})
 
 	params := custom.NewAddCustomRuleSetParams()
 	params.AccountNumber = accountNumber
@@ -55,7 +58,9 @@ func ResourceCustomRuleSetCreate(ctx context.Context,
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] %+v", resp)
+	logger.Info(ctx, "Created WAF Custom Rule Set", map[string]any{
+		"response": resp,
+	})
 
 	d.SetId(resp)
 
@@ -73,10 +78,10 @@ func ResourceCustomRuleSetRead(ctx context.Context,
 	accountNumber := d.Get("account_number").(string)
 	ruleID := d.Id()
 
-	log.Printf("[INFO] Retrieving custom rule %s for account number %s",
-		ruleID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Retrieving WAF Custom Rule Set", map[string]any{
+		"custom_rule_set_id": ruleID,
+		"account_number":     accountNumber,
+	})
 
 	wafService, err := buildWAFService(config)
 
@@ -90,11 +95,13 @@ func ResourceCustomRuleSetRead(ctx context.Context,
 	resp, err := wafService.Custom.GetCustomRuleSet(params)
 
 	if err != nil {
-		d.SetId("")
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
-	log.Printf("[INFO] Successfully retrieved rate rule %s: %+v", ruleID, resp)
+	logger.Debug(ctx, "Retrieved WAF Custom Rule Set", map[string]any{
+		"custom_rule_set_id": ruleID,
+		"custom_rule_set":    resp,
+	})
 
 	d.SetId(resp.ID)
 	d.Set("account_number", accountNumber)
@@ -115,10 +122,10 @@ func ResourceCustomRuleSetUpdate(ctx context.Context,
 	accountNumber := d.Get("account_number").(string)
 	customRuleSetID := d.Id()
 
-	log.Printf("[INFO] Updating WAF Custom Rule Set ID %s for Account >> %s",
-		customRuleSetID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Updating WAF Custom Rule Set", map[string]any{
+		"custom_rule_set_id": customRuleSetID,
+		"account_number":     accountNumber,
+	})
 
 	customRuleSetRequest := custom.CustomRuleSet{}
 	customRuleSetRequest.Name = d.Get("name").(string)
@@ -129,8 +136,9 @@ func ResourceCustomRuleSetUpdate(ctx context.Context,
 	}
 	customRuleSetRequest.Directives = *directives
 
-	log.Printf("[DEBUG] Name: %+v\n", customRuleSetRequest.Name)
-	log.Printf("[DEBUG] Directives: %+v\n", customRuleSetRequest.Directives)
+	logger.Debug(ctx, "WAF Custom Rule Set request", map[string]any{
+		"custom_rule_set": customRuleSetRequest,
+	})
 
 	config := m.(internal.ProviderConfig)
 
@@ -148,9 +156,9 @@ func ResourceCustomRuleSetUpdate(ctx context.Context,
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Successfully updated WAF Custom Rule Set: %+v",
-		customRuleSetRequest)
+	logger.Info(ctx, "Updated WAF Custom Rule Set", map[string]any{
+		"custom_rule_set_id": customRuleSetID,
+	})
 
 	return ResourceCustomRuleSetRead(ctx, d, m)
 }
@@ -165,10 +173,10 @@ func ResourceCustomRuleSetDelete(ctx context.Context,
 	accountNumber := d.Get("account_number").(string)
 	customRuleID := d.Id()
 
-	log.Printf("[INFO] Deleting WAF Custom Rule Set ID %s for Account >> %s",
-		customRuleID,
-		accountNumber,
-	)
+	logger.Info(ctx, "Deleting WAF Custom Rule Set", map[string]any{
+		"custom_rule_set_id": customRuleID,
+		"account_number":     accountNumber,
+	})
 
 	config := m.(internal.ProviderConfig)
 
@@ -185,8 +193,9 @@ func ResourceCustomRuleSetDelete(ctx context.Context,
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Successfully deleted WAF Custom Rule Set: %+v", customRuleID)
+	logger.Info(ctx, "Deleted WAF Custom Rule Set", map[string]any{
+		"custom_rule_set_id": customRuleID,
+	})
 
 	d.SetId("")
 
@@ -0,0 +1,156 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package waf
+
+import (
+	"context"
+	"errors"
+
+	"terraform-provider-edgecast/edgecast/helper"
+	"terraform-provider-edgecast/edgecast/internal"
+
+	sdkwaf "github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/access"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/bot"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/custom"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/managed"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/rate"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// lookupService returns the WAF service and account number used to look up
+// a rule by name during import.
+func lookupService(
+	d *schema.ResourceData,
+	m interface{},
+) (*sdkwaf.WafService, string, error) {
+	accountNumber := d.Get("account_number").(string)
+	if len(accountNumber) == 0 {
+		return nil, "", errors.New(
+			"account_number is required to import a rule by name")
+	}
+
+	config := m.(internal.ProviderConfig)
+	config.AccountNumber = accountNumber
+
+	svc, err := buildWAFService(config)
+
+	return svc, accountNumber, err
+}
+
+// lookupAccessRule returns the ID of the access rule with the given name.
+func lookupAccessRule(
+	_ context.Context,
+	d *schema.ResourceData,
+	m interface{},
+	name string,
+) (string, error) {
+	svc, accountNumber, err := lookupService(d, m)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := svc.Access.GetAllAccessRules(
+		access.GetAllAccessRulesParams{AccountNumber: accountNumber})
+	if err != nil {
+		return "", err
+	}
+
+	return helper.FindIDByName("access rule", name, *resp,
+		func(r access.AccessRuleGetAllOK) string { return r.Name },
+		func(r access.AccessRuleGetAllOK) string { return r.ID })
+}
+
+// lookupRateRule returns the ID of the rate rule with the given name.
+func lookupRateRule(
+	_ context.Context,
+	d *schema.ResourceData,
+	m interface{},
+	name string,
+) (string, error) {
+	svc, accountNumber, err := lookupService(d, m)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := svc.Rate.GetAllRateRules(
+		rate.GetAllRateRulesParams{AccountNumber: accountNumber})
+	if err != nil {
+		return "", err
+	}
+
+	return helper.FindIDByName("rate rule", name, *resp,
+		func(r rate.RateRuleGetAllOK) string { return r.Name },
+		func(r rate.RateRuleGetAllOK) string { return r.ID })
+}
+
+// lookupManagedRule returns the ID of the managed rule with the given name.
+func lookupManagedRule(
+	_ context.Context,
+	d *schema.ResourceData,
+	m interface{},
+	name string,
+) (string, error) {
+	svc, accountNumber, err := lookupService(d, m)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := svc.Managed.GetAllManagedRules(
+		managed.GetAllManagedRulesParams{AccountNumber: accountNumber})
+	if err != nil {
+		return "", err
+	}
+
+	return helper.FindIDByName("managed rule", name, *resp,
+		func(r managed.ManagedRuleLight) string { return r.Name },
+		func(r managed.ManagedRuleLight) string { return r.ID })
+}
+
+// lookupCustomRuleSet returns the ID of the custom rule set with the given
+// name.
+func lookupCustomRuleSet(
+	_ context.Context,
+	d *schema.ResourceData,
+	m interface{},
+	name string,
+) (string, error) {
+	svc, accountNumber, err := lookupService(d, m)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := svc.Custom.GetAllCustomRuleSets(
+		custom.GetAllCustomRuleSetsParams{AccountNumber: accountNumber})
+	if err != nil {
+		return "", err
+	}
+
+	return helper.FindIDByName("custom rule set", name, *resp,
+		func(r custom.CustomRuleSetGetAllOK) string { return r.Name },
+		func(r custom.CustomRuleSetGetAllOK) string { return r.ID })
+}
+
+// lookupBotRuleSet returns the ID of the bot rule set with the given name.
+func lookupBotRuleSet(
+	_ context.Context,
+	d *schema.ResourceData,
+	m interface{},
+	name string,
+) (string, error) {
+	svc, accountNumber, err := lookupService(d, m)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := svc.Bot.GetAllBotRuleSets(
+		bot.GetAllBotRuleSetsParams{AccountNumber: accountNumber})
+	if err != nil {
+		return "", err
+	}
+
+	return helper.FindIDByName("bot rule set", name, *resp,
+		func(r bot.BotRuleSetGetAllOK) string { return r.Name },
+		func(r bot.BotRuleSetGetAllOK) string { return r.ID })
+}
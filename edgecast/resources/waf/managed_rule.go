@@ -7,7 +7,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
@@ -24,13 +23,19 @@ func ResourceManagedRule() *schema.Resource {
 		ReadContext:   ResourceManagedRuleRead,
 		UpdateContext: ResourceManagedRuleUpdate,
 		DeleteContext: ResourceManagedRuleDelete,
-		Importer:      helper.Import(ResourceManagedRuleRead, "account_number", "id"),
+		Importer: helper.ImportWithLookup(
+			ResourceManagedRuleRead,
+			lookupManagedRule,
+			"account_number",
+			"id"),
+		Timeouts: internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Identifies your account. Find your account number in the upper right-hand corner of the MCC.",
+				Computed:    true,
+				Description: "Identifies your account. Find your account number in the upper right-hand corner of the MCC. Defaults to the provider's `account_number`.",
 			},
 			"name": {
 				Type:        schema.TypeString,
@@ -240,7 +245,9 @@ func ResourceManagedRuleCreate(ctx context.Context, d *schema.ResourceData, m in
 
 	accountNumber := d.Get("account_number").(string)
 
-	log.Printf("[INFO] Creating WAF Managed Rule for Account >> %s", accountNumber)
+	logger.Info(ctx, "Creating WAF Managed Rule", map[string]any{
+		"account_number": accountNumber,
+	})
 
 	managedRule := managed.ManagedRule{}
 
@@ -286,13 +293,9 @@ func ResourceManagedRuleCreate(ctx context.Context, d *schema.ResourceData, m in
 		}
 	}
 
-	log.Printf("[DEBUG] Name: %+v\n", managedRule.Name)
-	log.Printf("[DEBUG] RulesetID: %+v\n", managedRule.RulesetID)
-	log.Printf("[DEBUG] Ruleset Version: %+v\n", managedRule.RulesetVersion)
-	log.Printf("[DEBUG] Disabled Rules: %+v\n", managedRule.DisabledRules)
-	log.Printf("[DEBUG] General Settings: %+v\n", managedRule.GeneralSettings)
-	log.Printf("[DEBUG] Policies: %+v\n", managedRule.Policies)
-	log.Printf("[DEBUG] Rule Target Updates: %+v\n", managedRule.RuleTargetUpdates)
+	logger.Debug(ctx, "WAF Managed Rule request", map[string]any{
+		"managed_rule": managedRule,
+	})
 
 	if diags.HasError() {
 		return diags
@@ -316,7 +319,9 @@ func ResourceManagedRuleCreate(ctx context.Context, d *schema.ResourceData, m in
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Successfully created WAF Managed Rule: %+v", resp)
+	logger.Info(ctx, "Created WAF Managed Rule", map[string]any{
+		"response": resp,
+	})
 
 	d.SetId(resp)
 
@@ -330,7 +335,10 @@ func ResourceManagedRuleRead(ctx context.Context, d *schema.ResourceData, m inte
 	accountNumber := d.Get("account_number").(string)
 	ruleID := d.Id()
 
-	log.Printf("[INFO] Reading WAF Managed Rule ID %s for Account >> %s", ruleID, accountNumber)
+	logger.Info(ctx, "Reading WAF Managed Rule", map[string]any{
+		"managed_rule_id": ruleID,
+		"account_number":  accountNumber,
+	})
 
 	// Initialize WAF Service
 	config := m.(internal.ProviderConfig)
@@ -346,10 +354,11 @@ func ResourceManagedRuleRead(ctx context.Context, d *schema.ResourceData, m inte
 	params.ManagedRuleID = ruleID
 	resp, err := wafService.Managed.GetManagedRule(params)
 	if err != nil {
-		d.SetId("")
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
-	log.Printf("[INFO] Retrieved Managed Rule: %+v", resp)
+	logger.Debug(ctx, "Retrieved WAF Managed Rule", map[string]any{
+		"managed_rule": resp,
+	})
 
 	// Store all the values retrieved from the API
 	d.SetId(resp.ID)
@@ -383,7 +392,10 @@ func ResourceManagedRuleUpdate(ctx context.Context, d *schema.ResourceData, m in
 	accountNumber := d.Get("account_number").(string)
 	managedRuleID := d.Id()
 
-	log.Printf("[INFO] Updating WAF Managed Rule ID %s for Account >> %s", managedRuleID, accountNumber)
+	logger.Info(ctx, "Updating WAF Managed Rule", map[string]any{
+		"managed_rule_id": managedRuleID,
+		"account_number":  accountNumber,
+	})
 
 	managedRule := managed.ManagedRule{}
 
@@ -429,13 +441,9 @@ func ResourceManagedRuleUpdate(ctx context.Context, d *schema.ResourceData, m in
 		}
 	}
 
-	log.Printf("[DEBUG] Name: %+v\n", managedRule.Name)
-	log.Printf("[DEBUG] RulesetID: %+v\n", managedRule.RulesetID)
-	log.Printf("[DEBUG] Ruleset Version: %+v\n", managedRule.RulesetVersion)
-	log.Printf("[DEBUG] Disabled Rules: %+v\n", managedRule.DisabledRules)
-	log.Printf("[DEBUG] General Settings: %+v\n", managedRule.GeneralSettings)
-	log.Printf("[DEBUG] Policies: %+v\n", managedRule.Policies)
-	log.Printf("[DEBUG] Rule Target Updates: %+v\n", managedRule.RuleTargetUpdates)
+	logger.Debug(ctx, "WAF Managed Rule request", map[string]any{
+		"managed_rule": managedRule,
+	})
 
 	if diags.HasError() {
 		return diags
@@ -460,7 +468,9 @@ func ResourceManagedRuleUpdate(ctx context.Context, d *schema.ResourceData, m in
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Successfully updated WAF Managed Rule: %+v", managedRule)
+	logger.Info(ctx, "Updated WAF Managed Rule", map[string]any{
+		"managed_rule_id": managedRuleID,
+	})
 
 	return ResourceManagedRuleRead(ctx, d, m)
 }
@@ -471,7 +481,10 @@ func ResourceManagedRuleDelete(ctx context.Context, d *schema.ResourceData, m in
 	accountNumber := d.Get("account_number").(string)
 	managedRuleID := d.Id()
 
-	log.Printf("[INFO] Deleting WAF Managed Rule ID %s for Account >> %s", managedRuleID, accountNumber)
+	logger.Info(ctx, "Deleting WAF Managed Rule", map[string]any{
+		"managed_rule_id": managedRuleID,
+		"account_number":  accountNumber,
+	})
 
 	config := m.(internal.ProviderConfig)
 
@@ -490,8 +503,9 @@ func ResourceManagedRuleDelete(ctx context.Context, d *schema.ResourceData, m in
 		return diag.FromErr(err)
 	}
 
-	log.Printf(
-		"[INFO] Successfully deleted WAF Managed Rule ID: %+v", managedRuleID)
+	logger.Info(ctx, "Deleted WAF Managed Rule", map[string]any{
+		"managed_rule_id": managedRuleID,
+	})
 
 	d.SetId("")
 
@@ -6,7 +6,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
@@ -22,13 +21,19 @@ func ResourceRateRule() *schema.Resource {
 		ReadContext:   ResourceRateRuleRead,
 		UpdateContext: ResourceRateRuleUpdate,
 		DeleteContext: ResourceRateRuleDelete,
-		Importer:      helper.Import(ResourceRateRuleRead, "account_number", "id"),
+		Importer: helper.ImportWithLookup(
+			ResourceRateRuleRead,
+			lookupRateRule,
+			"account_number",
+			"id"),
+		Timeouts: internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Identifies your account. Find your account number in the upper right-hand corner of the MCC.",
+				Computed:    true,
+				Description: "Identifies your account. Find your account number in the upper right-hand corner of the MCC. Defaults to the provider's `account_number`.",
 			},
 			"duration_sec": {
 				Type:         schema.TypeInt,
@@ -188,16 +193,12 @@ func ResourceRateRuleCreate(
 		d.SetId("")
 		return diag.FromErr(err)
 	}
-	log.Printf(
-		"[INFO] Creating WAF Rate Rule for Account >> %s",
-		rule.CustomerID)
-	log.Printf("[DEBUG] Customer ID: %+v \n", rule.CustomerID)
-	log.Printf("[DEBUG] Disabled: %+v\n", rule.Disabled)
-	log.Printf("[DEBUG] DurationSec: %+v\n", rule.DurationSec)
-	log.Printf("[DEBUG] Name: %+v\n", rule.Name)
-	log.Printf("[DEBUG] Num: %+v\n", rule.Num)
-	log.Printf("[DEBUG] Keys: %+v\n", rule.Keys)
-	log.Printf("[DEBUG] ConditionGroups: %+v\n", rule.ConditionGroups)
+	logger.Info(ctx, "Creating WAF Rate Rule", map[string]any{
+		"account_number": rule.CustomerID,
+	})
+	logger.Debug(ctx, "WAF Rate Rule request", map[string]any{
+		"rate_rule": rule,
+	})
 
 	params := rate.NewAddRateRuleParams()
 	params.AccountNumber = accountNumber
@@ -209,7 +210,9 @@ func ResourceRateRuleCreate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] %+v", resp)
+	logger.Info(ctx, "Created WAF Rate Rule", map[string]any{
+		"response": resp,
+	})
 	d.SetId(resp)
 	return ResourceRateRuleRead(ctx, d, m)
 }
@@ -223,10 +226,10 @@ func ResourceRateRuleRead(
 	config := m.(internal.ProviderConfig)
 	accountNumber := d.Get("account_number").(string)
 	ruleID := d.Id()
-	log.Printf(
-		"[INFO] Retrieving Rate Rule '%s' for account number %s",
-		ruleID,
-		accountNumber)
+	logger.Info(ctx, "Retrieving WAF Rate Rule", map[string]any{
+		"rate_rule_id":   ruleID,
+		"account_number": accountNumber,
+	})
 	wafService, err := buildWAFService(config)
 
 	if err != nil {
@@ -239,10 +242,13 @@ func ResourceRateRuleRead(
 	resp, err := wafService.Rate.GetRateRule(params)
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
-	log.Printf("[INFO] Successfully retrieved rate rule %s: %+v", ruleID, resp)
+	logger.Debug(ctx, "Retrieved WAF Rate Rule", map[string]any{
+		"rate_rule_id": ruleID,
+		"rate_rule":    resp,
+	})
 	d.SetId(resp.ID)
 	d.Set("account_number", accountNumber)
 	d.Set("duration_sec", resp.DurationSec)
@@ -274,17 +280,13 @@ func ResourceRateRuleUpdate(
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	log.Printf(
-		"[INFO] Updating WAF Rate Rule %s for Account >> %s",
-		ruleID,
-		rule.CustomerID)
-	log.Printf("[DEBUG] Customer ID: %+v \n", rule.CustomerID)
-	log.Printf("[DEBUG] Disabled: %+v\n", rule.Disabled)
-	log.Printf("[DEBUG] DurationSec: %+v\n", rule.DurationSec)
-	log.Printf("[DEBUG] Name: %+v\n", rule.Name)
-	log.Printf("[DEBUG] Num: %+v\n", rule.Num)
-	log.Printf("[DEBUG] Keys: %+v\n", rule.Keys)
-	log.Printf("[DEBUG] ConditionGroups: %+v\n", rule.ConditionGroups)
+	logger.Info(ctx, "Updating WAF Rate Rule", map[string]any{
+		"rate_rule_id":   ruleID,
+		"account_number": rule.CustomerID,
+	})
+	logger.Debug(ctx, "WAF Rate Rule request", map[string]any{
+		"rate_rule": rule,
+	})
 
 	params := rate.NewUpdateRateRuleParams()
 	params.AccountNumber = accountNumber
@@ -296,7 +298,9 @@ func ResourceRateRuleUpdate(
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Rate Rule Updated Successfully: %+v", rule)
+	logger.Info(ctx, "Updated WAF Rate Rule", map[string]any{
+		"rate_rule_id": ruleID,
+	})
 	return ResourceRateRuleRead(ctx, d, m)
 }
 
@@ -6,7 +6,6 @@ package waf
 import (
 	"context"
 	"errors"
-	"log"
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
 
@@ -23,12 +22,14 @@ func ResourceScopes() *schema.Resource {
 		UpdateContext: ResourceScopesUpdate,
 		DeleteContext: ResourceScopesDelete,
 		Importer:      helper.Import(ResourceScopesRead, "account_number", "id"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 
 		Schema: map[string]*schema.Schema{
 			"account_number": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				Description: "Identifies your account. Find your account number in the upper right-hand corner of the MCC.",
+				Computed:    true,
+				Description: "Identifies your account. Find your account number in the upper right-hand corner of the MCC. Defaults to the provider's `account_number`.",
 			},
 			"scope": {
 				Type:     schema.TypeList,
@@ -202,6 +203,7 @@ func ResourceScopes() *schema.Resource {
 						"recaptcha_secret_key": {
 							Type:        schema.TypeString,
 							Optional:    true,
+							Sensitive:   true,
 							Description: "Indicates the secret key assigned to the bot manager with recaptcha type defined within the BotManagerConfigId property.",
 						},
 						"recaptcha_site_key": {
@@ -520,16 +522,22 @@ func ResourceScopesRead(
 	}
 
 	accountNumber := d.Get("account_number").(string)
-	log.Printf("[INFO] Getting WAF Scopes for Account >> %s", accountNumber)
+	logger.Info(ctx, "Retrieving WAF Scopes", map[string]any{
+		"account_number": accountNumber,
+	})
+
 	resp, err := wafService.Scopes.GetAllScopes(scopes.GetAllScopesParams{
 		AccountNumber: accountNumber,
 	})
 
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
-	log.Printf("[INFO] Successfully retrieved WAF Scopes: %+v", resp)
+	logger.Debug(ctx, "Retrieved WAF Scopes", map[string]any{
+		"scopes": resp,
+	})
+
 	flattenedScopes, err := flattenScopes(resp)
 
 	if err != nil {
@@ -930,12 +938,18 @@ func modifyAllScopes(
 	accountNumber string,
 	scps []scopes.Scope,
 ) error {
-	log.Printf("[INFO] Modifying WAF Scopes for Account >> %s", accountNumber)
+	logger.Info(ctx, "Modifying WAF Scopes", map[string]any{
+		"account_number": accountNumber,
+	})
+
 	payload := scopes.Scopes{
 		CustomerID: accountNumber,
 		Scopes:     scps,
 	}
-	logScopes(payload)
+	logger.Debug(ctx, "WAF Scopes request", map[string]any{
+		"scopes": payload,
+	})
+
 	config := m.(internal.ProviderConfig)
 	wafService, err := buildWAFService(config)
 
@@ -949,7 +963,10 @@ func modifyAllScopes(
 		return err
 	}
 
-	log.Printf("[INFO] Successfully modified WAF Scopes: %+v", resp)
+	logger.Info(ctx, "Modified WAF Scopes", map[string]any{
+		"response": resp,
+	})
+
 	return nil
 }
 
@@ -963,156 +980,3 @@ func readScopes(d *schema.ResourceData) ([]scopes.Scope, error) {
 	}
 	return nil, errors.New("scopes not found or incorrectly formatted")
 }
-
-func logScopes(s scopes.Scopes) {
-	log.Printf("[DEBUG] Customer ID: %+v \n", s.CustomerID)
-	log.Printf("[DEBUG] Name: %+v \n", s.Name)
-	log.Printf("[DEBUG] Scopes: (%d items) \n\n", len(s.Scopes))
-	for _, sc := range s.Scopes {
-		logScope(sc)
-	}
-}
-
-func logScope(s scopes.Scope) {
-	log.Printf("[DEBUG] \tID: %+v \n", s.ID)
-	log.Printf("[DEBUG] \tName: %+v \n", s.Name)
-	log.Print("[DEBUG] \tHost: \n\n")
-	logMatchCondition(s.Host)
-	log.Print("[DEBUG] \tPath: \n\n")
-	logMatchCondition(s.Path)
-	log.Print("[DEBUG] \tLimits: \n\n")
-	if s.Limits != nil {
-		for _, l := range *s.Limits {
-			logLimit(l)
-		}
-	}
-
-	if s.ACLAuditID != nil {
-		log.Printf("[DEBUG] \tACL Audit ID: %+v \n", *s.ACLAuditID)
-	}
-	if s.ACLAuditAction != nil {
-		log.Print("[DEBUG] \tACL Audit Action: \n\n")
-		logAuditAction(*s.ACLAuditAction)
-	}
-	if s.ACLProdID != nil {
-		log.Printf("[DEBUG] \tACL Prod ID: %+v \n", *s.ACLProdID)
-	}
-	if s.ACLProdAction != nil {
-		log.Print("[DEBUG] \tACL Prod Action: \n\n")
-		logProdAction(*s.ACLProdAction)
-	}
-
-	if s.ReCaptchaActionName != nil {
-		log.Printf("[DEBUG] \tRecaptcha action name: %+v \n", *s.ReCaptchaActionName)
-	}
-	if s.ReCaptchaSecretKey != nil {
-		log.Printf("[DEBUG] \tRecaptcha secret key: %+v \n", *s.ReCaptchaSecretKey)
-	}
-	if s.ReCaptchaSiteKey != nil {
-		log.Printf("[DEBUG] \tRecaptcha site key: %+v \n", *s.ReCaptchaSiteKey)
-	}
-
-	if s.BotManagerConfigId != nil {
-		log.Printf("[DEBUG] \tBot manager config ID: %+v \n", *s.BotManagerConfigId)
-	}
-
-	if s.ProfileAuditID != nil {
-		log.Printf("[DEBUG] \tProfile Audit ID: %+v \n", *s.ProfileAuditID)
-	}
-	if s.ProfileAuditAction != nil {
-		log.Print("[DEBUG] \tProfile Audit Action: \n\n")
-		logAuditAction(*s.ProfileAuditAction)
-	}
-	if s.ProfileProdID != nil {
-		log.Printf("[DEBUG] \tProfile Prod ID: %+v \n", *s.ProfileProdID)
-	}
-	if s.ProfileProdAction != nil {
-		log.Print("[DEBUG] \tProfile Prod Action: \n\n")
-		logProdAction(*s.ProfileProdAction)
-	}
-
-	if s.RuleAuditID != nil {
-		log.Printf("[DEBUG] \tRule Audit ID: %+v \n", *s.RuleAuditID)
-	}
-	if s.RuleAuditAction != nil {
-		log.Print("[DEBUG] \tRule Audit Action: \n\n")
-		logAuditAction(*s.RuleAuditAction)
-	}
-	if s.RuleProdID != nil {
-		log.Printf("[DEBUG] \tRule Prod ID: %+v \n", *s.RuleProdID)
-	}
-	if s.RuleProdAction != nil {
-		log.Print("[DEBUG] \tRule Prod Action: \n\n")
-		logProdAction(*s.RuleProdAction)
-	}
-}
-
-func logAuditAction(a scopes.AuditAction) {
-	log.Printf("[DEBUG] \t\t ID: %+v \n", a.ID)
-	log.Printf("[DEBUG] \t\t Name: %+v \n", a.Name)
-	log.Printf("[DEBUG] \t\t Type: %+v \n", a.Type)
-}
-
-func logProdAction(a scopes.ProdAction) {
-	log.Printf("[DEBUG] \t\t ID: %+v \n", a.ID)
-	log.Printf("[DEBUG] \t\t Name: %+v \n", a.Name)
-	log.Printf("[DEBUG] \t\t ENFType: %+v \n", a.ENFType)
-	if a.ResponseBodyBase64 != nil {
-		log.Printf(
-			"[DEBUG] \t\t ResponseBodyBase64: %+v \n",
-			*a.ResponseBodyBase64)
-	}
-	if a.ResponseHeaders != nil {
-		log.Printf("[DEBUG] \t\t ResponseHeaders: %+v \n", *a.ResponseHeaders)
-	}
-	if a.Status != nil {
-		log.Printf("[DEBUG] \t\t Status: %+v \n", *a.Status)
-	}
-	if a.URL != nil {
-		log.Printf("[DEBUG] \t\t URL: %+v \n", *a.URL)
-	}
-	if a.ValidForSec != nil {
-		log.Printf("[DEBUG] \t\t ValidForSec: %+v \n", *a.ValidForSec)
-	}
-}
-
-func logLimit(l scopes.Limit) {
-	log.Printf("[DEBUG] \t\t ID: %+v \n", l.ID)
-	log.Printf("[DEBUG] \t\t DurationSec: %+v \n", l.Action.DurationSec)
-	log.Printf("[DEBUG] \t\t ENFType: %+v \n", l.Action.ENFType)
-	log.Printf("[DEBUG] \t\t Name: %+v \n", l.Action.Name)
-	if l.Action.ResponseBodyBase64 != nil {
-		log.Printf(
-			"[DEBUG] \t\t ResponseBodyBase64: %+v \n",
-			*l.Action.ResponseBodyBase64)
-	}
-	if l.Action.ResponseHeaders != nil {
-		log.Printf(
-			"[DEBUG] \t\t ResponseHeaders: %+v \n",
-			*l.Action.ResponseHeaders)
-	}
-	if l.Action.Status != nil {
-		log.Printf("[DEBUG] \t\t Status: %+v \n", *l.Action.Status)
-	}
-	if l.Action.URL != nil {
-		log.Printf("[DEBUG] \t\t URL: %+v \n", *l.Action.URL)
-	}
-}
-
-func logMatchCondition(m scopes.MatchCondition) {
-	if m.IsCaseInsensitive != nil {
-		log.Printf(
-			"[DEBUG] \t\t IsCaseInsensitive: %+v \n",
-			*m.IsCaseInsensitive)
-	}
-	if m.IsNegated != nil {
-		log.Printf("[DEBUG] \t\t IsNegated: %+v \n", *m.IsNegated)
-	}
-	if m.Value != nil {
-		log.Printf("[DEBUG] \t\t Value: %+v \n", *m.Value)
-	}
-	if m.Values != nil {
-		log.Printf("[DEBUG] \t\t Values: %+v \n", *m.Values)
-	}
-	log.Printf("[DEBUG] \t\t Type: %+v \n", m.Type)
-}
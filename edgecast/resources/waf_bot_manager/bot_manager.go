@@ -7,7 +7,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
 
 	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/edgecast/internal"
@@ -15,7 +14,6 @@ import (
 	sdkbotmanager "github.com/EdgeCast/ec-sdk-go/edgecast/waf_bot_manager"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/kr/pretty"
 )
 
 func ResourceBotManager() *schema.Resource {
@@ -25,6 +23,7 @@ func ResourceBotManager() *schema.Resource {
 		UpdateContext: ResourceBotManagerUpdate,
 		DeleteContext: ResourceBotManagerDelete,
 		Importer:      helper.Import(ResourceBotManagerRead, "customer_id", "id"),
+		Timeouts:      internal.DefaultResourceTimeouts(),
 		Schema:        GetBotManagerSchema(),
 	}
 }
@@ -45,7 +44,9 @@ func ResourceBotManagerCreate(ctx context.Context,
 
 	customerID := d.Get("customer_id").(string)
 
-	log.Printf("[INFO] Creating Bot Manager for Account >> %s", customerID)
+	logger.Info(ctx, "Creating Bot Manager", map[string]any{
+		"customer_id": customerID,
+	})
 
 	// Read from TF state.
 	botManagerState, errs := ExpandBotManager(d)
@@ -64,8 +65,10 @@ func ResourceBotManagerCreate(ctx context.Context,
 	}
 
 	botManagerID := cresp.Id
-	log.Printf("[INFO] bot manager created: %# v\n", pretty.Formatter(cresp))
-	log.Printf("[INFO] bot manager id: %d\n", botManagerID)
+	logger.Info(ctx, "Created Bot Manager", map[string]any{
+		"bot_manager_id": botManagerID,
+		"response":       cresp,
+	})
 
 	d.SetId(*botManagerID)
 
@@ -89,10 +92,10 @@ func ResourceBotManagerRead(ctx context.Context,
 	customerID := d.Get("customer_id").(string)
 	botManagerID := d.Id()
 
-	log.Printf(
-		"[INFO] Retrieving Bot Manager ID %s for Account >> %s",
-		botManagerID,
-		customerID)
+	logger.Info(ctx, "Retrieving Bot Manager", map[string]any{
+		"bot_manager_id": botManagerID,
+		"customer_id":    customerID,
+	})
 
 	params := sdkbotmanager.NewGetBotManagerParams()
 	params.BotManagerId = botManagerID
@@ -100,10 +103,12 @@ func ResourceBotManagerRead(ctx context.Context,
 
 	resp, err := svc.BotManagers.GetBotManager(params)
 	if err != nil {
-		return diag.FromErr(err)
+		return helper.ReadError(ctx, d, err)
 	}
 
-	log.Printf("[INFO] Retrieved Bot Manager: %# v\n", pretty.Formatter(resp))
+	logger.Debug(ctx, "Retrieved Bot Manager", map[string]any{
+		"bot_manager": resp,
+	})
 
 	err = FlattenBotManager(d, resp)
 	if err != nil {
@@ -120,10 +125,10 @@ func ResourceBotManagerUpdate(ctx context.Context,
 	customerID := d.Get("customer_id").(string)
 	botManagerID := d.Id()
 
-	log.Printf("[INFO] Updating WAF Bot Manager ID %s for Account >> %s",
-		botManagerID,
-		customerID,
-	)
+	logger.Info(ctx, "Updating Bot Manager", map[string]any{
+		"bot_manager_id": botManagerID,
+		"customer_id":    customerID,
+	})
 
 	config := m.(internal.ProviderConfig)
 
@@ -161,10 +166,10 @@ func ResourceBotManagerDelete(ctx context.Context,
 	customerID := d.Get("customer_id").(string)
 	botManagerID := d.Id()
 
-	log.Printf("[INFO] Deleting WAF Bot Manager ID %s for Account >> %s",
-		botManagerID,
-		customerID,
-	)
+	logger.Info(ctx, "Deleting Bot Manager", map[string]any{
+		"bot_manager_id": botManagerID,
+		"customer_id":    customerID,
+	})
 
 	config := m.(internal.ProviderConfig)
 
@@ -182,7 +187,9 @@ func ResourceBotManagerDelete(ctx context.Context,
 		return diag.FromErr(err)
 	}
 
-	log.Printf("[INFO] Successfully deleted WAF Bot Manager: %s", botManagerID)
+	logger.Info(ctx, "Deleted Bot Manager", map[string]any{
+		"bot_manager_id": botManagerID,
+	})
 	d.SetId("")
 
 	return diags
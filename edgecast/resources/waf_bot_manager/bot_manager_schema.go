@@ -17,9 +17,10 @@ func GetBotManagerSchema() map[string]*schema.Schema {
 		},
 		"customer_id": {
 			Type:         schema.TypeString,
-			Required:     true,
+			Optional:     true,
+			Computed:     true,
 			ValidateFunc: validation.StringIsNotWhiteSpace,
-			Description:  "Identifies the customer id.",
+			Description:  "Identifies the customer id. Defaults to the provider's `account_number`.",
 		},
 		"name": {
 			Type:         schema.TypeString,
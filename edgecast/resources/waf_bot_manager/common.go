@@ -6,28 +6,16 @@ package waf_bot_manager
 import (
 	"terraform-provider-edgecast/edgecast/internal"
 
-	"github.com/EdgeCast/ec-sdk-go/edgecast"
 	sdkbotmanager "github.com/EdgeCast/ec-sdk-go/edgecast/waf_bot_manager"
 )
 
-// buildCPSService builds the SDK CPS service to manage CPS
+// logger writes to the WAF logging subsystem.
+var logger = internal.Logger(internal.SubsystemWAF)
+
+// buildBotManagerService returns the shared SDK Bot Manager service to manage
 // resources.
 func buildBotManagerService(
 	config internal.ProviderConfig,
 ) (*sdkbotmanager.Service, error) {
-	idsCredentials := edgecast.IDSCredentials{
-		ClientID:     config.IdsClientID,
-		ClientSecret: config.IdsClientSecret,
-		Scope:        config.IdsScope,
-	}
-
-	sdkConfig := edgecast.NewSDKConfig()
-	sdkConfig.APIToken = config.APIToken
-	sdkConfig.IDSCredentials = idsCredentials
-	sdkConfig.BaseAPIURL = *config.APIURL
-	sdkConfig.BaseAPIURLLegacy = *config.APIURLLegacy
-	sdkConfig.BaseIDSURL = *config.IdsURL
-	sdkConfig.UserAgent = config.UserAgent
-
-	return sdkbotmanager.New(sdkConfig)
+	return internal.GetService(config, "waf_bot_manager", sdkbotmanager.New)
 }
@@ -0,0 +1,35 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package edgecast
+
+import (
+	"context"
+
+	"terraform-provider-edgecast/edgecast/framework"
+
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+)
+
+// ProviderServer returns a function that creates the provider's protocol
+// version 5 server. It combines the SDKv2 provider with the framework
+// provider, so that new resources can be written with the framework.
+func ProviderServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	sdkProvider := Provider()
+
+	// The mux server configures providers in this order, and the framework
+	// provider reuses the SDKv2 provider's configuration.
+	servers := []func() tfprotov5.ProviderServer{
+		sdkProvider.GRPCProvider,
+		providerserver.NewProtocol5(framework.New(Version, sdkProvider)()),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, servers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return muxServer.ProviderServer, nil
+}
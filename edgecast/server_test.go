@@ -0,0 +1,78 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package edgecast
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestProviderServer(t *testing.T) {
+	ctx := context.Background()
+
+	newServer, err := ProviderServer(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error creating server: %v", err)
+	}
+
+	server := newServer()
+
+	// The mux server reports an error if the providers' provider blocks
+	// differ.
+	schemaResp, err := server.GetProviderSchema(
+		ctx,
+		&tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error getting schema: %v", err)
+	}
+
+	checkDiagnostics(t, schemaResp.Diagnostics)
+
+	for _, name := range []string{"edgecast_origin", "edgecast_waf_scopes"} {
+		if _, ok := schemaResp.ResourceSchemas[name]; !ok {
+			t.Errorf("expected resource %s to be served", name)
+		}
+	}
+
+	objectType := schemaResp.Provider.ValueType().(tftypes.Object)
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+
+	values["api_token"] = tftypes.NewValue(tftypes.String, "token")
+	values["account_number"] = tftypes.NewValue(tftypes.String, "A1B2")
+
+	config, err := tfprotov5.NewDynamicValue(
+		objectType,
+		tftypes.NewValue(objectType, values))
+	if err != nil {
+		t.Fatalf("unexpected error building configuration: %v", err)
+	}
+
+	// Both providers are configured; the framework provider fails if the
+	// SDKv2 provider's configuration is not available to it.
+	configResp, err := server.ConfigureProvider(
+		ctx,
+		&tfprotov5.ConfigureProviderRequest{
+			TerraformVersion: "1.5.0",
+			Config:           &config,
+		})
+	if err != nil {
+		t.Fatalf("unexpected error configuring provider: %v", err)
+	}
+
+	checkDiagnostics(t, configResp.Diagnostics)
+}
+
+func checkDiagnostics(t *testing.T, diags []*tfprotov5.Diagnostic) {
+	for _, d := range diags {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			t.Errorf("unexpected error: %s: %s", d.Summary, d.Detail)
+		}
+	}
+}
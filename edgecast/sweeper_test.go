@@ -0,0 +1,731 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package edgecast
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast/internal"
+	"terraform-provider-edgecast/edgecast/resources/dnsroute"
+	"terraform-provider-edgecast/edgecast/resources/rulesengine"
+
+	sdkcps "github.com/EdgeCast/ec-sdk-go/edgecast/cps"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/cps/certificate"
+	sdkedgecname "github.com/EdgeCast/ec-sdk-go/edgecast/edgecname"
+	sdkorigin "github.com/EdgeCast/ec-sdk-go/edgecast/origin"
+	sdkoriginv3 "github.com/EdgeCast/ec-sdk-go/edgecast/originv3"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/routedns"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/shared/enums"
+	sdkwaf "github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/access"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/bot"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/custom"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/managed"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/rate"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/scopes"
+	sdkbotmanager "github.com/EdgeCast/ec-sdk-go/edgecast/waf_bot_manager"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// sweepPrefix is the prefix of the names that tests give the objects they
+// create. Sweepers only delete objects whose names start with it.
+const sweepPrefix = "tf-"
+
+// sweepers holds a sweeper for each resource in buildResourcesMap, except those
+// in unsweepable, keyed by resource name. Dependencies name the sweepers that
+// must run first, e.g. because their objects reference this sweeper's objects.
+//
+// Run them against the account set in the usual EDGECAST_* environment
+// variables with:
+//
+//	go test ./edgecast -v -sweep=all
+var sweepers = map[string]*resource.Sweeper{
+	"edgecast_waf_scopes": {
+		F: sweepWAFScopes,
+	},
+	"edgecast_waf_botmanager": {
+		Dependencies: []string{"edgecast_waf_scopes"},
+		F:            sweepBotManagers,
+	},
+	"edgecast_waf_access_rule": {
+		Dependencies: []string{"edgecast_waf_scopes"},
+		F:            sweepAccessRules,
+	},
+	"edgecast_waf_rate_rule": {
+		Dependencies: []string{"edgecast_waf_scopes"},
+		F:            sweepRateRules,
+	},
+	"edgecast_waf_managed_rule": {
+		Dependencies: []string{"edgecast_waf_scopes"},
+		F:            sweepManagedRules,
+	},
+	"edgecast_waf_custom_rule_set": {
+		Dependencies: []string{"edgecast_waf_scopes"},
+		F:            sweepCustomRuleSets,
+	},
+	"edgecast_waf_bot_rule_set": {
+		Dependencies: []string{
+			"edgecast_waf_scopes",
+			"edgecast_waf_botmanager",
+		},
+		F: sweepBotRuleSets,
+	},
+	"edgecast_edgecname": {
+		F: sweepEdgeCnames,
+	},
+	"edgecast_origin": {
+		Dependencies: []string{"edgecast_edgecname"},
+		F:            sweepOrigins,
+	},
+	"edgecast_originv3_httplarge": {
+		Dependencies: []string{"edgecast_edgecname"},
+		F:            sweepOriginV3Groups,
+	},
+	"edgecast_cps_certificate": {
+		F: sweepCertificates,
+	},
+	"edgecast_dns_masterservergroup": {
+		Dependencies: []string{"edgecast_dns_secondaryzonegroup"},
+		F:            sweepMasterServerGroups,
+	},
+	"edgecast_dns_tsig": {
+		Dependencies: []string{"edgecast_dns_secondaryzonegroup"},
+		F:            sweepTSIGs,
+	},
+	"edgecast_dns_secondaryzonegroup": {
+		F: sweepSecondaryZoneGroups,
+	},
+	"edgecast_dns_zone": {
+		F: sweepZones,
+	},
+	"edgecast_dns_group": {
+		F: sweepGroups,
+	},
+	"edgecast_rules_engine_policy": {
+		F: sweepRulesEnginePolicies,
+	},
+}
+
+// unsweepable lists the resources that have no sweeper, since the API cannot
+// list their objects and so they cannot be found by name.
+var unsweepable = map[string]bool{
+	"edgecast_customer_user": true,
+	"edgecast_customer":      true,
+}
+
+func init() {
+	for name, s := range sweepers {
+		s.Name = name
+		resource.AddTestSweepers(name, s)
+	}
+}
+
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func TestSweepers(t *testing.T) {
+	t.Parallel()
+
+	resources := buildResourcesMap()
+	for name := range resources {
+		_, ok := sweepers[name]
+		if ok && unsweepable[name] {
+			t.Errorf("resource %s has a sweeper but is listed as unsweepable", name)
+		} else if !ok && !unsweepable[name] {
+			t.Errorf("resource %s has no sweeper", name)
+		}
+	}
+
+	for name := range unsweepable {
+		if _, ok := resources[name]; !ok {
+			t.Errorf("unsweepable resource %s does not exist", name)
+		}
+	}
+
+	for name, s := range sweepers {
+		for _, dep := range s.Dependencies {
+			if _, ok := sweepers[dep]; !ok {
+				t.Errorf("sweeper %s depends on unknown sweeper %s", name, dep)
+			}
+		}
+	}
+}
+
+// sweptObject is an object found by a sweeper. attrs holds the attributes,
+// besides the ID, that the resource's Delete function reads.
+type sweptObject struct {
+	id    string
+	name  string
+	attrs map[string]any
+}
+
+// sweepMeta configures the provider from the environment and returns its
+// configuration.
+func sweepMeta() (internal.ProviderConfig, error) {
+	p := Provider()
+
+	diags := p.Configure(
+		context.Background(),
+		terraform.NewResourceConfigRaw(map[string]any{}))
+	if diags.HasError() {
+		return internal.ProviderConfig{}, fmt.Errorf(
+			"configuring provider: %v",
+			diags)
+	}
+
+	return p.Meta().(internal.ProviderConfig), nil
+}
+
+// sweepAccount returns the provider configuration and the account to sweep,
+// or ok=false if no account number is configured.
+func sweepAccount(
+	kind string,
+) (config internal.ProviderConfig, ok bool, err error) {
+	config, err = sweepMeta()
+	if err != nil {
+		return config, false, err
+	}
+
+	if len(config.AccountNumber) == 0 {
+		log.Printf(
+			"[WARN] Skipping %s: %s is not set",
+			kind,
+			internal.EnvVarName("account_number"))
+		return config, false, nil
+	}
+
+	return config, true, nil
+}
+
+// sweepResources deletes the objects whose names start with sweepPrefix
+// through the resource's Delete function, so that sweeping follows the same
+// rules as terraform destroy, e.g. canceling certificates that have not been
+// issued yet. It tries every object and reports those it failed to delete.
+func sweepResources(
+	resourceName string,
+	config internal.ProviderConfig,
+	objects []sweptObject,
+) error {
+	r := Provider().ResourcesMap[resourceName]
+
+	var failed []string
+	for _, obj := range objects {
+		if !strings.HasPrefix(obj.name, sweepPrefix) {
+			continue
+		}
+
+		log.Printf("[INFO] Deleting %s %s (%s)", resourceName, obj.id, obj.name)
+
+		d := r.Data(nil)
+		d.SetId(obj.id)
+
+		for key, value := range obj.attrs {
+			if err := d.Set(key, value); err != nil {
+				return fmt.Errorf("setting %s: %w", key, err)
+			}
+		}
+
+		diags := r.DeleteContext(context.Background(), d, config)
+		if diags.HasError() {
+			log.Printf("[ERROR] Deleting %s %s: %v", resourceName, obj.id, diags)
+			failed = append(failed, obj.id)
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf(
+			"failed to delete %s: %s",
+			resourceName,
+			strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// sweepWAFScopes removes the scopes whose names start with sweepPrefix and
+// keeps the others, since all of an account's scopes are a single object.
+func sweepWAFScopes(string) error {
+	config, ok, err := sweepAccount("WAF scopes")
+	if !ok {
+		return err
+	}
+
+	svc, err := internal.GetService(config, "waf", sdkwaf.New)
+	if err != nil {
+		return err
+	}
+
+	resp, err := svc.Scopes.GetAllScopes(
+		scopes.GetAllScopesParams{AccountNumber: config.AccountNumber})
+	if err != nil {
+		return fmt.Errorf("listing WAF scopes: %w", err)
+	}
+
+	kept := make([]scopes.Scope, 0, len(resp.Scopes))
+	for _, s := range resp.Scopes {
+		if strings.HasPrefix(s.Name, sweepPrefix) {
+			log.Printf("[INFO] Deleting WAF scope %s (%s)", s.ID, s.Name)
+			continue
+		}
+
+		kept = append(kept, s)
+	}
+
+	if len(kept) == len(resp.Scopes) {
+		return nil
+	}
+
+	_, err = svc.Scopes.ModifyAllScopes(scopes.Scopes{
+		CustomerID: config.AccountNumber,
+		Scopes:     kept,
+	})
+	if err != nil {
+		return fmt.Errorf("modifying WAF scopes: %w", err)
+	}
+
+	return nil
+}
+
+func sweepBotManagers(string) error {
+	config, ok, err := sweepAccount("bot managers")
+	if !ok {
+		return err
+	}
+
+	svc, err := internal.GetService(
+		config,
+		"waf_bot_manager",
+		sdkbotmanager.New)
+	if err != nil {
+		return err
+	}
+
+	params := sdkbotmanager.NewGetBotManagersParams()
+	params.CustId = config.AccountNumber
+
+	resp, err := svc.BotManagers.GetBotManagers(params)
+	if err != nil {
+		return fmt.Errorf("listing bot managers: %w", err)
+	}
+
+	objects := make([]sweptObject, 0, len(resp))
+	for _, bm := range resp {
+		objects = append(objects, sweptObject{
+			id:    bm.Id,
+			name:  bm.Name,
+			attrs: map[string]any{"customer_id": config.AccountNumber},
+		})
+	}
+
+	return sweepResources("edgecast_waf_botmanager", config, objects)
+}
+
+// sweepWAFRules lists WAF rules of one kind with list and deletes them.
+func sweepWAFRules(
+	resourceName string,
+	list func(svc *sdkwaf.WafService, account string) ([]sweptObject, error),
+) error {
+	config, ok, err := sweepAccount(resourceName)
+	if !ok {
+		return err
+	}
+
+	svc, err := internal.GetService(config, "waf", sdkwaf.New)
+	if err != nil {
+		return err
+	}
+
+	objects, err := list(svc, config.AccountNumber)
+	if err != nil {
+		return fmt.Errorf("listing %s: %w", resourceName, err)
+	}
+
+	for i := range objects {
+		objects[i].attrs = map[string]any{
+			"account_number": config.AccountNumber,
+		}
+	}
+
+	return sweepResources(resourceName, config, objects)
+}
+
+func sweepAccessRules(string) error {
+	return sweepWAFRules(
+		"edgecast_waf_access_rule",
+		func(svc *sdkwaf.WafService, account string) ([]sweptObject, error) {
+			resp, err := svc.Access.GetAllAccessRules(
+				access.GetAllAccessRulesParams{AccountNumber: account})
+			if err != nil {
+				return nil, err
+			}
+
+			objects := make([]sweptObject, 0, len(*resp))
+			for _, rule := range *resp {
+				objects = append(objects, sweptObject{id: rule.ID, name: rule.Name})
+			}
+
+			return objects, nil
+		})
+}
+
+func sweepRateRules(string) error {
+	return sweepWAFRules(
+		"edgecast_waf_rate_rule",
+		func(svc *sdkwaf.WafService, account string) ([]sweptObject, error) {
+			resp, err := svc.Rate.GetAllRateRules(
+				rate.GetAllRateRulesParams{AccountNumber: account})
+			if err != nil {
+				return nil, err
+			}
+
+			objects := make([]sweptObject, 0, len(*resp))
+			for _, rule := range *resp {
+				objects = append(objects, sweptObject{id: rule.ID, name: rule.Name})
+			}
+
+			return objects, nil
+		})
+}
+
+func sweepManagedRules(string) error {
+	return sweepWAFRules(
+		"edgecast_waf_managed_rule",
+		func(svc *sdkwaf.WafService, account string) ([]sweptObject, error) {
+			resp, err := svc.Managed.GetAllManagedRules(
+				managed.GetAllManagedRulesParams{AccountNumber: account})
+			if err != nil {
+				return nil, err
+			}
+
+			objects := make([]sweptObject, 0, len(*resp))
+			for _, rule := range *resp {
+				objects = append(objects, sweptObject{id: rule.ID, name: rule.Name})
+			}
+
+			return objects, nil
+		})
+}
+
+func sweepCustomRuleSets(string) error {
+	return sweepWAFRules(
+		"edgecast_waf_custom_rule_set",
+		func(svc *sdkwaf.WafService, account string) ([]sweptObject, error) {
+			resp, err := svc.Custom.GetAllCustomRuleSets(
+				custom.GetAllCustomRuleSetsParams{AccountNumber: account})
+			if err != nil {
+				return nil, err
+			}
+
+			objects := make([]sweptObject, 0, len(*resp))
+			for _, rule := range *resp {
+				objects = append(objects, sweptObject{id: rule.ID, name: rule.Name})
+			}
+
+			return objects, nil
+		})
+}
+
+func sweepBotRuleSets(string) error {
+	return sweepWAFRules(
+		"edgecast_waf_bot_rule_set",
+		func(svc *sdkwaf.WafService, account string) ([]sweptObject, error) {
+			resp, err := svc.Bot.GetAllBotRuleSets(
+				bot.GetAllBotRuleSetsParams{AccountNumber: account})
+			if err != nil {
+				return nil, err
+			}
+
+			objects := make([]sweptObject, 0, len(*resp))
+			for _, rule := range *resp {
+				objects = append(objects, sweptObject{id: rule.ID, name: rule.Name})
+			}
+
+			return objects, nil
+		})
+}
+
+// sweptPlatforms are the delivery platforms whose edge CNAMEs and origins are
+// swept.
+var sweptPlatforms = []enums.Platform{
+	enums.HttpLarge,
+	enums.HttpSmall,
+	enums.ADN,
+}
+
+func sweepEdgeCnames(string) error {
+	config, ok, err := sweepAccount("edge CNAMEs")
+	if !ok {
+		return err
+	}
+
+	svc, err := internal.GetService(config, "edgecname", sdkedgecname.New)
+	if err != nil {
+		return err
+	}
+
+	var objects []sweptObject
+	for _, platform := range sweptPlatforms {
+		params := sdkedgecname.NewGetAllEdgeCnameParams()
+		params.AccountNumber = config.AccountNumber
+		params.Platform = platform
+
+		resp, err := svc.GetAllEdgeCnames(*params)
+		if err != nil {
+			return fmt.Errorf("listing %s edge CNAMEs: %w", platform, err)
+		}
+
+		for _, cname := range *resp {
+			objects = append(objects, sweptObject{
+				id:    strconv.Itoa(cname.ID),
+				name:  cname.Name,
+				attrs: map[string]any{"account_number": config.AccountNumber},
+			})
+		}
+	}
+
+	return sweepResources("edgecast_edgecname", config, objects)
+}
+
+func sweepOrigins(string) error {
+	config, ok, err := sweepAccount("origins")
+	if !ok {
+		return err
+	}
+
+	svc, err := internal.GetService(config, "origin", sdkorigin.New)
+	if err != nil {
+		return err
+	}
+
+	var objects []sweptObject
+	for _, platform := range sweptPlatforms {
+		params := sdkorigin.NewGetAllOriginsParams()
+		params.AccountNumber = config.AccountNumber
+		params.MediaTypeID = platform
+
+		resp, err := svc.GetAllOrigins(*params)
+		if err != nil {
+			return fmt.Errorf("listing %s origins: %w", platform, err)
+		}
+
+		for _, o := range *resp {
+			objects = append(objects, sweptObject{
+				id:   strconv.Itoa(o.ID),
+				name: o.DirectoryName,
+				attrs: map[string]any{
+					"account_number": config.AccountNumber,
+					"media_type_id":  int(platform),
+				},
+			})
+		}
+	}
+
+	return sweepResources("edgecast_origin", config, objects)
+}
+
+func sweepOriginV3Groups(string) error {
+	config, err := sweepMeta()
+	if err != nil {
+		return err
+	}
+
+	svc, err := internal.GetService(config, "originv3", sdkoriginv3.New)
+	if err != nil {
+		return err
+	}
+
+	groups, err := svc.HttpLargeOnly.GetAllHttpLargeGroups()
+	if err != nil {
+		return fmt.Errorf("listing origin groups: %w", err)
+	}
+
+	objects := make([]sweptObject, 0, len(groups))
+	for _, g := range groups {
+		objects = append(objects, sweptObject{
+			id:   strconv.Itoa(int(g.GetId())),
+			name: g.GetName(),
+		})
+	}
+
+	return sweepResources("edgecast_originv3_httplarge", config, objects)
+}
+
+func sweepCertificates(string) error {
+	config, err := sweepMeta()
+	if err != nil {
+		return err
+	}
+
+	svc, err := internal.GetService(config, "cps", sdkcps.New)
+	if err != nil {
+		return err
+	}
+
+	var objects []sweptObject
+	for page := int32(1); ; page++ {
+		params := certificate.NewCertificateFindParams()
+		params.Page = &page
+
+		resp, err := svc.Certificate.CertificateFind(params)
+		if err != nil {
+			return fmt.Errorf("listing certificates: %w", err)
+		}
+
+		for _, cert := range resp.Items {
+			objects = append(objects, sweptObject{
+				id:   strconv.FormatInt(cert.ID, 10),
+				name: cert.CertificateLabel,
+			})
+		}
+
+		if len(resp.Items) == 0 || len(objects) >= int(resp.TotalItems) {
+			break
+		}
+	}
+
+	return sweepResources("edgecast_cps_certificate", config, objects)
+}
+
+func sweepMasterServerGroups(string) error {
+	config, ok, err := sweepAccount("master server groups")
+	if !ok {
+		return err
+	}
+
+	svc, err := internal.GetService(config, "routedns", routedns.New)
+	if err != nil {
+		return err
+	}
+
+	params := routedns.NewGetAllMasterServerGroupsParams()
+	params.AccountNumber = config.AccountNumber
+
+	resp, err := svc.GetAllMasterServerGroups(*params)
+	if err != nil {
+		return fmt.Errorf("listing master server groups: %w", err)
+	}
+
+	objects := make([]sweptObject, 0, len(*resp))
+	for _, g := range *resp {
+		objects = append(objects, sweptObject{
+			id:    strconv.Itoa(g.MasterGroupID),
+			name:  g.Name,
+			attrs: map[string]any{"account_number": config.AccountNumber},
+		})
+	}
+
+	return sweepResources("edgecast_dns_masterservergroup", config, objects)
+}
+
+// sweepRouteObjects lists Route DNS objects of one kind with list and deletes
+// them.
+func sweepRouteObjects(
+	resourceName string,
+	list func(
+		config internal.ProviderConfig,
+		accountNumber string,
+	) ([]dnsroute.ListedObject, error),
+) error {
+	config, ok, err := sweepAccount(resourceName)
+	if !ok {
+		return err
+	}
+
+	listed, err := list(config, config.AccountNumber)
+	if err != nil {
+		return fmt.Errorf("listing %s: %w", resourceName, err)
+	}
+
+	objects := make([]sweptObject, 0, len(listed))
+	for _, obj := range listed {
+		objects = append(objects, sweptObject{
+			id:    strconv.Itoa(obj.ID),
+			name:  obj.Name,
+			attrs: map[string]any{"account_number": config.AccountNumber},
+		})
+	}
+
+	return sweepResources(resourceName, config, objects)
+}
+
+func sweepTSIGs(string) error {
+	return sweepRouteObjects("edgecast_dns_tsig", dnsroute.TSIGs)
+}
+
+func sweepSecondaryZoneGroups(string) error {
+	return sweepRouteObjects(
+		"edgecast_dns_secondaryzonegroup",
+		dnsroute.SecondaryZoneGroups)
+}
+
+func sweepZones(string) error {
+	return sweepRouteObjects("edgecast_dns_zone", dnsroute.Zones)
+}
+
+// sweepGroups deletes the load balancing and failover groups that do not
+// belong to a zone. Their Delete function finds them by group_id and
+// group_product_type rather than by ID.
+func sweepGroups(string) error {
+	config, ok, err := sweepAccount("DNS groups")
+	if !ok {
+		return err
+	}
+
+	groups, err := dnsroute.Groups(config, config.AccountNumber)
+	if err != nil {
+		return fmt.Errorf("listing DNS groups: %w", err)
+	}
+
+	objects := make([]sweptObject, 0, len(groups))
+	for _, g := range groups {
+		objects = append(objects, sweptObject{
+			id:   strconv.Itoa(g.ID),
+			name: g.Name,
+			attrs: map[string]any{
+				"account_number":     config.AccountNumber,
+				"group_id":           g.ID,
+				"group_product_type": g.GroupProductType,
+			},
+		})
+	}
+
+	return sweepResources("edgecast_dns_group", config, objects)
+}
+
+// sweepRulesEnginePolicies replaces the policies in effect whose names start
+// with sweepPrefix with placeholder policies, as destroying the resource
+// does, since policies cannot be deleted.
+func sweepRulesEnginePolicies(string) error {
+	config, ok, err := sweepAccount("Rules Engine policies")
+	if !ok {
+		return err
+	}
+
+	policies, err := rulesengine.DeployedPolicies(config, config.AccountNumber)
+	if err != nil {
+		return fmt.Errorf("listing Rules Engine policies: %w", err)
+	}
+
+	objects := make([]sweptObject, 0, len(policies))
+	for _, p := range policies {
+		objects = append(objects, sweptObject{
+			id:   p.ID,
+			name: p.Name,
+			attrs: map[string]any{
+				"account_number": config.AccountNumber,
+				"deploy_to":      p.Environment,
+				"destroy_mode":   "placeholder",
+			},
+		})
+	}
+
+	return sweepResources("edgecast_rules_engine_policy", config, objects)
+}
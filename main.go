@@ -4,8 +4,13 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"log"
+	"os"
+
 	"terraform-provider-edgecast/edgecast"
+	"terraform-provider-edgecast/edgecast/export"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
 )
@@ -14,6 +19,12 @@ import (
 //go:generate go run github.com/hashicorp/terraform-plugin-docs/cmd/tfplugindocs
 
 func main() {
+	if len(os.Args) > 1 {
+		if run, ok := export.Commands[os.Args[1]]; ok {
+			os.Exit(run(os.Args[2:], os.Stdout, os.Stderr))
+		}
+	}
+
 	var debugMode bool
 
 	flag.BoolVar(
@@ -24,7 +35,12 @@ func main() {
 	)
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{ProviderFunc: edgecast.Provider}
+	providerServer, err := edgecast.ProviderServer(context.Background())
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts := &plugin.ServeOpts{GRPCProviderFunc: providerServer}
 
 	if debugMode {
 		opts.Debug = true
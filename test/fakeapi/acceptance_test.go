@@ -0,0 +1,93 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast"
+	"terraform-provider-edgecast/test/fakeapi"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// TestAccWAFAccessRule runs a Terraform plan and apply against the fake. Like
+// all acceptance tests it needs TF_ACC and a terraform binary, but no account.
+func TestAccWAFAccessRule(t *testing.T) {
+	s := fakeapi.New()
+	defer s.Close()
+
+	config := func(name string) string {
+		return s.ProviderConfig() + fmt.Sprintf(`
+resource "edgecast_waf_access_rule" "test" {
+  account_number                = %q
+  name                          = %q
+  response_header_name          = "x-rule"
+  allowed_http_methods          = ["GET", "POST"]
+  allowed_request_content_types = ["application/json"]
+  disallowed_extensions         = [".bat"]
+  disallowed_headers            = ["x-reserved"]
+
+  ip {
+    blacklist = ["10.10.10.114"]
+  }
+}
+`, testAccount, name)
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV5ProviderFactories: map[string]func() (
+			tfprotov5.ProviderServer,
+			error,
+		){
+			"edgecast": func() (tfprotov5.ProviderServer, error) {
+				newServer, err := edgecast.ProviderServer(context.Background())
+				if err != nil {
+					return nil, err
+				}
+
+				return newServer(), nil
+			},
+		},
+		CheckDestroy: func(*terraform.State) error {
+			if n := s.Count(fakeapi.KindWAFAccessRule, testAccount); n != 0 {
+				return fmt.Errorf("%d access rules left after destroy", n)
+			}
+
+			return nil
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: config("Access Rule #1"),
+				Check: resource.TestCheckResourceAttr(
+					"edgecast_waf_access_rule.test", "name", "Access Rule #1"),
+			},
+			{
+				Config: config("Access Rule #2"),
+				Check: resource.TestCheckResourceAttr(
+					"edgecast_waf_access_rule.test", "name", "Access Rule #2"),
+			},
+			{
+				ResourceName:      "edgecast_waf_access_rule.test",
+				ImportState:       true,
+				ImportStateIdFunc: importID,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+// importID returns the account_number:id import ID of the access rule.
+func importID(state *terraform.State) (string, error) {
+	rs, ok := state.RootModule().Resources["edgecast_waf_access_rule.test"]
+	if !ok {
+		return "", fmt.Errorf("access rule not found in state")
+	}
+
+	return testAccount + ":" + rs.Primary.ID, nil
+}
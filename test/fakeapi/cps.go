@@ -0,0 +1,432 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KindCPSCertificate is the kind of CPS certificates, for use with Count. CPS
+// certificates belong to the account of the IDS token, so Count them under the
+// account "". Canceled certificates are kept with the status "Deleted".
+const KindCPSCertificate = "cps_certificate"
+
+// Certificate statuses that the provider handles differently on delete.
+const (
+	CertificateStatusProcessing = "Processing"
+	CertificateStatusDCV        = "DomainControlValidation"
+	CertificateStatusActive     = "Active"
+	CertificateStatusDeleted    = "Deleted"
+)
+
+const (
+	cpsBasePath = "/sec/cps/v2.0"
+
+	// cpsDateFormat is the date format of the CPS API.
+	cpsDateFormat = "2006-01-02T15:04:05.000Z07:00"
+)
+
+// cpsAppendix holds the lookup lists of the CPS appendix endpoints, by path.
+var cpsAppendix = map[string][]map[string]any{
+	"cancel-actions": namedEntities(
+		"Cancel", "Delete"),
+	"certificate-authorities": namedEntities(
+		"DigiCert"),
+	"certificate-statuses": namedEntities(
+		"Processing", "DomainControlValidation", "OtherValidation",
+		"Deployment", "Active", "Expired", "Deleted"),
+	"dcv-types": namedEntities(
+		"Email", "DnsCnameToken", "DnsTxtToken"),
+	"domain-statuses": namedEntities(
+		"Pending", "Submitted", "Validated", "Expired"),
+	"order-statuses": namedEntities(
+		"Pending", "Processing", "Completed", "Canceled"),
+	"product-types": namedEntities(
+		"SingleDomain", "MultiDomain", "Wildcard"),
+	"request-types": namedEntities(
+		"Enrollment", "Renewal"),
+	"validation-statuses": namedEntities(
+		"Pending", "Validated", "Rejected"),
+	"validation-types": namedEntities(
+		"DV", "OV", "EV"),
+	"country-codes": {
+		{"country": "United States", "two_letter_code": "US"},
+		{"country": "Canada", "two_letter_code": "CA"},
+		{"country": "United Kingdom", "two_letter_code": "GB"},
+	},
+}
+
+func namedEntities(names ...string) []map[string]any {
+	entities := make([]map[string]any, 0, len(names))
+	for i, name := range names {
+		entities = append(entities, map[string]any{"id": i + 1, "name": name})
+	}
+
+	return entities
+}
+
+// registerCPS registers the Certificate Provisioning System endpoints.
+func (s *Server) registerCPS() {
+	const path = cpsBasePath
+
+	s.handle(http.MethodPost, path+"/certificates/cdnprovided", s.addCertificate)
+	s.handle(http.MethodGet, path+"/certificates/{id}", s.getCertificate)
+	s.handle(http.MethodPatch, path+"/certificates/{id}", s.patchCertificate)
+	s.handle(http.MethodDelete, path+"/certificates/{id}", s.deleteCertificate)
+	s.handle(http.MethodPut, path+"/certificates/{id}/cancel", s.cancelCertificate)
+	s.handle(http.MethodGet, path+"/certificates/{id}/status", s.getCertificateStatus)
+	s.handle(http.MethodGet, path+"/certificates/{id}/notifications", s.getCertificateNotifications)
+	s.handle(http.MethodPatch, path+"/certificates/{id}/notifications", s.updateCertificateNotifications)
+	s.handle(http.MethodPut, path+"/certificates/{id}/organization", s.updateCertificateOrganization)
+	s.handle(http.MethodGet, path+"/dcv/certificates/{id}", s.getCertificateDCV)
+
+	s.handle(http.MethodGet, path+"/appendix/{name}",
+		func(w http.ResponseWriter, r *request) {
+			items, ok := cpsAppendix[r.vars["name"]]
+			if !ok {
+				writeNotFound(w, "appendix", r.vars["name"])
+				return
+			}
+
+			writeCPSCollection(w, items)
+		})
+}
+
+// SetCertificateStatus sets the status of a CPS certificate, e.g. to drive
+// the provider's cancel or delete flow. It reports whether the certificate
+// exists.
+func (s *Server) SetCertificateStatus(id string, status string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cert, ok := s.lookup(KindCPSCertificate, "", id)
+	if !ok {
+		return false
+	}
+
+	cert["status"] = status
+
+	return true
+}
+
+// CertificateStatus returns the status of a CPS certificate and whether it
+// exists.
+func (s *Server) CertificateStatus(id string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cert, ok := s.lookup(KindCPSCertificate, "", id)
+	if !ok {
+		return "", false
+	}
+
+	status, _ := cert["status"].(string)
+
+	return status, true
+}
+
+// addCertificate creates a certificate request. New requests are in the
+// Processing status and have not been placed with the certificate authority.
+func (s *Server) addCertificate(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC()
+	id := s.newID()
+
+	obj["id"] = id
+	obj["@id"] = cpsBasePath + "/certificates/" + strconv.Itoa(id)
+	obj["@type"] = "CdnProvidedCertificate"
+	obj["request_type"] = "Enrollment"
+	obj["created"] = now.Format(cpsDateFormat)
+	obj["last_modified"] = now.Format(cpsDateFormat)
+	obj["expiration_date"] = now.AddDate(1, 0, 0).Format(cpsDateFormat)
+	obj["deployments"] = []any{}
+	obj["status"] = CertificateStatusProcessing
+	obj["notifications"] = []any{}
+	s.setCertificateDomains(obj)
+
+	if org, ok := obj["organization"].(map[string]any); ok {
+		org["id"] = s.newID()
+	}
+
+	s.insert(KindCPSCertificate, "", strconv.Itoa(id), obj)
+
+	writeJSON(w, http.StatusCreated, certificateView(obj))
+}
+
+// setCertificateDomains gives the domains of a certificate IDs and a status.
+func (s *Server) setCertificateDomains(cert map[string]any) {
+	domains, _ := cert["domains"].([]any)
+	for _, item := range domains {
+		domain, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if intValue(domain["id"]) == 0 {
+			domain["id"] = s.newID()
+		}
+
+		domain["status"] = "Pending"
+		domain["created"] = cert["created"]
+	}
+
+	if domains == nil {
+		cert["domains"] = []any{}
+	}
+}
+
+// certificateView returns a certificate without the fields that the fake
+// stores alongside it but the API returns from other endpoints.
+func certificateView(cert map[string]any) map[string]any {
+	view := copyObject(cert)
+	delete(view, "status")
+	delete(view, "notifications")
+
+	return view
+}
+
+// liveCertificate returns a certificate that has not been canceled or deleted.
+func (s *Server) liveCertificate(id string) (map[string]any, bool) {
+	cert, ok := s.lookup(KindCPSCertificate, "", id)
+	if !ok || cert["status"] == CertificateStatusDeleted {
+		return nil, false
+	}
+
+	return cert, true
+}
+
+func (s *Server) getCertificate(w http.ResponseWriter, r *request) {
+	cert, ok := s.liveCertificate(r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindCPSCertificate, r.vars["id"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, certificateView(cert))
+}
+
+// patchCertificate updates the settings of a certificate that are present in
+// the request.
+func (s *Server) patchCertificate(w http.ResponseWriter, r *request) {
+	cert, ok := s.liveCertificate(r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindCPSCertificate, r.vars["id"])
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	for _, key := range []string{
+		"auto_renew", "certificate_label", "description", "dcv_method",
+	} {
+		if v, ok := obj[key]; ok {
+			cert[key] = v
+		}
+	}
+
+	if domains, ok := obj["domains"]; ok && domains != nil {
+		cert["domains"] = domains
+		s.setCertificateDomains(cert)
+	}
+
+	cert["last_modified"] = time.Now().UTC().Format(cpsDateFormat)
+
+	writeJSON(w, http.StatusOK, certificateView(cert))
+}
+
+// deleteCertificate deletes an issued certificate.
+func (s *Server) deleteCertificate(w http.ResponseWriter, r *request) {
+	if _, ok := s.liveCertificate(r.vars["id"]); !ok {
+		writeNotFound(w, KindCPSCertificate, r.vars["id"])
+		return
+	}
+
+	s.remove(KindCPSCertificate, "", r.vars["id"])
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// cancelCertificate cancels a certificate request that has not been issued.
+// The request is kept with the status Deleted, as the API does.
+func (s *Server) cancelCertificate(w http.ResponseWriter, r *request) {
+	cert, ok := s.liveCertificate(r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindCPSCertificate, r.vars["id"])
+		return
+	}
+
+	if status := cert["status"]; status != CertificateStatusProcessing &&
+		status != CertificateStatusDCV && status != "OtherValidation" {
+		writeError(w, http.StatusBadRequest,
+			"certificate %s cannot be canceled in status %v", r.vars["id"], status)
+		return
+	}
+
+	if r.URL.Query().Get("apply") == "true" {
+		cert["status"] = CertificateStatusDeleted
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getCertificateStatus responds with the status of a certificate. Requests
+// that have been placed with the certificate authority have a pending order
+// validation.
+func (s *Server) getCertificateStatus(w http.ResponseWriter, r *request) {
+	cert, ok := s.lookup(KindCPSCertificate, "", r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindCPSCertificate, r.vars["id"])
+		return
+	}
+
+	status, _ := cert["status"].(string)
+	resp := map[string]any{
+		"@type":              "CertificateStatus",
+		"status":             status,
+		"requires_attention": false,
+	}
+
+	switch status {
+	case CertificateStatusDCV, "OtherValidation":
+		resp["order_validation"] = map[string]any{"status": "Pending"}
+	case CertificateStatusActive, "Deployment":
+		resp["order_validation"] = map[string]any{"status": "Validated"}
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (s *Server) getCertificateNotifications(w http.ResponseWriter, r *request) {
+	cert, ok := s.liveCertificate(r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindCPSCertificate, r.vars["id"])
+		return
+	}
+
+	items, _ := cert["notifications"].([]any)
+	writeCPSCollection(w, items)
+}
+
+// updateCertificateNotifications replaces the notification settings of the
+// types in the request.
+func (s *Server) updateCertificateNotifications(w http.ResponseWriter, r *request) {
+	cert, ok := s.liveCertificate(r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindCPSCertificate, r.vars["id"])
+		return
+	}
+
+	var updates []any
+	if err := json.Unmarshal(r.body, &updates); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body: %v", err)
+		return
+	}
+
+	existing, _ := cert["notifications"].([]any)
+	for _, update := range updates {
+		updateType := notificationType(update)
+
+		replaced := false
+		for i, item := range existing {
+			if notificationType(item) == updateType {
+				existing[i] = update
+				replaced = true
+			}
+		}
+
+		if !replaced {
+			existing = append(existing, update)
+		}
+	}
+
+	cert["notifications"] = existing
+
+	writeCPSCollection(w, existing)
+}
+
+func notificationType(v any) string {
+	n, _ := v.(map[string]any)
+	t, _ := n["notification_type"].(string)
+	return t
+}
+
+func (s *Server) updateCertificateOrganization(w http.ResponseWriter, r *request) {
+	cert, ok := s.liveCertificate(r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindCPSCertificate, r.vars["id"])
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	obj["id"] = s.newID()
+	cert["organization"] = obj
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// getCertificateDCV responds with the domain control validation details of
+// the domains in the domain_ids query parameter.
+func (s *Server) getCertificateDCV(w http.ResponseWriter, r *request) {
+	cert, ok := s.liveCertificate(r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindCPSCertificate, r.vars["id"])
+		return
+	}
+
+	wanted := make(map[int]bool)
+	for _, id := range strings.Split(r.URL.Query().Get("domain_ids"), ",") {
+		if i, err := strconv.Atoi(strings.TrimSpace(id)); err == nil {
+			wanted[i] = true
+		}
+	}
+
+	items := make([]any, 0)
+	domains, _ := cert["domains"].([]any)
+	for _, item := range domains {
+		domain, _ := item.(map[string]any)
+		id := intValue(domain["id"])
+		if !wanted[id] {
+			continue
+		}
+
+		items = append(items, map[string]any{
+			"domain_id":  id,
+			"dcv_method": cert["dcv_method"],
+			"dcv_token": map[string]any{
+				"token": "fake-dcv-token-" + strconv.Itoa(id),
+			},
+			"emails": []any{},
+		})
+	}
+
+	writeCPSCollection(w, items)
+}
+
+// writeCPSCollection writes items in the collection format of the CPS API.
+func writeCPSCollection[T any](w http.ResponseWriter, items []T) {
+	if items == nil {
+		items = make([]T, 0)
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"@type":       "Collection",
+		"items":       items,
+		"total_items": len(items),
+	})
+}
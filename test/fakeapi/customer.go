@@ -0,0 +1,340 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// Kinds of customer objects, for use with Count. Customers and customer
+// users are not scoped to an account, so Count them under the account "".
+const (
+	KindCustomer     = "customer"
+	KindCustomerUser = "customer_user"
+)
+
+// Kinds of per-customer settings, keyed by the customer's account number.
+const (
+	kindCustomerServices       = "customer_services"
+	kindCustomerDeliveryRegion = "customer_delivery_region"
+	kindCustomerAccessModules  = "customer_access_modules"
+)
+
+// customerServices is the catalog of services that may be enabled for a
+// customer.
+var customerServices = []map[string]any{
+	{"Id": 1, "Name": "HTTP Large Object", "ParentId": 0},
+	{"Id": 2, "Name": "HTTP Small Object", "ParentId": 0},
+	{"Id": 3, "Name": "Application Delivery Network", "ParentId": 0},
+	{"Id": 9, "Name": "Real-Time Log Delivery", "ParentId": 0},
+	{"Id": 19, "Name": "Web Application Firewall", "ParentId": 0},
+	{"Id": 21, "Name": "Rules Engine", "ParentId": 0},
+}
+
+// registerCustomer registers the customer and customer user endpoints of the
+// legacy Partner API.
+func (s *Server) registerCustomer() {
+	const path = "/v2/pcc/customers"
+
+	s.handle(http.MethodPost, path, s.addCustomer)
+	s.handle(http.MethodGet, path+"/{account_number}", s.getCustomer)
+	s.handle(http.MethodPut, path, s.updateCustomer)
+	s.handle(http.MethodDelete, path, s.deleteCustomer)
+
+	s.handle(http.MethodGet, path+"/services",
+		func(w http.ResponseWriter, r *request) {
+			writeJSON(w, http.StatusOK, customerServices)
+		})
+	s.handle(http.MethodGet, path+"/{account_number}/services", s.getCustomerServices)
+	s.handle(http.MethodPut, path+"/{account_number}/services/{id}", s.updateCustomerService)
+
+	s.handle(http.MethodGet, path+"/{account_number}/deliveryregions", s.getDeliveryRegion)
+	s.handle(http.MethodPut, path+"/deliveryregions", s.updateDeliveryRegion)
+
+	s.handle(http.MethodGet, path+"/{account_number}/accessmodules", s.getAccessModules)
+	s.handle(http.MethodPut, path+"/accessmodules/{id}/status", s.updateAccessModule)
+
+	s.handle(http.MethodPost, path+"/users", s.addCustomerUser)
+	s.handle(http.MethodGet, path+"/users/{id}", s.getCustomerUser)
+	s.handle(http.MethodPut, path+"/users/{id}", s.updateCustomerUser)
+	s.handle(http.MethodDelete, path+"/users/{id}", s.deleteCustomerUser)
+}
+
+// addCustomer creates a customer and responds with its account number, the
+// hexadecimal form of its ID.
+func (s *Server) addCustomer(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	id := s.newID()
+	account := fmt.Sprintf("%X", id)
+
+	obj["Id"] = id
+	obj["HexId"] = account
+	obj["CustomId"] = ""
+	obj["PartnerId"] = 1
+	obj["PartnerName"] = "Fake Partner"
+	obj["WholesaleId"] = 1
+	obj["WholesaleName"] = "Fake Wholesaler"
+	s.insert(KindCustomer, "", account, obj)
+
+	writeJSON(w, http.StatusOK, map[string]any{"AccountNumber": account})
+}
+
+func (s *Server) getCustomer(w http.ResponseWriter, r *request) {
+	obj, ok := s.lookup(KindCustomer, "", r.vars["account_number"])
+	if !ok {
+		writeNotFound(w, KindCustomer, r.vars["account_number"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// updateCustomer replaces the settings of the customer in the id query
+// parameter, keeping its read-only properties.
+func (s *Server) updateCustomer(w http.ResponseWriter, r *request) {
+	account := r.URL.Query().Get("id")
+
+	existing, ok := s.lookup(KindCustomer, "", account)
+	if !ok {
+		writeNotFound(w, KindCustomer, account)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	for _, key := range []string{
+		"Id", "HexId", "CustomId", "PartnerId", "PartnerName",
+		"WholesaleId", "WholesaleName",
+	} {
+		obj[key] = existing[key]
+	}
+
+	s.insert(KindCustomer, "", account, obj)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deleteCustomer(w http.ResponseWriter, r *request) {
+	account := r.URL.Query().Get("id")
+
+	if !s.remove(KindCustomer, "", account) {
+		writeNotFound(w, KindCustomer, account)
+		return
+	}
+
+	s.remove(kindCustomerServices, account, account)
+	s.remove(kindCustomerDeliveryRegion, account, account)
+	s.remove(kindCustomerAccessModules, account, account)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// customerSettings returns the settings of a kind for a customer, creating
+// them if needed. It returns false if the customer does not exist.
+func (s *Server) customerSettings(kind, account string) (map[string]any, bool) {
+	if _, ok := s.lookup(KindCustomer, "", account); !ok {
+		return nil, false
+	}
+
+	settings, ok := s.lookup(kind, account, account)
+	if !ok {
+		settings = make(map[string]any)
+		s.insert(kind, account, account, settings)
+	}
+
+	return settings, true
+}
+
+// getCustomerServices responds with the service catalog, with the status of
+// each service for the customer.
+func (s *Server) getCustomerServices(w http.ResponseWriter, r *request) {
+	account := r.vars["account_number"]
+
+	statuses, ok := s.customerSettings(kindCustomerServices, account)
+	if !ok {
+		writeNotFound(w, KindCustomer, account)
+		return
+	}
+
+	services := make([]map[string]any, 0, len(customerServices))
+	for _, svc := range customerServices {
+		service := copyObject(svc)
+		service["Status"] = intValue(statuses[strconv.Itoa(intValue(svc["Id"]))])
+		services = append(services, service)
+	}
+
+	writeJSON(w, http.StatusOK, services)
+}
+
+func (s *Server) updateCustomerService(w http.ResponseWriter, r *request) {
+	account := r.vars["account_number"]
+
+	statuses, ok := s.customerSettings(kindCustomerServices, account)
+	if !ok {
+		writeNotFound(w, KindCustomer, account)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	statuses[r.vars["id"]] = intValue(obj["Status"])
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) getDeliveryRegion(w http.ResponseWriter, r *request) {
+	account := r.vars["account_number"]
+
+	region, ok := s.customerSettings(kindCustomerDeliveryRegion, account)
+	if !ok {
+		writeNotFound(w, KindCustomer, account)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"AccountNumber":    account,
+		"CustomId":         "",
+		"DeliveryRegionId": intValue(region["Id"]),
+	})
+}
+
+func (s *Server) updateDeliveryRegion(w http.ResponseWriter, r *request) {
+	account := r.URL.Query().Get("id")
+
+	region, ok := s.customerSettings(kindCustomerDeliveryRegion, account)
+	if !ok {
+		writeNotFound(w, KindCustomer, account)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	region["Id"] = intValue(obj["Id"])
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// getAccessModules responds with the access modules enabled for a customer.
+func (s *Server) getAccessModules(w http.ResponseWriter, r *request) {
+	account := r.vars["account_number"]
+
+	statuses, ok := s.customerSettings(kindCustomerAccessModules, account)
+	if !ok {
+		writeNotFound(w, KindCustomer, account)
+		return
+	}
+
+	ids := make([]int, 0)
+	for id, status := range statuses {
+		if intValue(status) == 1 {
+			i, _ := strconv.Atoi(id)
+			ids = append(ids, i)
+		}
+	}
+
+	sort.Ints(ids)
+
+	modules := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		modules = append(modules, map[string]any{
+			"Id":   id,
+			"Name": fmt.Sprintf("Access Module %d", id),
+		})
+	}
+
+	writeJSON(w, http.StatusOK, modules)
+}
+
+func (s *Server) updateAccessModule(w http.ResponseWriter, r *request) {
+	account := r.URL.Query().Get("id")
+
+	statuses, ok := s.customerSettings(kindCustomerAccessModules, account)
+	if !ok {
+		writeNotFound(w, KindCustomer, account)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	statuses[r.vars["id"]] = intValue(obj["Status"])
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) addCustomerUser(w http.ResponseWriter, r *request) {
+	account := r.URL.Query().Get("id")
+	if _, ok := s.lookup(KindCustomer, "", account); !ok {
+		writeNotFound(w, KindCustomer, account)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	id := s.newID()
+	obj["Id"] = id
+	obj["CustomId"] = ""
+	s.insert(KindCustomerUser, "", strconv.Itoa(id), obj)
+
+	writeJSON(w, http.StatusOK, map[string]any{"CustomerUserId": id})
+}
+
+func (s *Server) getCustomerUser(w http.ResponseWriter, r *request) {
+	obj, ok := s.lookup(KindCustomerUser, "", r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindCustomerUser, r.vars["id"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) updateCustomerUser(w http.ResponseWriter, r *request) {
+	id := r.vars["id"]
+	if _, ok := s.lookup(KindCustomerUser, "", id); !ok {
+		writeNotFound(w, KindCustomerUser, id)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	obj["Id"], _ = strconv.Atoi(id)
+	obj["CustomId"] = ""
+	s.insert(KindCustomerUser, "", id, obj)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deleteCustomerUser(w http.ResponseWriter, r *request) {
+	if !s.remove(KindCustomerUser, "", r.vars["id"]) {
+		writeNotFound(w, KindCustomerUser, r.vars["id"])
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
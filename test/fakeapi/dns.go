@@ -0,0 +1,479 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Kinds of Route DNS objects, for use with Count.
+const (
+	KindDNSZone               = "dns_zone"
+	KindDNSGroup              = "dns_group"
+	KindDNSTSIG               = "dns_tsig"
+	KindDNSMasterServerGroup  = "dns_master_server_group"
+	KindDNSSecondaryZoneGroup = "dns_secondary_zone_group"
+)
+
+const dnsBasePath = "/v2/mcc/customers/{account_number}/dns"
+
+// registerDNS registers the Route DNS endpoints of the legacy API. The API is
+// irregular: zones and groups are created and updated by the same POST, some
+// objects are addressed by query parameters rather than by path, and some
+// responses are bare IDs or single-element arrays. The fake follows the API.
+func (s *Server) registerDNS() {
+	s.handle(http.MethodGet, dnsBasePath+"/routezones", s.listDNSZones)
+	s.handle(http.MethodPost, dnsBasePath+"/zone", s.saveDNSZone)
+	s.handle(http.MethodGet, dnsBasePath+"/zone/{id}", s.getDNSZone)
+	s.handle(http.MethodDelete, dnsBasePath+"/routezone/{id}", s.deleteDNSZone)
+
+	s.handle(http.MethodGet, dnsBasePath+"/routegroups", s.listDNSGroups)
+	s.handle(http.MethodPost, dnsBasePath+"/group", s.saveDNSGroup)
+	s.handle(http.MethodGet, dnsBasePath+"/group", s.getDNSGroup)
+	s.handle(http.MethodDelete, dnsBasePath+"/group", s.deleteDNSGroup)
+
+	s.handle(http.MethodGet, dnsBasePath+"/tsigs", s.listDNSTSIGs)
+	s.handle(http.MethodPost, dnsBasePath+"/tsig", s.addDNSTSIG)
+	s.handle(http.MethodGet, dnsBasePath+"/tsigs/{id}", s.getDNSTSIG)
+	s.handle(http.MethodPut, dnsBasePath+"/tsigs/{id}", s.updateDNSTSIG)
+	s.handle(http.MethodDelete, dnsBasePath+"/tsigs/{id}", s.deleteDNSTSIG)
+
+	s.handle(http.MethodGet, dnsBasePath+"/mastergroups", s.getDNSMasterServerGroups)
+	s.handle(http.MethodPost, dnsBasePath+"/mastergroup", s.addDNSMasterServerGroup)
+	s.handle(http.MethodPut, dnsBasePath+"/mastergroup", s.updateDNSMasterServerGroup)
+	s.handle(http.MethodDelete, dnsBasePath+"/mastergroup/{id}", s.deleteDNSMasterServerGroup)
+
+	s.handle(http.MethodGet, dnsBasePath+"/secondarygroup", s.getDNSSecondaryZoneGroup)
+	s.handle(http.MethodPost, dnsBasePath+"/secondarygroup", s.addDNSSecondaryZoneGroup)
+	s.handle(http.MethodPut, dnsBasePath+"/secondarygroup", s.updateDNSSecondaryZoneGroup)
+	s.handle(http.MethodDelete, dnsBasePath+"/secondarygroup", s.deleteDNSSecondaryZoneGroup)
+}
+
+// listDNSZones responds with a summary of each zone.
+func (s *Server) listDNSZones(w http.ResponseWriter, r *request) {
+	zones := s.list(KindDNSZone, r.vars["account_number"])
+
+	summaries := make([]map[string]any, 0, len(zones))
+	for _, zone := range zones {
+		summaries = append(summaries, map[string]any{
+			"ZoneId":      zone["ZoneId"],
+			"FixedZoneId": zone["FixedZoneId"],
+			"DomainName":  zone["DomainName"],
+			"Status":      zone["Status"],
+			"ZoneType":    zone["ZoneType"],
+		})
+	}
+
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// saveDNSZone creates a zone, or updates it if the body has a zone ID, and
+// responds with the bare zone ID.
+func (s *Server) saveDNSZone(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	account := r.vars["account_number"]
+
+	id := intValue(obj["FixedZoneId"])
+	if id == 0 {
+		id = intValue(obj["ZoneId"])
+	}
+
+	if id == 0 {
+		id = s.newID()
+	} else if _, ok := s.lookup(KindDNSZone, account, strconv.Itoa(id)); !ok {
+		writeNotFound(w, KindDNSZone, strconv.Itoa(id))
+		return
+	}
+
+	obj["ZoneId"] = id
+	obj["FixedZoneId"] = id
+	obj["StatusName"] = "Active"
+	obj["IsCustomerOwned"] = true
+	obj["Serial"] = intValue(obj["Serial"]) + 1
+
+	if groups, ok := obj["groups"].([]any); ok {
+		for _, item := range groups {
+			if group, ok := item.(map[string]any); ok {
+				s.setDNSGroupIDs(group, id)
+			}
+		}
+	}
+
+	s.insert(KindDNSZone, account, strconv.Itoa(id), obj)
+
+	writeText(w, http.StatusOK, strconv.Itoa(id))
+}
+
+func (s *Server) getDNSZone(w http.ResponseWriter, r *request) {
+	obj, ok := s.lookup(KindDNSZone, r.vars["account_number"], r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindDNSZone, r.vars["id"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) deleteDNSZone(w http.ResponseWriter, r *request) {
+	if !s.remove(KindDNSZone, r.vars["account_number"], r.vars["id"]) {
+		writeNotFound(w, KindDNSZone, r.vars["id"])
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// saveDNSGroup creates a group, or updates it if the body has a group ID, and
+// responds with the bare group ID.
+func (s *Server) saveDNSGroup(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	account := r.vars["account_number"]
+
+	id := intValue(obj["GroupId"])
+	if id == 0 {
+		id = s.newID()
+	} else if _, ok := s.lookup(KindDNSGroup, account, strconv.Itoa(id)); !ok {
+		writeNotFound(w, KindDNSGroup, strconv.Itoa(id))
+		return
+	}
+
+	obj["GroupId"] = id
+	s.setDNSGroupIDs(obj, 0)
+	s.insert(KindDNSGroup, account, strconv.Itoa(id), obj)
+
+	writeText(w, http.StatusOK, strconv.Itoa(id))
+}
+
+// setDNSGroupIDs sets the IDs of a group and its records.
+func (s *Server) setDNSGroupIDs(group map[string]any, zoneID int) {
+	id := intValue(group["GroupId"])
+	if id == 0 {
+		id = s.newID()
+	}
+
+	group["GroupId"] = id
+	group["FixedGroupId"] = id
+
+	if zoneID > 0 {
+		group["ZoneId"] = zoneID
+		group["FixedZoneId"] = zoneID
+	}
+
+	composition, ok := group["GroupComposition"].(map[string]any)
+	if !ok {
+		return
+	}
+
+	for _, records := range composition {
+		items, ok := records.([]any)
+		if !ok {
+			continue
+		}
+
+		for _, item := range items {
+			if record, ok := item.(map[string]any); ok {
+				if intValue(record["Id"]) == 0 {
+					record["Id"] = s.newID()
+				}
+			}
+		}
+	}
+}
+
+// listDNSGroups responds with all groups, without their records.
+func (s *Server) listDNSGroups(w http.ResponseWriter, r *request) {
+	groups := s.list(KindDNSGroup, r.vars["account_number"])
+
+	summaries := make([]map[string]any, 0, len(groups))
+	for _, group := range groups {
+		summaries = append(summaries, map[string]any{
+			"GroupId":            group["GroupId"],
+			"Name":               group["Name"],
+			"GroupTypeId":        group["GroupTypeId"],
+			"GroupProductTypeId": group["GroupProductTypeId"],
+		})
+	}
+
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+func (s *Server) getDNSGroup(w http.ResponseWriter, r *request) {
+	id := r.URL.Query().Get("id")
+
+	obj, ok := s.lookup(KindDNSGroup, r.vars["account_number"], id)
+	if !ok {
+		writeNotFound(w, KindDNSGroup, id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) deleteDNSGroup(w http.ResponseWriter, r *request) {
+	id := r.URL.Query().Get("id")
+
+	if !s.remove(KindDNSGroup, r.vars["account_number"], id) {
+		writeNotFound(w, KindDNSGroup, id)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) listDNSTSIGs(w http.ResponseWriter, r *request) {
+	writeJSON(w, http.StatusOK, s.list(KindDNSTSIG, r.vars["account_number"]))
+}
+
+func (s *Server) addDNSTSIG(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	id := s.newID()
+	obj["Id"] = id
+	s.insert(KindDNSTSIG, r.vars["account_number"], strconv.Itoa(id), obj)
+
+	writeText(w, http.StatusOK, strconv.Itoa(id))
+}
+
+func (s *Server) getDNSTSIG(w http.ResponseWriter, r *request) {
+	obj, ok := s.lookup(KindDNSTSIG, r.vars["account_number"], r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindDNSTSIG, r.vars["id"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) updateDNSTSIG(w http.ResponseWriter, r *request) {
+	account, id := r.vars["account_number"], r.vars["id"]
+	if _, ok := s.lookup(KindDNSTSIG, account, id); !ok {
+		writeNotFound(w, KindDNSTSIG, id)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	obj["Id"], _ = strconv.Atoi(id)
+	s.insert(KindDNSTSIG, account, id, obj)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) deleteDNSTSIG(w http.ResponseWriter, r *request) {
+	if !s.remove(KindDNSTSIG, r.vars["account_number"], r.vars["id"]) {
+		writeNotFound(w, KindDNSTSIG, r.vars["id"])
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// getDNSMasterServerGroups responds with all master server groups, or with a
+// single-element array if the id query parameter is set.
+func (s *Server) getDNSMasterServerGroups(w http.ResponseWriter, r *request) {
+	account := r.vars["account_number"]
+
+	id := r.URL.Query().Get("id")
+	if len(id) == 0 {
+		writeJSON(w, http.StatusOK, s.list(KindDNSMasterServerGroup, account))
+		return
+	}
+
+	obj, ok := s.lookup(KindDNSMasterServerGroup, account, id)
+	if !ok {
+		writeNotFound(w, KindDNSMasterServerGroup, id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []any{obj})
+}
+
+// addDNSMasterServerGroup creates a master server group. The request lists
+// the masters under MasterServers, but responses list them under Masters.
+func (s *Server) addDNSMasterServerGroup(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	id := s.newID()
+	group := s.dnsMasterServerGroup(obj, id)
+	s.insert(KindDNSMasterServerGroup, r.vars["account_number"], strconv.Itoa(id), group)
+
+	writeJSON(w, http.StatusOK, []any{group})
+}
+
+func (s *Server) updateDNSMasterServerGroup(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	account := r.vars["account_number"]
+	id := intValue(obj["Id"])
+
+	if _, ok := s.lookup(KindDNSMasterServerGroup, account, strconv.Itoa(id)); !ok {
+		writeNotFound(w, KindDNSMasterServerGroup, strconv.Itoa(id))
+		return
+	}
+
+	group := s.dnsMasterServerGroup(obj, id)
+	s.insert(KindDNSMasterServerGroup, account, strconv.Itoa(id), group)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (s *Server) dnsMasterServerGroup(obj map[string]any, id int) map[string]any {
+	masters, ok := obj["Masters"].([]any)
+	if !ok {
+		masters, _ = obj["MasterServers"].([]any)
+	}
+
+	for _, item := range masters {
+		if master, ok := item.(map[string]any); ok {
+			if intValue(master["Id"]) == 0 {
+				master["Id"] = s.newID()
+			}
+		}
+	}
+
+	if masters == nil {
+		masters = []any{}
+	}
+
+	return map[string]any{
+		"MasterGroupId": id,
+		"Name":          obj["Name"],
+		"Masters":       masters,
+	}
+}
+
+func (s *Server) deleteDNSMasterServerGroup(w http.ResponseWriter, r *request) {
+	if !s.remove(KindDNSMasterServerGroup, r.vars["account_number"], r.vars["id"]) {
+		writeNotFound(w, KindDNSMasterServerGroup, r.vars["id"])
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// getDNSSecondaryZoneGroup responds with all secondary zone groups, or with a
+// single-element array if the id query parameter is set.
+func (s *Server) getDNSSecondaryZoneGroup(w http.ResponseWriter, r *request) {
+	account := r.vars["account_number"]
+
+	id := r.URL.Query().Get("id")
+	if len(id) == 0 {
+		writeJSON(w, http.StatusOK, s.list(KindDNSSecondaryZoneGroup, account))
+		return
+	}
+
+	obj, ok := s.lookup(KindDNSSecondaryZoneGroup, account, id)
+	if !ok {
+		writeNotFound(w, KindDNSSecondaryZoneGroup, id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, []any{obj})
+}
+
+func (s *Server) addDNSSecondaryZoneGroup(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	id := s.newID()
+	s.setDNSSecondaryZoneIDs(obj, id)
+	s.insert(KindDNSSecondaryZoneGroup, r.vars["account_number"], strconv.Itoa(id), obj)
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) updateDNSSecondaryZoneGroup(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	account := r.vars["account_number"]
+	id := intValue(obj["Id"])
+
+	if _, ok := s.lookup(KindDNSSecondaryZoneGroup, account, strconv.Itoa(id)); !ok {
+		writeNotFound(w, KindDNSSecondaryZoneGroup, strconv.Itoa(id))
+		return
+	}
+
+	s.setDNSSecondaryZoneIDs(obj, id)
+	s.insert(KindDNSSecondaryZoneGroup, account, strconv.Itoa(id), obj)
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// setDNSSecondaryZoneIDs sets the IDs of a secondary zone group and its zones.
+func (s *Server) setDNSSecondaryZoneIDs(obj map[string]any, id int) {
+	obj["Id"] = id
+
+	composition, ok := obj["ZoneComposition"].(map[string]any)
+	if !ok {
+		return
+	}
+
+	zones, _ := composition["Zones"].([]any)
+	for _, item := range zones {
+		zone, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if intValue(zone["ZoneId"]) == 0 {
+			zoneID := s.newID()
+			zone["ZoneId"] = zoneID
+			zone["FixedZoneId"] = zoneID
+			zone["ZoneType"] = 2
+			zone["StatusName"] = "Active"
+		}
+	}
+}
+
+func (s *Server) deleteDNSSecondaryZoneGroup(w http.ResponseWriter, r *request) {
+	id := r.URL.Query().Get("id")
+
+	if !s.remove(KindDNSSecondaryZoneGroup, r.vars["account_number"], id) {
+		writeNotFound(w, KindDNSSecondaryZoneGroup, id)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// intValue returns a JSON number or numeric string as an int, or 0.
+func intValue(v any) int {
+	switch n := v.(type) {
+	case float64:
+		return int(n)
+	case int:
+		return n
+	case string:
+		i, _ := strconv.Atoi(n)
+		return i
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// registerIDS registers the IDS token endpoint, which issues AccessToken for
+// the client credentials IDSClientID and IDSClientSecret.
+func (s *Server) registerIDS() {
+	s.handle(http.MethodPost, "/connect/token", s.issueToken)
+}
+
+func (s *Server) issueToken(w http.ResponseWriter, r *request) {
+	form, err := url.ParseQuery(string(r.body))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": "invalid_request",
+		})
+		return
+	}
+
+	if form.Get("client_id") != IDSClientID ||
+		form.Get("client_secret") != IDSClientSecret {
+		writeJSON(w, http.StatusBadRequest, map[string]any{
+			"error": "invalid_client",
+		})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": AccessToken,
+		"expires_in":   3600,
+		"token_type":   "Bearer",
+		"scope":        form.Get("scope"),
+	})
+}
+
+func isTokenEndpoint(segments []string) bool {
+	return len(segments) == 2 &&
+		segments[0] == "connect" &&
+		segments[1] == "token"
+}
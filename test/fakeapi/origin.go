@@ -0,0 +1,183 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// Kinds of legacy origin and edge CNAME objects, for use with Count.
+const (
+	KindOrigin    = "origin"
+	KindEdgeCname = "edgecname"
+)
+
+// platformIDs maps the platform names used in legacy API paths to their
+// media type IDs.
+var platformIDs = map[string]int{
+	"httplarge": 3,
+	"httpsmall": 8,
+	"adn":       14,
+}
+
+// registerOrigin registers the customer origin endpoints of the legacy API.
+func (s *Server) registerOrigin() {
+	const path = "/v2/mcc/customers/{account_number}/origins"
+
+	s.handle(http.MethodPost, path+"/{platform}", s.addOrigin)
+	s.handle(http.MethodGet, path+"/{platform}", s.getAllOrigins)
+	s.handle(http.MethodGet, path+"/{platform}/{id}", s.getOrigin)
+	s.handle(http.MethodPut, path+"/{platform}/{id}", s.updateOrigin)
+	s.handle(http.MethodDelete, path+"/{id}", s.deleteOrigin)
+}
+
+func (s *Server) addOrigin(w http.ResponseWriter, r *request) {
+	platformID, ok := platformIDs[r.vars["platform"]]
+	if !ok {
+		writeError(w, http.StatusBadRequest, "unknown platform %s", r.vars["platform"])
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	id := s.newID()
+	obj["Id"] = id
+	obj["MediaTypeId"] = platformID
+	s.insert(KindOrigin, r.vars["account_number"], strconv.Itoa(id), obj)
+
+	writeJSON(w, http.StatusOK, map[string]any{"CustomerOriginId": id})
+}
+
+func (s *Server) getAllOrigins(w http.ResponseWriter, r *request) {
+	platformID := platformIDs[r.vars["platform"]]
+
+	origins := make([]map[string]any, 0)
+	for _, obj := range s.list(KindOrigin, r.vars["account_number"]) {
+		if intValue(obj["MediaTypeId"]) == platformID {
+			origins = append(origins, obj)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, origins)
+}
+
+func (s *Server) getOrigin(w http.ResponseWriter, r *request) {
+	obj, ok := s.lookup(KindOrigin, r.vars["account_number"], r.vars["id"])
+	if !ok || intValue(obj["MediaTypeId"]) != platformIDs[r.vars["platform"]] {
+		writeNotFound(w, KindOrigin, r.vars["id"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) updateOrigin(w http.ResponseWriter, r *request) {
+	account, id := r.vars["account_number"], r.vars["id"]
+
+	existing, ok := s.lookup(KindOrigin, account, id)
+	if !ok {
+		writeNotFound(w, KindOrigin, id)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	obj["Id"] = existing["Id"]
+	obj["MediaTypeId"] = existing["MediaTypeId"]
+	s.insert(KindOrigin, account, id, obj)
+
+	writeJSON(w, http.StatusOK, map[string]any{"CustomerOriginId": obj["Id"]})
+}
+
+func (s *Server) deleteOrigin(w http.ResponseWriter, r *request) {
+	if !s.remove(KindOrigin, r.vars["account_number"], r.vars["id"]) {
+		writeNotFound(w, KindOrigin, r.vars["id"])
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// registerEdgeCname registers the edge CNAME endpoints of the legacy API.
+func (s *Server) registerEdgeCname() {
+	const path = "/v2/mcc/customers/{account_number}/cnames"
+
+	s.handle(http.MethodPost, path, s.addEdgeCname)
+	s.handle(http.MethodGet, path+"/{id}", s.getEdgeCname)
+	s.handle(http.MethodPut, path+"/{id}", s.updateEdgeCname)
+	s.handle(http.MethodDelete, path+"/{id}", s.deleteEdgeCname)
+}
+
+func (s *Server) addEdgeCname(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	id := s.newID()
+	obj["Id"] = id
+	s.insert(KindEdgeCname, r.vars["account_number"], strconv.Itoa(id), obj)
+
+	writeJSON(w, http.StatusOK, map[string]any{"CnameId": id})
+}
+
+// getEdgeCname responds with an edge CNAME, or with all edge CNAMEs of a
+// platform since both share the path /cnames/{id}.
+func (s *Server) getEdgeCname(w http.ResponseWriter, r *request) {
+	account, id := r.vars["account_number"], r.vars["id"]
+
+	if platformID, ok := platformIDs[id]; ok {
+		cnames := make([]map[string]any, 0)
+		for _, obj := range s.list(KindEdgeCname, account) {
+			if intValue(obj["MediaTypeId"]) == platformID {
+				cnames = append(cnames, obj)
+			}
+		}
+
+		writeJSON(w, http.StatusOK, cnames)
+		return
+	}
+
+	obj, ok := s.lookup(KindEdgeCname, account, id)
+	if !ok {
+		writeNotFound(w, KindEdgeCname, id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) updateEdgeCname(w http.ResponseWriter, r *request) {
+	account, id := r.vars["account_number"], r.vars["id"]
+	if _, ok := s.lookup(KindEdgeCname, account, id); !ok {
+		writeNotFound(w, KindEdgeCname, id)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	obj["Id"], _ = strconv.Atoi(id)
+	s.insert(KindEdgeCname, account, id, obj)
+
+	writeJSON(w, http.StatusOK, map[string]any{"CnameId": obj["Id"]})
+}
+
+func (s *Server) deleteEdgeCname(w http.ResponseWriter, r *request) {
+	if !s.remove(KindEdgeCname, r.vars["account_number"], r.vars["id"]) {
+		writeNotFound(w, KindEdgeCname, r.vars["id"])
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,260 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// Kinds of Origin v3 objects, for use with Count. Origin v3 objects belong to
+// the account of the IDS token, so Count them under the account "".
+const (
+	KindOriginV3Group  = "originv3_group"
+	KindOriginV3Origin = "originv3_origin"
+)
+
+const originV3BasePath = "/cdn/origins/v0.5"
+
+// originV3ShieldPops are the origin shield locations returned for any find
+// code.
+var originV3ShieldPops = []map[string]any{
+	{
+		"region_id":   1,
+		"region_name": "US West",
+		"bypass_code": "BYPASS",
+		"bypass_name": "Bypass",
+		"pops": []map[string]any{
+			{"id": 1, "code": "LAA", "city": "Los Angeles", "is_pci_certified": true},
+			{"id": 2, "code": "SJC", "city": "San Jose", "is_pci_certified": false},
+		},
+	},
+}
+
+var originV3ProtocolTypes = []map[string]any{
+	{"id": 1, "name": "http"},
+	{"id": 2, "name": "https"},
+	{"id": 3, "name": "match"},
+}
+
+var originV3NetworkTypes = []map[string]any{
+	{"id": 1, "name": "Default", "value": "default"},
+	{"id": 2, "name": "IPv6 Preferred", "value": "ipv6"},
+	{"id": 3, "name": "IPv4 Preferred", "value": "ipv4"},
+	{"id": 4, "name": "IPv4 Only", "value": "ipv4only"},
+	{"id": 5, "name": "IPv6 Only", "value": "ipv6only"},
+}
+
+// registerOriginV3 registers the Origin v3 endpoints.
+func (s *Server) registerOriginV3() {
+	const path = originV3BasePath
+
+	s.handle(http.MethodPost, path+"/http-large/groups", s.addOriginV3Group)
+	s.handle(http.MethodGet, path+"/http-large/groups",
+		func(w http.ResponseWriter, r *request) {
+			writeJSON(w, http.StatusOK, s.list(KindOriginV3Group, ""))
+		})
+	s.handle(http.MethodGet, path+"/http-large/groups/{groupId}", s.getOriginV3Group)
+	s.handle(http.MethodPut, path+"/http-large/groups/{groupId}", s.updateOriginV3Group)
+	s.handle(http.MethodDelete, path+"/{mediaType}/groups/{groupId}", s.deleteOriginV3Group)
+
+	s.handle(http.MethodPost, path+"/{mediaType}", s.addOriginV3Origin)
+	s.handle(http.MethodGet, path+"/{mediaType}/{id}", s.getOriginV3Origin)
+	s.handle(http.MethodPut, path+"/{mediaType}/{id}", s.updateOriginV3Origin)
+	s.handle(http.MethodDelete, path+"/{mediaType}/{id}", s.deleteOriginV3Origin)
+	s.handle(http.MethodGet, path+"/{mediaType}/groups/{groupId}/origins", s.getOriginV3GroupOrigins)
+	s.handle(http.MethodPatch, path+"/{mediaType}/groups/{groupId}/origins", s.updateOriginV3FailoverOrder)
+
+	s.handle(http.MethodGet, path+"/http-large/shield-pops",
+		func(w http.ResponseWriter, r *request) {
+			writeJSON(w, http.StatusOK, originV3ShieldPops)
+		})
+	s.handle(http.MethodGet, path+"/protocol-types",
+		func(w http.ResponseWriter, r *request) {
+			writeJSON(w, http.StatusOK, originV3ProtocolTypes)
+		})
+	s.handle(http.MethodGet, path+"/network-types",
+		func(w http.ResponseWriter, r *request) {
+			writeJSON(w, http.StatusOK, originV3NetworkTypes)
+		})
+}
+
+func (s *Server) addOriginV3Group(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	id := s.newID()
+	obj["id"] = id
+	s.insert(KindOriginV3Group, "", strconv.Itoa(id), obj)
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) getOriginV3Group(w http.ResponseWriter, r *request) {
+	obj, ok := s.lookup(KindOriginV3Group, "", r.vars["groupId"])
+	if !ok {
+		writeNotFound(w, KindOriginV3Group, r.vars["groupId"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) updateOriginV3Group(w http.ResponseWriter, r *request) {
+	id := r.vars["groupId"]
+	if _, ok := s.lookup(KindOriginV3Group, "", id); !ok {
+		writeNotFound(w, KindOriginV3Group, id)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	obj["id"], _ = strconv.Atoi(id)
+	s.insert(KindOriginV3Group, "", id, obj)
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// deleteOriginV3Group deletes a group and its origins.
+func (s *Server) deleteOriginV3Group(w http.ResponseWriter, r *request) {
+	id := r.vars["groupId"]
+	if !s.remove(KindOriginV3Group, "", id) {
+		writeNotFound(w, KindOriginV3Group, id)
+		return
+	}
+
+	for _, origin := range s.list(KindOriginV3Origin, "") {
+		if strconv.Itoa(intValue(origin["group_id"])) == id {
+			s.remove(KindOriginV3Origin, "", strconv.Itoa(intValue(origin["id"])))
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// addOriginV3Origin adds an origin to a group. New origins are last in the
+// group's failover order.
+func (s *Server) addOriginV3Origin(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	groupID := strconv.Itoa(intValue(obj["group_id"]))
+	if _, ok := s.lookup(KindOriginV3Group, "", groupID); !ok {
+		writeNotFound(w, KindOriginV3Group, groupID)
+		return
+	}
+
+	id := s.newID()
+	obj["id"] = id
+	obj["failover_order"] = len(s.originV3GroupOrigins(groupID))
+	s.insert(KindOriginV3Origin, "", strconv.Itoa(id), obj)
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) getOriginV3Origin(w http.ResponseWriter, r *request) {
+	obj, ok := s.lookup(KindOriginV3Origin, "", r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindOriginV3Origin, r.vars["id"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) updateOriginV3Origin(w http.ResponseWriter, r *request) {
+	id := r.vars["id"]
+
+	existing, ok := s.lookup(KindOriginV3Origin, "", id)
+	if !ok {
+		writeNotFound(w, KindOriginV3Origin, id)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	obj["id"] = existing["id"]
+	obj["failover_order"] = existing["failover_order"]
+	s.insert(KindOriginV3Origin, "", id, obj)
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) deleteOriginV3Origin(w http.ResponseWriter, r *request) {
+	if !s.remove(KindOriginV3Origin, "", r.vars["id"]) {
+		writeNotFound(w, KindOriginV3Origin, r.vars["id"])
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getOriginV3GroupOrigins(w http.ResponseWriter, r *request) {
+	id := r.vars["groupId"]
+	if _, ok := s.lookup(KindOriginV3Group, "", id); !ok {
+		writeNotFound(w, KindOriginV3Group, id)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, s.originV3GroupOrigins(id))
+}
+
+// originV3GroupOrigins returns the origins of a group in failover order.
+func (s *Server) originV3GroupOrigins(groupID string) []map[string]any {
+	origins := make([]map[string]any, 0)
+	for _, origin := range s.list(KindOriginV3Origin, "") {
+		if strconv.Itoa(intValue(origin["group_id"])) == groupID {
+			origins = append(origins, origin)
+		}
+	}
+
+	sort.SliceStable(origins, func(i, j int) bool {
+		return intValue(origins[i]["failover_order"]) <
+			intValue(origins[j]["failover_order"])
+	})
+
+	return origins
+}
+
+// updateOriginV3FailoverOrder sets the failover order of a group's origins
+// from a list of {id, host, failover_order}.
+func (s *Server) updateOriginV3FailoverOrder(w http.ResponseWriter, r *request) {
+	groupID := r.vars["groupId"]
+	if _, ok := s.lookup(KindOriginV3Group, "", groupID); !ok {
+		writeNotFound(w, KindOriginV3Group, groupID)
+		return
+	}
+
+	var orders []map[string]any
+	if err := json.Unmarshal(r.body, &orders); err != nil {
+		writeError(w, http.StatusBadRequest, "malformed request body: %v", err)
+		return
+	}
+
+	for _, order := range orders {
+		id := strconv.Itoa(intValue(order["id"]))
+
+		origin, ok := s.lookup(KindOriginV3Origin, "", id)
+		if !ok || strconv.Itoa(intValue(origin["group_id"])) != groupID {
+			writeNotFound(w, KindOriginV3Origin, id)
+			return
+		}
+
+		origin["failover_order"] = intValue(order["failover_order"])
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
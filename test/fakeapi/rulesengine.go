@@ -0,0 +1,211 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Kinds of Rules Engine objects, for use with Count. Policies cannot be
+// deleted, so deleting a policy resource adds an empty placeholder policy or
+// redeploys an earlier one.
+const (
+	KindRulesEnginePolicy        = "rulesengine_policy"
+	KindRulesEngineDeployRequest = "rulesengine_deploy_request"
+)
+
+const rulesEngineBasePath = "/rules-engine/v1.1"
+
+// deployRequestPageSize is the number of deploy requests listed per page. It
+// is small so that clients have to follow the links between pages.
+const deployRequestPageSize = 2
+
+// registerRulesEngine registers the Rules Engine v4 endpoints.
+func (s *Server) registerRulesEngine() {
+	const path = rulesEngineBasePath
+
+	s.handle(http.MethodPost, path+"/policies", s.addPolicy)
+	s.handle(http.MethodGet, path+"/policies/{id}", s.getPolicy)
+	s.handle(http.MethodPost, path+"/deploy-requests", s.addDeployRequest)
+	s.handle(http.MethodGet, path+"/deploy-requests", s.listDeployRequests)
+	s.handle(http.MethodGet, path+"/deploy-requests/{id}", s.getDeployRequest)
+}
+
+// rulesEngineAccount returns the account number of a Rules Engine request from
+// the customer ID in its Portals_CustomerId header, or "" if there is none.
+func rulesEngineAccount(r *request) (string, error) {
+	customerID := r.Header.Get("Portals_CustomerId")
+	if len(customerID) == 0 {
+		return "", nil
+	}
+
+	id, err := strconv.ParseInt(customerID, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid Portals_CustomerId %q", customerID)
+	}
+
+	return fmt.Sprintf("%X", id), nil
+}
+
+// addPolicy creates a locked policy, giving it and its rules IDs.
+func (s *Server) addPolicy(w http.ResponseWriter, r *request) {
+	account, err := rulesEngineAccount(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	id := strconv.Itoa(s.newID())
+
+	obj["id"] = id
+	obj["@id"] = rulesEngineBasePath + "/policies/" + id
+	obj["@type"] = "Policy"
+	obj["policy_type"] = "customer"
+	obj["state"] = "locked"
+	obj["created_at"] = now
+	obj["updated_at"] = now
+	obj["history"] = []any{}
+
+	rules, _ := obj["rules"].([]any)
+	for i, item := range rules {
+		rule, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		ruleID := strconv.Itoa(s.newID())
+		rule["id"] = ruleID
+		rule["@id"] = rulesEngineBasePath + "/policies/" + id + "/rules/" + ruleID
+		rule["@type"] = "Rule"
+		rule["ordinal"] = i + 1
+		rule["created_at"] = now
+		rule["updated_at"] = now
+	}
+
+	if rules == nil {
+		obj["rules"] = []any{}
+	}
+
+	s.insert(KindRulesEnginePolicy, account, id, obj)
+
+	writeJSON(w, http.StatusCreated, obj)
+}
+
+func (s *Server) getPolicy(w http.ResponseWriter, r *request) {
+	account, err := rulesEngineAccount(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	obj, ok := s.lookup(KindRulesEnginePolicy, account, r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindRulesEnginePolicy, r.vars["id"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// addDeployRequest deploys a policy to an environment. Deploy requests
+// complete immediately.
+func (s *Server) addDeployRequest(w http.ResponseWriter, r *request) {
+	account, err := rulesEngineAccount(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	policyID := strconv.Itoa(intValue(obj["policy_id"]))
+
+	policy, ok := s.lookup(KindRulesEnginePolicy, account, policyID)
+	if !ok {
+		writeNotFound(w, KindRulesEnginePolicy, policyID)
+		return
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	id := strconv.Itoa(s.newID())
+
+	obj["id"] = id
+	obj["@id"] = rulesEngineBasePath + "/deploy-requests/" + id
+	obj["@type"] = "DeployRequest"
+	obj["state"] = "deployed"
+	obj["customer_id"] = r.Header.Get("Portals_CustomerId")
+	obj["created_at"] = now
+	obj["updated_at"] = now
+	obj["policies"] = copyObject(policy)
+	s.insert(KindRulesEngineDeployRequest, account, id, obj)
+
+	writeJSON(w, http.StatusCreated, obj)
+}
+
+func (s *Server) getDeployRequest(w http.ResponseWriter, r *request) {
+	account, err := rulesEngineAccount(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	obj, ok := s.lookup(KindRulesEngineDeployRequest, account, r.vars["id"])
+	if !ok {
+		writeNotFound(w, KindRulesEngineDeployRequest, r.vars["id"])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+func (s *Server) listDeployRequests(w http.ResponseWriter, r *request) {
+	account, err := rulesEngineAccount(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	items := s.list(KindRulesEngineDeployRequest, account)
+	total := len(items)
+
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	pageURL := func(page int) map[string]any {
+		return map[string]any{
+			"href": fmt.Sprintf(
+				"%s/deploy-requests?page=%d", rulesEngineBasePath, page),
+		}
+	}
+
+	links := map[string]any{"first": pageURL(1)}
+	if page*deployRequestPageSize < total {
+		links["next"] = pageURL(page + 1)
+	}
+
+	start := min((page-1)*deployRequestPageSize, total)
+	end := min(start+deployRequestPageSize, total)
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"@id":    rulesEngineBasePath + "/deploy-requests",
+		"@type":  "Collection",
+		"@total": total,
+		"@links": links,
+		"@items": items[start:end],
+	})
+}
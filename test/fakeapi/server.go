@@ -0,0 +1,368 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+// Package fakeapi provides an in-process fake of the Edgecast APIs used by the
+// provider, so that acceptance tests can run without an Edgecast account.
+//
+// The fake keeps its state in memory and implements just enough of each API
+// for the provider's create, read, update, delete and import flows: objects
+// are stored as sent, given IDs, and returned as stored. It does not validate
+// payloads the way the real APIs do.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Credentials accepted by the fake. Requests with other credentials are
+// rejected with 401 Unauthorized.
+const (
+	APIToken        = "fake-api-token"
+	IDSClientID     = "fake-client-id"
+	IDSClientSecret = "fake-client-secret"
+	IDSScope        = "ec.rules cdn.origins ec.cps sec.waf"
+	AccessToken     = "fake-access-token"
+)
+
+// Server is a fake Edgecast API server. It serves the REST APIs, the legacy
+// REST APIs and the IDS token endpoint from a single address.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	nextID   int
+	requests int
+	objects  map[string]map[string]map[string]any
+	routes   []route
+}
+
+// New starts a fake Edgecast API server. Callers must call Close when done.
+func New() *Server {
+	s := &Server{
+		nextID:  100,
+		objects: make(map[string]map[string]map[string]any),
+	}
+
+	s.registerIDS()
+	s.registerWAF()
+	s.registerDNS()
+	s.registerOrigin()
+	s.registerEdgeCname()
+	s.registerCustomer()
+	s.registerOriginV3()
+	s.registerCPS()
+	s.registerRulesEngine()
+
+	s.Server = httptest.NewServer(s)
+
+	return s
+}
+
+// Settings returns the provider settings that point the provider at the
+// fake, for use with schema.Provider.Configure.
+func (s *Server) Settings() map[string]any {
+	return map[string]any{
+		"api_token":          APIToken,
+		"ids_client_id":      IDSClientID,
+		"ids_client_secret":  IDSClientSecret,
+		"ids_scope":          IDSScope,
+		"api_address":        s.URL,
+		"api_address_legacy": s.URL,
+		"ids_address":        s.URL,
+		"max_retries":        0,
+	}
+}
+
+// ProviderConfig returns a provider block that points the provider at the
+// fake, for use in resource.Test configurations.
+func (s *Server) ProviderConfig() string {
+	return fmt.Sprintf(`
+provider "edgecast" {
+  api_token          = %q
+  ids_client_id      = %q
+  ids_client_secret  = %q
+  ids_scope          = %q
+  api_address        = %q
+  api_address_legacy = %q
+  ids_address        = %q
+  max_retries        = 0
+}
+`,
+		APIToken,
+		IDSClientID,
+		IDSClientSecret,
+		IDSScope,
+		s.URL,
+		s.URL,
+		s.URL)
+}
+
+// route is a handler for a method and path pattern. Path segments of the form
+// {name} match any segment and are passed to the handler as variables.
+type route struct {
+	method   string
+	segments []string
+	handler  handlerFunc
+}
+
+type handlerFunc func(w http.ResponseWriter, r *request)
+
+// request is an API request with its path variables and body.
+type request struct {
+	*http.Request
+	vars map[string]string
+	body []byte
+}
+
+// handle registers a handler for a method and path pattern.
+func (s *Server) handle(method string, pattern string, handler handlerFunc) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: splitPath(pattern),
+		handler:  handler,
+	})
+}
+
+// ServeHTTP routes a request to the handler whose pattern matches its path.
+// When several patterns match, the one with literal segments nearest the
+// start of the path wins, e.g. /customers/services over
+// /customers/{account_number}.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests++
+	requestID := fmt.Sprintf("fake-%d", s.requests)
+	s.mu.Unlock()
+
+	w.Header().Set("X-Request-Id", requestID)
+
+	segments := splitPath(r.URL.Path)
+
+	var (
+		match     *route
+		vars      map[string]string
+		bestScore = -1
+	)
+
+	for i := range s.routes {
+		rt := &s.routes[i]
+		if rt.method != r.Method {
+			continue
+		}
+
+		v, score, ok := rt.match(segments)
+		if ok && score > bestScore {
+			match, vars, bestScore = rt, v, score
+		}
+	}
+
+	if match == nil {
+		writeError(w, http.StatusNotFound, "no route for %s %s", r.Method, r.URL.Path)
+		return
+	}
+
+	if !isTokenEndpoint(segments) && !authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid credentials")
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "reading body: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	match.handler(w, &request{Request: r, vars: vars, body: body})
+}
+
+// match reports whether the route matches the path segments and scores the
+// match by the position of its literal segments.
+func (rt route) match(segments []string) (map[string]string, int, bool) {
+	if len(rt.segments) != len(segments) {
+		return nil, 0, false
+	}
+
+	vars := make(map[string]string)
+	score := 0
+
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			vars[strings.Trim(seg, "{}")] = segments[i]
+			continue
+		}
+
+		if !strings.EqualFold(seg, segments[i]) {
+			return nil, 0, false
+		}
+
+		score += 1 << (len(segments) - i)
+	}
+
+	return vars, score, true
+}
+
+func splitPath(path string) []string {
+	segments := make([]string, 0)
+	for _, seg := range strings.Split(path, "/") {
+		if len(seg) > 0 {
+			segments = append(segments, seg)
+		}
+	}
+
+	return segments
+}
+
+// authorized checks the Authorization header sent by the SDK's token or IDS
+// authorization provider.
+func authorized(r *http.Request) bool {
+	switch r.Header.Get("Authorization") {
+	case "TOK:" + APIToken, "Bearer " + AccessToken:
+		return true
+	default:
+		return false
+	}
+}
+
+// decode unmarshals the request body into an object.
+func (r *request) decode() (map[string]any, error) {
+	obj := make(map[string]any)
+	if len(r.body) == 0 {
+		return obj, nil
+	}
+
+	if err := json.Unmarshal(r.body, &obj); err != nil {
+		return nil, fmt.Errorf("malformed request body: %w", err)
+	}
+
+	return obj, nil
+}
+
+// decodeOrFail is like decode, but writes a 400 Bad Request response and
+// returns false if the body is malformed.
+func (r *request) decodeOrFail(w http.ResponseWriter) (map[string]any, bool) {
+	obj, err := r.decode()
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%v", err)
+		return nil, false
+	}
+
+	return obj, true
+}
+
+// newID returns a new numeric ID. IDs are unique across all objects.
+func (s *Server) newID() int {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+// insert stores an object of the given kind under an account and ID.
+func (s *Server) insert(kind, account, id string, obj map[string]any) {
+	key := kind + "/" + account
+	if s.objects[key] == nil {
+		s.objects[key] = make(map[string]map[string]any)
+	}
+
+	s.objects[key][id] = obj
+}
+
+// lookup returns a stored object.
+func (s *Server) lookup(kind, account, id string) (map[string]any, bool) {
+	obj, ok := s.objects[kind+"/"+account][id]
+	return obj, ok
+}
+
+// remove deletes a stored object and reports whether it existed.
+func (s *Server) remove(kind, account, id string) bool {
+	if _, ok := s.lookup(kind, account, id); !ok {
+		return false
+	}
+
+	delete(s.objects[kind+"/"+account], id)
+
+	return true
+}
+
+// list returns the objects of a kind stored under an account, ordered by ID.
+func (s *Server) list(kind, account string) []map[string]any {
+	objects := s.objects[kind+"/"+account]
+
+	ids := make([]string, 0, len(objects))
+	for id := range objects {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		a, errA := strconv.Atoi(ids[i])
+		b, errB := strconv.Atoi(ids[j])
+		if errA == nil && errB == nil {
+			return a < b
+		}
+
+		return ids[i] < ids[j]
+	})
+
+	list := make([]map[string]any, 0, len(ids))
+	for _, id := range ids {
+		list = append(list, objects[id])
+	}
+
+	return list
+}
+
+// Count returns the number of objects of a kind stored under an account. It
+// lets tests check that a resource was created or removed. The kinds are
+// named in the file of each API.
+func (s *Server) Count(kind, account string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.objects[kind+"/"+account])
+}
+
+// copyObject returns a deep copy of an object so that stored state is not
+// modified by callers.
+func copyObject(obj map[string]any) map[string]any {
+	b, _ := json.Marshal(obj)
+
+	var c map[string]any
+	_ = json.Unmarshal(b, &c)
+
+	return c
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeText writes a plain text body e.g. the bare IDs returned by the Route
+// DNS API.
+func writeText(w http.ResponseWriter, status int, text string) {
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, text)
+}
+
+// writeError writes an error in the format of the legacy APIs, which the
+// provider's error parsing understands.
+func writeError(w http.ResponseWriter, status int, format string, a ...any) {
+	writeJSON(w, status, map[string]any{
+		"Message": fmt.Sprintf(format, a...),
+	})
+}
+
+func writeNotFound(w http.ResponseWriter, kind string, id string) {
+	writeError(w, http.StatusNotFound, "%s %s was not found", kind, id)
+}
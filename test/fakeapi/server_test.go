@@ -0,0 +1,391 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"terraform-provider-edgecast/edgecast"
+	"terraform-provider-edgecast/test/fakeapi"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccount = "ABCD"
+
+// resourceCase creates a resource against the fake, reads it back, updates
+// it, deletes it, and checks that a later read removes it from state.
+type resourceCase struct {
+	resource string
+	config   map[string]any
+	update   map[string]any
+	kind     string
+	account  string
+
+	// kept is true when deleting leaves the object in the fake, e.g. for Rules
+	// Engine policies, which are replaced rather than deleted.
+	kept bool
+}
+
+func TestResourceLifecycle(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]resourceCase{
+		"waf access rule": {
+			resource: "edgecast_waf_access_rule",
+			config: map[string]any{
+				"account_number":       testAccount,
+				"name":                 "Access Rule #1",
+				"response_header_name": "x-rule",
+				"allowed_http_methods": []any{"GET", "POST"},
+				"allowed_request_content_types": []any{
+					"application/json",
+				},
+				"disallowed_extensions": []any{".bat"},
+				"disallowed_headers":    []any{"x-reserved"},
+				"ip": []any{map[string]any{
+					"blacklist": []any{"10.10.10.114"},
+				}},
+			},
+			update:  map[string]any{"name": "Access Rule #2"},
+			kind:    fakeapi.KindWAFAccessRule,
+			account: testAccount,
+		},
+		"dns tsig": {
+			resource: "edgecast_dns_tsig",
+			config: map[string]any{
+				"account_number": testAccount,
+				"alias":          "Test keys",
+				"key_name":       "key1",
+				"key_value":      "HFNASHDJJKQWHKJ1234",
+				"algorithm_name": "HMAC-SHA512",
+			},
+			update:  map[string]any{"alias": "Updated keys"},
+			kind:    fakeapi.KindDNSTSIG,
+			account: testAccount,
+		},
+		"edge cname": {
+			resource: "edgecast_edgecname",
+			config: map[string]any{
+				"account_number": testAccount,
+				"name":           "cdn.example.com",
+				"dir_path":       "/ec/origin/path",
+				"media_type_id":  3,
+				"origin_id":      -1,
+			},
+			update:  map[string]any{"dir_path": "/ec/origin/other"},
+			kind:    fakeapi.KindEdgeCname,
+			account: testAccount,
+		},
+		"originv3 http large": {
+			resource: "edgecast_originv3_httplarge",
+			config: map[string]any{
+				"name":            "group-1",
+				"host_header":     "origin.example.com",
+				"network_type_id": 2,
+				"origin": []any{
+					map[string]any{
+						"name":             "origin-a",
+						"host":             "https://origin-a.example.com",
+						"port":             443,
+						"is_primary":       true,
+						"storage_type_id":  1,
+						"protocol_type_id": 2,
+					},
+				},
+			},
+			update: map[string]any{"host_header": "other.example.com"},
+			kind:   fakeapi.KindOriginV3Group,
+		},
+		"cps certificate": {
+			resource: "edgecast_cps_certificate",
+			config: map[string]any{
+				"certificate_label":     "Test certificate",
+				"description":           "Test DV certificate",
+				"auto_renew":            true,
+				"certificate_authority": "DigiCert",
+				"validation_type":       "DV",
+				"dcv_method":            "DnsTxtToken",
+				"domain": []any{
+					map[string]any{
+						"is_common_name": true,
+						"name":           "example.com",
+					},
+				},
+			},
+			update: map[string]any{"description": "Updated DV certificate"},
+			kind:   fakeapi.KindCPSCertificate,
+		},
+		"rules engine policy": {
+			resource: "edgecast_rules_engine_policy",
+			config: map[string]any{
+				"account_number": testAccount,
+				"deploy_to":      "staging",
+				"policy":         testPolicy("test policy"),
+			},
+			update:  map[string]any{"policy": testPolicy("updated policy")},
+			kind:    fakeapi.KindRulesEnginePolicy,
+			account: testAccount,
+			kept:    true,
+		},
+	}
+
+	for name, tt := range tests {
+		tt := tt
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			s := fakeapi.New()
+			defer s.Close()
+
+			testResourceLifecycle(t, s, tt)
+		})
+	}
+}
+
+func testResourceLifecycle(t *testing.T, s *fakeapi.Server, tt resourceCase) {
+	t.Helper()
+
+	ctx := context.Background()
+	meta := configure(t, s)
+	r := edgecast.Provider().ResourcesMap[tt.resource]
+
+	d := schema.TestResourceDataRaw(t, r.Schema, tt.config)
+	if diags := r.CreateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("create: %v", diags)
+	}
+
+	if len(d.Id()) == 0 {
+		t.Fatal("create: expected an ID")
+	}
+
+	if got := s.Count(tt.kind, tt.account); got == 0 {
+		t.Fatalf("create: expected a %s in the fake", tt.kind)
+	}
+
+	if diags := r.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read: %v", diags)
+	}
+
+	for key, value := range tt.update {
+		if err := d.Set(key, value); err != nil {
+			t.Fatalf("setting %s: %v", key, err)
+		}
+	}
+
+	if diags := r.UpdateContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("update: %v", diags)
+	}
+
+	id := d.Id()
+	if diags := r.DeleteContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+
+	if tt.kept {
+		return
+	}
+
+	d.SetId(id)
+	if diags := r.ReadContext(ctx, d, meta); diags.HasError() {
+		t.Fatalf("read after delete: %v", diags)
+	}
+
+	if len(d.Id()) != 0 {
+		t.Errorf("read after delete: expected the ID to be cleared, got %s", d.Id())
+	}
+}
+
+func TestCertificateDeleteFlow(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		status     string
+		wantStatus string
+		wantExists bool
+	}{
+		{
+			name:       "unplaced request is canceled",
+			status:     fakeapi.CertificateStatusProcessing,
+			wantStatus: fakeapi.CertificateStatusDeleted,
+			wantExists: true,
+		},
+		{
+			name:       "pending validation is canceled",
+			status:     fakeapi.CertificateStatusDCV,
+			wantStatus: fakeapi.CertificateStatusDeleted,
+			wantExists: true,
+		},
+		{
+			name:       "issued certificate is deleted",
+			status:     fakeapi.CertificateStatusActive,
+			wantExists: false,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := fakeapi.New()
+			defer s.Close()
+
+			ctx := context.Background()
+			meta := configure(t, s)
+			r := edgecast.Provider().ResourcesMap["edgecast_cps_certificate"]
+
+			d := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+				"certificate_label": "Test certificate",
+				"validation_type":   "DV",
+				"dcv_method":        "DnsTxtToken",
+				"domain": []any{
+					map[string]any{
+						"is_common_name": true,
+						"name":           "example.com",
+					},
+				},
+			})
+			if diags := r.CreateContext(ctx, d, meta); diags.HasError() {
+				t.Fatalf("create: %v", diags)
+			}
+
+			id := d.Id()
+			s.SetCertificateStatus(id, tt.status)
+
+			if diags := r.DeleteContext(ctx, d, meta); diags.HasError() {
+				t.Fatalf("delete: %v", diags)
+			}
+
+			status, exists := s.CertificateStatus(id)
+			if exists != tt.wantExists || status != tt.wantStatus {
+				t.Errorf("expected status %q (exists: %t), got %q (exists: %t)",
+					tt.wantStatus, tt.wantExists, status, exists)
+			}
+		})
+	}
+}
+
+func TestRulesEnginePolicyRollback(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	ctx := context.Background()
+	meta := configure(t, s)
+	r := edgecast.Provider().ResourcesMap["edgecast_rules_engine_policy"]
+
+	first := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+		"account_number": testAccount,
+		"deploy_to":      "staging",
+		"policy":         testPolicy("first policy"),
+	})
+	if diags := r.CreateContext(ctx, first, meta); diags.HasError() {
+		t.Fatalf("create first: %v", diags)
+	}
+
+	second := schema.TestResourceDataRaw(t, r.Schema, map[string]any{
+		"account_number": testAccount,
+		"deploy_to":      "staging",
+		"policy":         testPolicy("second policy"),
+		"destroy_mode":   "rollback",
+	})
+	if diags := r.CreateContext(ctx, second, meta); diags.HasError() {
+		t.Fatalf("create second: %v", diags)
+	}
+
+	if got := second.Get("previous_policy_id"); got != first.Id() {
+		t.Fatalf("expected previous_policy_id %q, got %q", first.Id(), got)
+	}
+
+	if diags := r.DeleteContext(ctx, second, meta); diags.HasError() {
+		t.Fatalf("delete: %v", diags)
+	}
+
+	// Rolling back redeploys the first policy instead of adding a placeholder.
+	if got := s.Count(fakeapi.KindRulesEnginePolicy, testAccount); got != 2 {
+		t.Errorf("expected 2 policies, got %d", got)
+	}
+
+	if got := s.Count(fakeapi.KindRulesEngineDeployRequest, testAccount); got != 3 {
+		t.Errorf("expected 3 deploy requests, got %d", got)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	t.Parallel()
+
+	s := fakeapi.New()
+	defer s.Close()
+
+	req, err := http.NewRequest(
+		http.MethodGet,
+		s.URL+"/v2/mcc/customers/"+testAccount+"/waf/v1.0/acl",
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "TOK:wrong-token")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", resp.StatusCode)
+	}
+
+	if len(resp.Header.Get("X-Request-Id")) == 0 {
+		t.Error("expected an X-Request-Id header")
+	}
+}
+
+// configure configures the provider against the fake and returns its meta.
+func configure(t *testing.T, s *fakeapi.Server) any {
+	t.Helper()
+
+	p := edgecast.Provider()
+
+	diags := p.Configure(
+		context.Background(),
+		terraform.NewResourceConfigRaw(s.Settings()))
+	if diags.HasError() {
+		t.Fatalf("configuring provider: %v", diags)
+	}
+
+	return p.Meta()
+}
+
+func testPolicy(name string) string {
+	return strings.ReplaceAll(`{
+  "name": "NAME",
+  "description": "test",
+  "platform": "http_large",
+  "rules": [
+    {
+      "name": "rule 1",
+      "matches": [
+        {
+          "type": "match.always",
+          "features": [
+            {
+              "type": "feature.comment",
+              "value": "test"
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}`, "NAME", name)
+}
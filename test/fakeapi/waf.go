@@ -0,0 +1,168 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+
+package fakeapi
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Kinds of WAF objects, for use with Count.
+const (
+	KindWAFAccessRule    = "waf_access_rule"
+	KindWAFBotRuleSet    = "waf_bot_rule_set"
+	KindWAFCustomRuleSet = "waf_custom_rule_set"
+	KindWAFManagedRule   = "waf_managed_rule"
+	KindWAFRateRule      = "waf_rate_rule"
+	KindWAFBotManager    = "waf_bot_manager"
+	kindWAFScopes        = "waf_scopes"
+)
+
+const wafBasePath = "/v2/mcc/customers/{account_number}/waf/v1.0"
+
+// registerWAF registers the WAF rule, scopes and bot manager endpoints of the
+// legacy API.
+func (s *Server) registerWAF() {
+	s.registerWAFRules(KindWAFAccessRule, wafBasePath+"/acl")
+	s.registerWAFRules(KindWAFBotRuleSet, wafBasePath+"/bots")
+	s.registerWAFRules(KindWAFCustomRuleSet, wafBasePath+"/rules")
+	s.registerWAFRules(KindWAFManagedRule, wafBasePath+"/profile")
+	s.registerWAFRules(KindWAFRateRule, wafBasePath+"/limit")
+	s.registerWAFRules(KindWAFBotManager, wafBasePath+"/bot-manager")
+
+	s.handle(http.MethodGet, wafBasePath+"/scopes", s.getWAFScopes)
+	s.handle(http.MethodPost, wafBasePath+"/scopes", s.modifyWAFScopes)
+}
+
+// registerWAFRules registers the endpoints of a WAF rule collection. Every
+// collection is managed the same way: rules are created by POST to the
+// collection and read, replaced or deleted by ID.
+func (s *Server) registerWAFRules(kind string, path string) {
+	s.handle(http.MethodPost, path,
+		func(w http.ResponseWriter, r *request) {
+			obj, ok := r.decodeOrFail(w)
+			if !ok {
+				return
+			}
+
+			account := r.vars["account_number"]
+			id := strconv.Itoa(s.newID())
+			s.insert(kind, account, id, wafRule(obj, account, id))
+
+			writeWAFResponse(w, id)
+		})
+
+	s.handle(http.MethodGet, path,
+		func(w http.ResponseWriter, r *request) {
+			writeJSON(w, http.StatusOK, s.list(kind, r.vars["account_number"]))
+		})
+
+	s.handle(http.MethodGet, path+"/{id}",
+		func(w http.ResponseWriter, r *request) {
+			obj, ok := s.lookup(kind, r.vars["account_number"], r.vars["id"])
+			if !ok {
+				writeNotFound(w, kind, r.vars["id"])
+				return
+			}
+
+			writeJSON(w, http.StatusOK, obj)
+		})
+
+	s.handle(http.MethodPut, path+"/{id}",
+		func(w http.ResponseWriter, r *request) {
+			account, id := r.vars["account_number"], r.vars["id"]
+			if _, ok := s.lookup(kind, account, id); !ok {
+				writeNotFound(w, kind, id)
+				return
+			}
+
+			obj, ok := r.decodeOrFail(w)
+			if !ok {
+				return
+			}
+
+			s.insert(kind, account, id, wafRule(obj, account, id))
+
+			writeWAFResponse(w, id)
+		})
+
+	s.handle(http.MethodDelete, path+"/{id}",
+		func(w http.ResponseWriter, r *request) {
+			if !s.remove(kind, r.vars["account_number"], r.vars["id"]) {
+				writeNotFound(w, kind, r.vars["id"])
+				return
+			}
+
+			writeWAFResponse(w, r.vars["id"])
+		})
+}
+
+// wafRule adds the read-only properties returned by the WAF API to a rule.
+func wafRule(obj map[string]any, account string, id string) map[string]any {
+	obj["id"] = id
+	obj["customer_id"] = account
+	obj["last_modified_date"] = time.Now().UTC().Format(time.RFC3339)
+
+	return obj
+}
+
+func writeWAFResponse(w http.ResponseWriter, id string) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"id":      id,
+		"success": true,
+		"status":  "success",
+		"errors":  []any{},
+	})
+}
+
+func (s *Server) getWAFScopes(w http.ResponseWriter, r *request) {
+	account := r.vars["account_number"]
+
+	obj, ok := s.lookup(kindWAFScopes, account, account)
+	if !ok {
+		obj = map[string]any{
+			"customer_id": account,
+			"scopes":      []any{},
+		}
+	}
+
+	writeJSON(w, http.StatusOK, obj)
+}
+
+// modifyWAFScopes replaces the scopes of an account. Scopes without an ID are
+// given one.
+func (s *Server) modifyWAFScopes(w http.ResponseWriter, r *request) {
+	obj, ok := r.decodeOrFail(w)
+	if !ok {
+		return
+	}
+
+	account := r.vars["account_number"]
+
+	if scopes, ok := obj["scopes"].([]any); ok {
+		for _, item := range scopes {
+			scope, ok := item.(map[string]any)
+			if !ok {
+				continue
+			}
+
+			if id, _ := scope["id"].(string); len(id) == 0 {
+				scope["id"] = strconv.Itoa(s.newID())
+			}
+		}
+	}
+
+	id := account
+	if existing, ok := s.lookup(kindWAFScopes, account, account); ok {
+		id, _ = existing["id"].(string)
+	}
+
+	obj["id"] = id
+	obj["customer_id"] = account
+	obj["last_modified_date"] = time.Now().UTC().Format(time.RFC3339)
+	s.insert(kindWAFScopes, account, account, obj)
+
+	writeWAFResponse(w, id)
+}
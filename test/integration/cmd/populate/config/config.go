@@ -32,6 +32,14 @@ type Config struct {
 	// Indicates which resources should be populated
 	PopulateFlags Set
 
+	// Whether to create, reuse or clean up data. One of ModeCreate, ModeReuse
+	// or ModeCleanup.
+	Mode string
+
+	// The file that results are written to, and in ModeReuse and ModeCleanup
+	// read from. If empty in ModeCreate, a timestamped file is used.
+	ResultFile string
+
 	// A timestamped test email used for testing purposes.
 	TestEmail string
 
@@ -51,6 +59,8 @@ func NewConfig() Config {
 		AccountNumber: getEnvRequired(envAccountNumber),
 		TestEmail:     getTestEmail(),
 		PopulateFlags: make(map[string]bool),
+		Mode:          internal.GetEnvWithDefault(envPopulateMode, ModeCreate),
+		ResultFile:    internal.GetEnvWithDefault(envResultFile, ""),
 		SDKConfig:     createSDKConfig(),
 		APITokenPCC:   getEnvRequired(envAPITokenPCC),
 		AccountEmail:  getEnvRequired(envMCCEmail),
@@ -63,6 +73,26 @@ func NewConfig() Config {
 		}
 	}
 
+	switch cfg.Mode {
+	case ModeCreate:
+	case ModeReuse, ModeCleanup:
+		if len(cfg.ResultFile) == 0 {
+			internal.CheckError(fmt.Errorf(
+				"%s=%s requires %s to be set",
+				envPopulateMode,
+				cfg.Mode,
+				envResultFile))
+		}
+	default:
+		internal.CheckError(fmt.Errorf(
+			"invalid %s %q, must be one of %s, %s or %s",
+			envPopulateMode,
+			cfg.Mode,
+			ModeCreate,
+			ModeReuse,
+			ModeCleanup))
+	}
+
 	return cfg
 }
 
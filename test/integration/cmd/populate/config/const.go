@@ -4,6 +4,8 @@ const (
 	formatTestEmail     = "devenablement+testing%d@edgecast.com"
 	envAccountNumber    = "ACCOUNT_NUMBER"
 	envPopulateOnly     = "POPULATE_ONLY"
+	envPopulateMode     = "POPULATE_MODE"
+	envResultFile       = "RESULT_FILE"
 	envAPIToken         = "API_TOKEN"
 	envAPITokenPCC      = "API_TOKEN_PCC"
 	envAPIAddress       = "API_ADDRESS"
@@ -15,3 +17,15 @@ const (
 	envLogFile          = "LOG_FILE"
 	envMCCEmail         = "TF_VAR_MCC_ACCOUNT_EMAIL"
 )
+
+const (
+	// ModeCreate creates new data on every run.
+	ModeCreate = "create"
+
+	// ModeReuse reuses the data recorded in the result file that still
+	// exists and creates the rest.
+	ModeReuse = "reuse"
+
+	// ModeCleanup deletes the data recorded in the result file.
+	ModeCleanup = "cleanup"
+)
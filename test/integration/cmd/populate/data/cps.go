@@ -5,6 +5,7 @@ package data
 import (
 	"time"
 
+	"terraform-provider-edgecast/edgecast/helper"
 	"terraform-provider-edgecast/test/integration/cmd/populate/config"
 	"terraform-provider-edgecast/test/integration/cmd/populate/internal"
 
@@ -25,6 +26,56 @@ func createCPSData(cfg config.Config) CPSResult {
 	}
 }
 
+func cpsDataExists(cfg config.Config, r CPSResult) (bool, error) {
+	svc := internal.Check(cps.New(cfg.SDKConfig))
+
+	params := certificate.NewCertificateGetCertificateStatusParams()
+	params.ID = r.CertificateID
+
+	resp, err := svc.Certificate.CertificateGetCertificateStatus(params)
+	if helper.IsNotFound(err) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return resp.Status != "Deleted", nil
+}
+
+// deleteCPSData cancels the certificate if it has not been issued, and
+// deletes it otherwise.
+func deleteCPSData(cfg config.Config, r CPSResult) error {
+	svc := internal.Check(cps.New(cfg.SDKConfig))
+
+	statusParams := certificate.NewCertificateGetCertificateStatusParams()
+	statusParams.ID = r.CertificateID
+
+	status, err := svc.Certificate.CertificateGetCertificateStatus(statusParams)
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+
+	switch status.Status {
+	case "Deleted":
+		return nil
+	case "Processing", "DomainControlValidation", "OtherValidation":
+		params := certificate.NewCertificateCancelParams()
+		params.ID = r.CertificateID
+		params.Apply = true
+
+		_, err = svc.Certificate.CertificateCancel(params)
+	default:
+		params := certificate.NewCertificateDeleteParams()
+		params.ID = r.CertificateID
+
+		_, err = svc.Certificate.CertificateDelete(params)
+	}
+
+	return ignoreNotFound(err)
+}
+
 func createCertificate(svc *cps.CpsService) int64 {
 	certParams := certificate.NewCertificatePostParams()
 	certParams.Certificate = &models.CertificateCreate{
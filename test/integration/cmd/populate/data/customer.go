@@ -10,22 +10,85 @@ import (
 )
 
 func createCustomerData(cfg config.Config) CustomerResult {
-	// Need to use a PCC token to create a customer.
-	pccSDKConfig := cfg.SDKConfig
-	pccSDKConfig.APIToken = cfg.APITokenPCC
-
-	svc := internal.Check(customer.New(pccSDKConfig))
+	svc := newCustomerService(cfg)
 	accountNumber := cfg.AccountNumber
+	createdCustomer := false
 
 	if accountNumber == "" {
 		accountNumber = internal.Check(createCustomer(svc))
+		createdCustomer = true
 	}
 
 	customerUserID := internal.Check(
 		createCustomerUser(svc, accountNumber, cfg.TestEmail),
 	)
 
-	return CustomerResult{accountNumber, customerUserID}
+	return CustomerResult{accountNumber, customerUserID, createdCustomer}
+}
+
+func customerDataExists(cfg config.Config, r CustomerResult) (bool, error) {
+	svc := newCustomerService(cfg)
+
+	var cust *customer.CustomerGetOK
+	return allFound(
+		func() (err error) {
+			cust, err = svc.GetCustomer(customer.GetCustomerParams{
+				AccountNumber: r.AccountNumber,
+			})
+			return err
+		},
+		func() error {
+			_, err := svc.GetCustomerUser(customer.GetCustomerUserParams{
+				Customer:       *cust,
+				CustomerUserID: r.CustomerUserID,
+			})
+			return err
+		},
+	)
+}
+
+func deleteCustomerData(cfg config.Config, r CustomerResult) error {
+	svc := newCustomerService(cfg)
+
+	cust, err := svc.GetCustomer(customer.GetCustomerParams{
+		AccountNumber: r.AccountNumber,
+	})
+	if err != nil {
+		return ignoreNotFound(err)
+	}
+
+	err = deleteIfFound(
+		func() (*customer.CustomerUserGetOK, error) {
+			return svc.GetCustomerUser(customer.GetCustomerUserParams{
+				Customer:       *cust,
+				CustomerUserID: r.CustomerUserID,
+			})
+		},
+		func(user customer.CustomerUserGetOK) error {
+			return svc.DeleteCustomerUser(customer.DeleteCustomerUserParams{
+				Customer:     *cust,
+				CustomerUser: user,
+			})
+		})
+	if err != nil {
+		return err
+	}
+
+	if !r.CreatedCustomer {
+		return nil
+	}
+
+	return ignoreNotFound(
+		svc.DeleteCustomer(customer.DeleteCustomerParams{Customer: *cust}))
+}
+
+// newCustomerService returns a customer service that uses the PCC token, which
+// is needed to create customers.
+func newCustomerService(cfg config.Config) *customer.CustomerService {
+	pccSDKConfig := cfg.SDKConfig
+	pccSDKConfig.APIToken = cfg.APITokenPCC
+
+	return internal.Check(customer.New(pccSDKConfig))
 }
 
 func createCustomerUser(
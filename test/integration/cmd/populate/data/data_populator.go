@@ -3,7 +3,7 @@
 package data
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"terraform-provider-edgecast/test/integration/cmd/populate/config"
+	"terraform-provider-edgecast/test/integration/cmd/populate/internal"
 
 	"github.com/kr/pretty"
 )
@@ -28,103 +29,156 @@ func (d DataPopulator) PopulateResource(resourceName string) bool {
 		d.Config.PopulateFlags[resourceName]
 }
 
-func (d DataPopulator) Populate() {
+// Run populates or cleans up data depending on the configured mode.
+func (d DataPopulator) Run() {
 	if len(d.Config.PopulateFlags) > 0 {
 		fmt.Printf(
-			"populating data for only: %v\n",
+			"%s data for only: %v\n",
+			d.Config.Mode,
 			pretty.Formatter(d.Config.PopulateFlags.ToList()))
 	}
 
-	result := PopulationResult{}
-
-	if d.PopulateResource("customer") {
-		fmt.Println("populating data: customer")
-		result.Customer = createCustomerData(d.Config)
-		fmt.Printf("Customer result: %v\n", result.Customer)
+	if d.Config.Mode == config.ModeCleanup {
+		d.Cleanup()
 	} else {
-		fmt.Println("skipping populate: customer")
+		d.Populate()
 	}
+}
 
-	if d.PopulateResource("origin") {
-		fmt.Println("populating data: origin")
-		result.Origin = createOriginData(d.Config)
-		fmt.Printf("Origin result: %v\n", result.Origin)
-	} else {
-		fmt.Println("skipping populate: origin")
+// Populate creates data for each resource and writes the results to a file.
+// The file is written after each resource's data is created or deleted, so
+// that data created before a failure can be reused or cleaned up. In reuse
+// mode, data recorded in the results file that still exists is kept instead
+// of being created again.
+func (d DataPopulator) Populate() {
+	file := d.Config.ResultFile
+	if len(file) == 0 {
+		file = fmt.Sprintf("result-%s.json", time.Now().Format(timestampFormat))
 	}
 
-	if d.PopulateResource("cname") {
-		fmt.Println("populating data: cname")
-		result.CNAME = createEdgeCnameData(d.Config)
-		fmt.Printf("CNAME result: %v\n", result.CNAME)
-	} else {
-		fmt.Println("skipping populate: cname")
+	result := PopulationResult{}
+	if d.Config.Mode == config.ModeReuse {
+		result = internal.Check(readResult(file))
+	} else if _, err := os.Stat(file); err == nil {
+		internal.CheckError(fmt.Errorf(
+			"results file %s already exists, reuse or clean up its data first",
+			file))
 	}
 
-	if d.PopulateResource("dns") {
-		fmt.Println("populating data: dns")
-		result.DNS = createDNSData(d.Config)
-		fmt.Printf("DNS result: %v\n", result.DNS)
-	} else {
-		fmt.Println("skipping populate: dns")
+	for _, f := range d.fixtures(&result) {
+		if !d.PopulateResource(f.name) {
+			fmt.Println("skipping populate:", f.name)
+			continue
+		}
+
+		if d.Config.Mode == config.ModeReuse && f.populated() {
+			if internal.Check(f.exists()) {
+				fmt.Println("reusing data:", f.name)
+				continue
+			}
+
+			// Remove what is left so that it is not orphaned.
+			fmt.Println("data incomplete, recreating:", f.name)
+			internal.CheckError(f.delete())
+			saveResult(file, result)
+		}
+
+		fmt.Println("populating data:", f.name)
+		f.create()
+		fmt.Printf("%s result: %v\n", f.title, f.result())
+		saveResult(file, result)
 	}
 
-	if d.PopulateResource("waf") {
-		fmt.Println("populating data: waf")
-		result.WAF = createWAFData(d.Config)
-		fmt.Printf("WAF result: %v\n", result.WAF)
-	} else {
-		fmt.Println("skipping populate: waf")
-	}
+	workingDirectory, _ := os.Getwd()
+	fmt.Println("result file: ", path.Join(workingDirectory, file))
+	fmt.Println("tfvars file: ", path.Join(workingDirectory, tfvarsFile(file)))
+}
 
-	if d.PopulateResource("rules_engine") {
-		fmt.Println("populating data: rules_engine")
-		result.RulesEngine = createRulesEnginePolicyData(d.Config)
-		fmt.Printf("Rules Engine result: %v\n", result.RulesEngine)
-	} else {
-		fmt.Println("skipping populate: rules_engine")
+// saveResult writes result to file. If it cannot be written, the result is
+// logged instead so that its data can be cleaned up by hand, and the
+// application exits.
+func saveResult(file string, result PopulationResult) {
+	err := writeResult(file, result)
+	if err != nil {
+		log.Println(err)
+		log.Printf("result: %# v\n", pretty.Formatter(result))
+		os.Exit(1)
 	}
+}
 
-	if d.PopulateResource("originv3") {
-		fmt.Println("populating data: originv3")
-		result.OriginV3 = createOriginV3Data(d.Config)
-		fmt.Printf("OriginV3 result: %v\n", result.OriginV3)
-	} else {
-		fmt.Println("skipping populate: originv3")
+// Cleanup deletes the data recorded in the results file, in the reverse order
+// of creation. Data that could not be deleted is kept in the results file so
+// that cleanup can be run again. Once all data is deleted, the results file
+// is removed.
+func (d DataPopulator) Cleanup() {
+	file := d.Config.ResultFile
+	if _, err := os.Stat(file); errors.Is(err, os.ErrNotExist) {
+		internal.CheckError(fmt.Errorf("results file %s not found", file))
 	}
 
-	if d.PopulateResource("cps") {
-		fmt.Println("populating data: cps")
-		result.CPS = createCPSData(d.Config)
-		fmt.Printf("CPS result: %v\n", result.CPS)
-	} else {
-		fmt.Println("skipping populate: cps")
+	result := internal.Check(readResult(file))
+	fixtures := d.fixtures(&result)
+
+	failed := false
+	remaining := false
+	for i := len(fixtures) - 1; i >= 0; i-- {
+		f := fixtures[i]
+		if !f.populated() {
+			continue
+		}
+
+		if !d.PopulateResource(f.name) {
+			fmt.Println("skipping cleanup:", f.name)
+			remaining = true
+			continue
+		}
+
+		fmt.Println("cleaning up data:", f.name)
+		if err := f.delete(); err != nil {
+			log.Printf("error cleaning up %s: %v\n", f.name, err)
+			failed = true
+			remaining = true
+		}
 	}
 
-	if d.PopulateResource("waf_botmanager") {
-		fmt.Println("populating data: waf_botmanager")
-		result.BotManager = createWAFBotManagerData(d.Config)
-		fmt.Printf("WAF Bot Manager result: %v\n", result.BotManager)
+	if remaining {
+		internal.CheckError(writeResult(file, result))
 	} else {
-		fmt.Println("skipping populate: waf_botmanager")
+		internal.CheckError(removeResult(file))
 	}
 
-	// Write results to a file
-	jsonBytes, err := json.MarshalIndent(result, "", "    ")
-	if err != nil {
-		log.Printf("error marshaling results to JSON: %v\n", err)
-		log.Printf("result: %# v\n", pretty.Formatter(result))
+	if failed {
 		os.Exit(1)
 	}
+}
 
-	file := fmt.Sprintf("result-%s.json", time.Now().Format(timestampFormat))
-	err = os.WriteFile(file, jsonBytes, 0o644)
-	if err != nil {
-		log.Printf("error writing results file: %v\n", err)
-		log.Printf("result json: %s\n", string(jsonBytes))
-		os.Exit(1)
+// fixtures returns the data that is populated, in order of creation. Each
+// fixture reads and writes its part of result.
+func (d DataPopulator) fixtures(result *PopulationResult) []fixture {
+	cfg := d.Config
+
+	return []fixture{
+		newFixture(cfg, "customer", "Customer", &result.Customer,
+			createCustomerData, customerDataExists, deleteCustomerData),
+		newFixture(cfg, "origin", "Origin", &result.Origin,
+			createOriginData, originDataExists, deleteOriginData),
+		newFixture(cfg, "cname", "CNAME", &result.CNAME,
+			createEdgeCnameData, edgeCnameDataExists, deleteEdgeCnameData),
+		newFixture(cfg, "dns", "DNS", &result.DNS,
+			createDNSData, dnsDataExists, deleteDNSData),
+		newFixture(cfg, "waf", "WAF", &result.WAF,
+			createWAFData, wafDataExists, deleteWAFData),
+		newFixture(cfg, "rules_engine", "Rules Engine", &result.RulesEngine,
+			createRulesEnginePolicyData,
+			rulesEnginePolicyDataExists,
+			deleteRulesEnginePolicyData),
+		newFixture(cfg, "originv3", "OriginV3", &result.OriginV3,
+			createOriginV3Data, originV3DataExists, deleteOriginV3Data),
+		newFixture(cfg, "cps", "CPS", &result.CPS,
+			createCPSData, cpsDataExists, deleteCPSData),
+		newFixture(cfg, "waf_botmanager", "WAF Bot Manager", &result.BotManager,
+			createWAFBotManagerData,
+			wafBotManagerDataExists,
+			deleteWAFBotManagerData),
 	}
-
-	workingDirectory, _ := os.Getwd()
-	fmt.Println("result file: ", path.Join(workingDirectory, file))
 }
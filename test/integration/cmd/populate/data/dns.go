@@ -39,6 +39,146 @@ func createDNSData(cfg config.Config) DNSResult {
 	}
 }
 
+func dnsDataExists(cfg config.Config, r DNSResult) (bool, error) {
+	svc := internal.Check(routedns.New(cfg.SDKConfig))
+	accountNumber := cfg.AccountNumber
+
+	return allFound(
+		func() error {
+			_, err := svc.GetZone(routedns.GetZoneParams{
+				AccountNumber: accountNumber,
+				ZoneID:        r.ZoneID,
+			})
+			return err
+		},
+		func() error {
+			_, err := svc.GetGroup(routedns.GetGroupParams{
+				AccountNumber:    accountNumber,
+				GroupID:          r.GroupID,
+				GroupProductType: routedns.LoadBalancing,
+			})
+			return err
+		},
+		func() error {
+			_, err := svc.GetSecondaryZoneGroup(
+				routedns.GetSecondaryZoneGroupParams{
+					AccountNumber: accountNumber,
+					ID:            r.SecondaryServerGroupID,
+				})
+			return err
+		},
+		func() error {
+			_, err := svc.GetMasterServerGroup(
+				routedns.GetMasterServerGroupParams{
+					AccountNumber:       accountNumber,
+					MasterServerGroupID: r.MasterServerGroupID,
+				})
+			return err
+		},
+		func() error {
+			_, err := svc.GetTSIG(routedns.GetTSIGParams{
+				AccountNumber: accountNumber,
+				TSIGID:        r.TsgID,
+			})
+			return err
+		},
+	)
+}
+
+// deleteDNSData deletes DNS data in the reverse order of creation, since the
+// secondary zone group references the master server group and TSIG key.
+func deleteDNSData(cfg config.Config, r DNSResult) error {
+	svc := internal.Check(routedns.New(cfg.SDKConfig))
+	accountNumber := cfg.AccountNumber
+
+	err := deleteIfFound(
+		func() (*routedns.ZoneGetOK, error) {
+			return svc.GetZone(routedns.GetZoneParams{
+				AccountNumber: accountNumber,
+				ZoneID:        r.ZoneID,
+			})
+		},
+		func(zone routedns.ZoneGetOK) error {
+			return svc.DeleteZone(routedns.DeleteZoneParams{
+				AccountNumber: accountNumber,
+				Zone:          zone,
+			})
+		})
+	if err != nil {
+		return err
+	}
+
+	err = deleteIfFound(
+		func() (*routedns.DnsRouteGroupOK, error) {
+			return svc.GetGroup(routedns.GetGroupParams{
+				AccountNumber:    accountNumber,
+				GroupID:          r.GroupID,
+				GroupProductType: routedns.LoadBalancing,
+			})
+		},
+		func(group routedns.DnsRouteGroupOK) error {
+			return svc.DeleteGroup(routedns.DeleteGroupParams{
+				AccountNumber: accountNumber,
+				Group:         group,
+			})
+		})
+	if err != nil {
+		return err
+	}
+
+	err = deleteIfFound(
+		func() (*routedns.SecondaryZoneGroupResponseOK, error) {
+			return svc.GetSecondaryZoneGroup(
+				routedns.GetSecondaryZoneGroupParams{
+					AccountNumber: accountNumber,
+					ID:            r.SecondaryServerGroupID,
+				})
+		},
+		func(szg routedns.SecondaryZoneGroupResponseOK) error {
+			return svc.DeleteSecondaryZoneGroup(
+				routedns.DeleteSecondaryZoneGroupParams{
+					AccountNumber:      accountNumber,
+					SecondaryZoneGroup: szg,
+				})
+		})
+	if err != nil {
+		return err
+	}
+
+	err = deleteIfFound(
+		func() (*routedns.MasterServerGroupAddGetOK, error) {
+			return svc.GetMasterServerGroup(
+				routedns.GetMasterServerGroupParams{
+					AccountNumber:       accountNumber,
+					MasterServerGroupID: r.MasterServerGroupID,
+				})
+		},
+		func(msg routedns.MasterServerGroupAddGetOK) error {
+			return svc.DeleteMasterServerGroup(
+				routedns.DeleteMasterServerGroupParams{
+					AccountNumber:     accountNumber,
+					MasterServerGroup: msg,
+				})
+		})
+	if err != nil {
+		return err
+	}
+
+	return deleteIfFound(
+		func() (*routedns.TSIGGetOK, error) {
+			return svc.GetTSIG(routedns.GetTSIGParams{
+				AccountNumber: accountNumber,
+				TSIGID:        r.TsgID,
+			})
+		},
+		func(tsig routedns.TSIGGetOK) error {
+			return svc.DeleteTSIG(routedns.DeleteTSIGParams{
+				AccountNumber: accountNumber,
+				TSIG:          tsig,
+			})
+		})
+}
+
 func createZone(svc *routedns.RouteDNSService, accountNumber string) int {
 	params := routedns.AddZoneParams{
 		AccountNumber: accountNumber,
@@ -16,6 +16,36 @@ func createEdgeCnameData(cfg config.Config) CNAMEResult {
 	return CNAMEResult{edgeCnameID}
 }
 
+func edgeCnameDataExists(cfg config.Config, r CNAMEResult) (bool, error) {
+	svc := internal.Check(edgecname.New(cfg.SDKConfig))
+
+	return allFound(func() error {
+		_, err := svc.GetEdgeCname(edgecname.GetEdgeCnameParams{
+			AccountNumber: cfg.AccountNumber,
+			EdgeCnameID:   r.EdgeCnameID,
+		})
+		return err
+	})
+}
+
+func deleteEdgeCnameData(cfg config.Config, r CNAMEResult) error {
+	svc := internal.Check(edgecname.New(cfg.SDKConfig))
+
+	return deleteIfFound(
+		func() (*edgecname.EdgeCnameGetOK, error) {
+			return svc.GetEdgeCname(edgecname.GetEdgeCnameParams{
+				AccountNumber: cfg.AccountNumber,
+				EdgeCnameID:   r.EdgeCnameID,
+			})
+		},
+		func(cname edgecname.EdgeCnameGetOK) error {
+			return svc.DeleteEdgeCname(edgecname.DeleteEdgeCnameParams{
+				AccountNumber: cfg.AccountNumber,
+				EdgeCname:     cname,
+			})
+		})
+}
+
 func createEdgeCname(
 	svc *edgecname.EdgeCnameService,
 	accountNumber string,
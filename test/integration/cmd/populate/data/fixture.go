@@ -0,0 +1,105 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package data
+
+import (
+	"terraform-provider-edgecast/edgecast/helper"
+	"terraform-provider-edgecast/test/integration/cmd/populate/config"
+)
+
+// fixture is a group of test data that is created, reused and cleaned up
+// together, e.g. all DNS data.
+type fixture struct {
+	// The POPULATE_ONLY value that selects the fixture.
+	name string
+
+	// Describes the fixture in output.
+	title string
+
+	// Reports whether the result holds data for the fixture.
+	populated func() bool
+
+	// Creates the data and stores it in the result.
+	create func()
+
+	// Reports whether all data in the result still exists.
+	exists func() (bool, error)
+
+	// Deletes the data in the result and clears it. Data that no longer
+	// exists is skipped.
+	delete func() error
+
+	// Returns the fixture's part of the result.
+	result func() any
+}
+
+// newFixture returns a fixture whose part of the result is stored in r.
+func newFixture[T comparable](
+	cfg config.Config,
+	name string,
+	title string,
+	r *T,
+	create func(config.Config) T,
+	exists func(config.Config, T) (bool, error),
+	del func(config.Config, T) error,
+) fixture {
+	var zero T
+
+	return fixture{
+		name:      name,
+		title:     title,
+		populated: func() bool { return *r != zero },
+		create:    func() { *r = create(cfg) },
+		exists:    func() (bool, error) { return exists(cfg, *r) },
+		delete: func() error {
+			if err := del(cfg, *r); err != nil {
+				return err
+			}
+
+			*r = zero
+			return nil
+		},
+		result: func() any { return *r },
+	}
+}
+
+// allFound calls each check in turn, and reports whether none of them failed
+// because an object does not exist. Other errors are returned.
+func allFound(checks ...func() error) (bool, error) {
+	for _, check := range checks {
+		err := check()
+		if helper.IsNotFound(err) {
+			return false, nil
+		}
+
+		if err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// deleteIfFound retrieves an object with get and passes it to del. Objects
+// that no longer exist are skipped.
+func deleteIfFound[T any](get func() (*T, error), del func(T) error) error {
+	obj, err := get()
+	if err == nil {
+		err = del(*obj)
+	}
+
+	if helper.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// ignoreNotFound returns nil if err was caused by an object not existing.
+func ignoreNotFound(err error) error {
+	if helper.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
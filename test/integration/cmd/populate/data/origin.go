@@ -16,6 +16,38 @@ func createOriginData(cfg config.Config) OriginResult {
 	return OriginResult{id}
 }
 
+func originDataExists(cfg config.Config, r OriginResult) (bool, error) {
+	svc := internal.Check(origin.New(cfg.SDKConfig))
+
+	return allFound(func() error {
+		_, err := svc.GetOrigin(origin.GetOriginParams{
+			AccountNumber:    cfg.AccountNumber,
+			MediaTypeID:      enums.HttpLarge,
+			CustomerOriginID: r.OriginID,
+		})
+		return err
+	})
+}
+
+func deleteOriginData(cfg config.Config, r OriginResult) error {
+	svc := internal.Check(origin.New(cfg.SDKConfig))
+
+	return deleteIfFound(
+		func() (*origin.OriginGetOK, error) {
+			return svc.GetOrigin(origin.GetOriginParams{
+				AccountNumber:    cfg.AccountNumber,
+				MediaTypeID:      enums.HttpLarge,
+				CustomerOriginID: r.OriginID,
+			})
+		},
+		func(o origin.OriginGetOK) error {
+			return svc.DeleteOrigin(origin.DeleteOriginParams{
+				AccountNumber: cfg.AccountNumber,
+				Origin:        o,
+			})
+		})
+}
+
 func createOrigin(svc *origin.OriginService, accountNumber string) int {
 	params := origin.AddOriginParams{
 		AccountNumber: accountNumber,
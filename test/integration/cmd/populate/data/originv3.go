@@ -21,6 +21,36 @@ func createOriginV3Data(cfg config.Config) OriginV3Result {
 	}
 }
 
+func originV3DataExists(cfg config.Config, r OriginV3Result) (bool, error) {
+	svc := internal.Check(originv3.New(cfg.SDKConfig))
+
+	return allFound(
+		func() error {
+			_, err := svc.HttpLargeOnly.GetHttpLargeGroup(
+				originv3.GetHttpLargeGroupParams{GroupId: r.GroupIdV3})
+			return err
+		},
+		func() error {
+			_, err := svc.Common.GetOrigin(originv3.GetOriginParams{
+				MediaType: enums.HttpLarge.String(),
+				Id:        r.OriginIdV3,
+			})
+			return err
+		},
+	)
+}
+
+// deleteOriginV3Data deletes the origin group, which deletes its origins.
+func deleteOriginV3Data(cfg config.Config, r OriginV3Result) error {
+	svc := internal.Check(originv3.New(cfg.SDKConfig))
+
+	params := originv3.NewDeleteGroupParams()
+	params.GroupId = r.GroupIdV3
+	params.MediaType = enums.HttpLarge.String()
+
+	return ignoreNotFound(svc.Common.DeleteGroup(params))
+}
+
 func createOriginV3Group(svc *originv3.Service) int32 {
 	tlsSettings := originv3.TlsSettings{
 		PublicKeysToVerify: []string{
@@ -17,6 +17,10 @@ type PopulationResult struct {
 type CustomerResult struct {
 	AccountNumber  string `json:"account_number,omitempty"`
 	CustomerUserID int    `json:"customer_user_id,omitempty"`
+
+	// CreatedCustomer is true if the customer was created rather than given
+	// by ACCOUNT_NUMBER, so that cleanup deletes it.
+	CreatedCustomer bool `json:"created_customer,omitempty"`
 }
 
 type OriginResult struct {
@@ -44,6 +48,7 @@ type WAFResult struct {
 	CustomRuleID  string `json:"custom_rule_id,omitempty"`
 	ManagedRuleID string `json:"managed_rule_id,omitempty"`
 	ScopesID      string `json:"scopes_id,omitempty"`
+	ScopeName     string `json:"scope_name,omitempty"`
 }
 
 type RulseEngineResult struct {
@@ -61,4 +66,5 @@ type CPSResult struct {
 
 type BotManagerResult struct {
 	BotManagerID string `json:"bot_manager_id,omitempty"`
+	BotRuleID    string `json:"bot_rule_id,omitempty"`
 }
@@ -0,0 +1,133 @@
+// Copyright 2023 Edgecast Inc., Licensed under the terms of the Apache 2.0
+// license. See LICENSE file in project root for terms.
+package data
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// readResult reads the results of a previous run. If the file does not exist,
+// it returns an empty result.
+func readResult(file string) (PopulationResult, error) {
+	result := PopulationResult{}
+
+	jsonBytes, err := os.ReadFile(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return result, nil
+	}
+
+	if err != nil {
+		return result, fmt.Errorf("error reading results file: %w", err)
+	}
+
+	err = json.Unmarshal(jsonBytes, &result)
+	if err != nil {
+		return result, fmt.Errorf("error parsing results file: %w", err)
+	}
+
+	return result, nil
+}
+
+// writeResult writes the result to file, and as Terraform variables to the
+// file returned by tfvarsFile.
+func writeResult(file string, result PopulationResult) error {
+	jsonBytes, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		return fmt.Errorf("error marshaling results to JSON: %w", err)
+	}
+
+	err = os.WriteFile(file, jsonBytes, 0o644)
+	if err != nil {
+		return fmt.Errorf("error writing results file: %w", err)
+	}
+
+	jsonBytes, err = json.MarshalIndent(buildTFVars(result), "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling tfvars to JSON: %w", err)
+	}
+
+	err = os.WriteFile(tfvarsFile(file), jsonBytes, 0o644)
+	if err != nil {
+		return fmt.Errorf("error writing tfvars file: %w", err)
+	}
+
+	return nil
+}
+
+// removeResult removes the results file and its tfvars file.
+func removeResult(file string) error {
+	for _, f := range []string{file, tfvarsFile(file)} {
+		err := os.Remove(f)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tfvarsFile returns the name of the tfvars file written next to a results
+// file, e.g. result.json -> result.tfvars.json.
+func tfvarsFile(file string) string {
+	return strings.TrimSuffix(file, ".json") + ".tfvars.json"
+}
+
+// buildTFVars returns the variables of the integration Terraform configs set
+// from result: account_number, and import_ids, which holds the import ID of
+// each populated object keyed by resource type, for task run to import.
+func buildTFVars(result PopulationResult) map[string]any {
+	tfvars := make(map[string]any)
+
+	account := result.Customer.AccountNumber
+	if len(account) > 0 {
+		tfvars["account_number"] = account
+	}
+
+	importIDs := make(map[string]string)
+
+	// add records the import ID of an object, prefixed with the account
+	// number if the resource's import ID starts with it. Objects that were
+	// not populated have an empty or zero ID and are skipped.
+	add := func(resourceType string, id any, withAccount bool) {
+		s := fmt.Sprint(id)
+		if len(s) == 0 || s == "0" {
+			return
+		}
+
+		if withAccount {
+			s = account + ":" + s
+		}
+
+		importIDs[resourceType] = s
+	}
+
+	add("edgecast_customer", account, false)
+	add("edgecast_customer_user", result.Customer.CustomerUserID, true)
+	add("edgecast_dns_group", result.DNS.GroupID, true)
+	add("edgecast_dns_masterservergroup", result.DNS.MasterServerGroupID, true)
+	add("edgecast_dns_secondaryzonegroup", result.DNS.SecondaryServerGroupID, true)
+	add("edgecast_dns_tsig", result.DNS.TsgID, true)
+	add("edgecast_dns_zone", result.DNS.ZoneID, true)
+	add("edgecast_edgecname", result.CNAME.EdgeCnameID, true)
+	add("edgecast_origin", result.Origin.OriginID, true)
+	add("edgecast_waf_access_rule", result.WAF.AccessRuleID, true)
+	add("edgecast_waf_bot_rule_set", result.WAF.BotRuleID, true)
+	add("edgecast_waf_managed_rule", result.WAF.ManagedRuleID, true)
+	add("edgecast_waf_rate_rule", result.WAF.RateRuleID, true)
+	add("edgecast_waf_custom_rule_set", result.WAF.CustomRuleID, true)
+	add("edgecast_waf_scopes", result.WAF.ScopesID, true)
+	add("edgecast_rules_engine_policy", result.RulesEngine.PolicyID, true)
+	add("edgecast_cps_certificate", result.CPS.CertificateID, false)
+	add("edgecast_originv3_httplarge", result.OriginV3.GroupIdV3, false)
+	add("edgecast_waf_botmanager", result.BotManager.BotManagerID, true)
+
+	if len(importIDs) > 0 {
+		tfvars["import_ids"] = importIDs
+	}
+
+	return tfvars
+}
@@ -4,6 +4,7 @@ package data
 
 import (
 	"fmt"
+	"strconv"
 
 	"terraform-provider-edgecast/test/integration/cmd/populate/config"
 	"terraform-provider-edgecast/test/integration/cmd/populate/internal"
@@ -42,6 +43,36 @@ func createRulesEnginePolicyData(cfg config.Config) RulseEngineResult {
 	}
 }
 
+func rulesEnginePolicyDataExists(
+	cfg config.Config,
+	r RulseEngineResult,
+) (bool, error) {
+	svc := internal.Check(rulesengine.New(cfg.SDKConfig))
+
+	policyID, err := strconv.Atoi(r.PolicyID)
+	if err != nil {
+		return false, fmt.Errorf("invalid policy ID %q: %w", r.PolicyID, err)
+	}
+
+	return allFound(func() error {
+		_, err := svc.GetPolicy(rulesengine.GetPolicyParams{
+			AccountNumber: cfg.AccountNumber,
+			PolicyID:      policyID,
+		})
+		return err
+	})
+}
+
+// deleteRulesEnginePolicyData only forgets the policy, since the Rules Engine
+// API cannot delete policies.
+func deleteRulesEnginePolicyData(_ config.Config, r RulseEngineResult) error {
+	fmt.Printf(
+		"rules engine policy %s cannot be deleted, leaving it in place\n",
+		r.PolicyID)
+
+	return nil
+}
+
 func createPolicyV4(
 	svc *rulesengine.RulesEngineService,
 	accountNumber string,
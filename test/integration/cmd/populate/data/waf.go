@@ -25,7 +25,7 @@ func createWAFData(cfg config.Config) WAFResult {
 	wafBotRuleID := createBotRule(svc, cfg.AccountNumber)
 	wafRateRuleID := createWAFRateRule(svc, cfg.AccountNumber)
 	wafCustomRuleID := createWAFCustomRule(svc, cfg.AccountNumber)
-	wafScopesID := createWAFScopes(
+	wafScopesID, wafScopeName := createWAFScopes(
 		svc,
 		wafRateRuleID,
 		wafAccessRuleID,
@@ -41,9 +41,143 @@ func createWAFData(cfg config.Config) WAFResult {
 		CustomRuleID:  wafCustomRuleID,
 		ManagedRuleID: wafManagedRuleID,
 		ScopesID:      wafScopesID,
+		ScopeName:     wafScopeName,
 	}
 }
 
+func wafDataExists(cfg config.Config, r WAFResult) (bool, error) {
+	svc := internal.Check(waf.New(cfg.SDKConfig))
+	accountNumber := cfg.AccountNumber
+
+	found, err := allFound(
+		func() error {
+			_, err := svc.Rate.GetRateRule(rate.GetRateRuleParams{
+				AccountNumber: accountNumber,
+				RateRuleID:    r.RateRuleID,
+			})
+			return err
+		},
+		func() error {
+			_, err := svc.Access.GetAccessRule(access.GetAccessRuleParams{
+				AccountNumber: accountNumber,
+				AccessRuleID:  r.AccessRuleID,
+			})
+			return err
+		},
+		func() error {
+			_, err := svc.Bot.GetBotRuleSet(bot.GetBotRuleSetParams{
+				AccountNumber: accountNumber,
+				BotRuleSetID:  r.BotRuleID,
+			})
+			return err
+		},
+		func() error {
+			_, err := svc.Custom.GetCustomRuleSet(custom.GetCustomRuleSetParams{
+				AccountNumber:   accountNumber,
+				CustomRuleSetID: r.CustomRuleID,
+			})
+			return err
+		},
+		func() error {
+			_, err := svc.Managed.GetManagedRule(managed.GetManagedRuleParams{
+				AccountNumber: accountNumber,
+				ManagedRuleID: r.ManagedRuleID,
+			})
+			return err
+		},
+	)
+	if !found || err != nil {
+		return found, err
+	}
+
+	all, err := svc.Scopes.GetAllScopes(
+		scopes.GetAllScopesParams{AccountNumber: accountNumber})
+	if err != nil {
+		return false, err
+	}
+
+	for _, scope := range all.Scopes {
+		if scope.Name == r.ScopeName {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// deleteWAFData removes the scope first, since rules cannot be deleted while
+// a scope references them. Other scopes of the account are kept.
+func deleteWAFData(cfg config.Config, r WAFResult) error {
+	svc := internal.Check(waf.New(cfg.SDKConfig))
+	accountNumber := cfg.AccountNumber
+
+	all, err := svc.Scopes.GetAllScopes(
+		scopes.GetAllScopesParams{AccountNumber: accountNumber})
+	if err != nil {
+		return err
+	}
+
+	kept := make([]scopes.Scope, 0, len(all.Scopes))
+	for _, scope := range all.Scopes {
+		if scope.Name != r.ScopeName {
+			kept = append(kept, scope)
+		}
+	}
+
+	if len(kept) < len(all.Scopes) {
+		_, err = svc.Scopes.ModifyAllScopes(scopes.Scopes{
+			CustomerID: accountNumber,
+			Scopes:     kept,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	deletes := []func() error{
+		func() error {
+			return svc.Rate.DeleteRateRule(rate.DeleteRateRuleParams{
+				AccountNumber: accountNumber,
+				RateRuleID:    r.RateRuleID,
+			})
+		},
+		func() error {
+			return svc.Access.DeleteAccessRule(access.DeleteAccessRuleParams{
+				AccountNumber: accountNumber,
+				AccessRuleID:  r.AccessRuleID,
+			})
+		},
+		func() error {
+			return svc.Bot.DeleteBotRuleSet(bot.DeleteBotRuleSetParams{
+				AccountNumber: accountNumber,
+				BotRuleSetID:  r.BotRuleID,
+			})
+		},
+		func() error {
+			return svc.Custom.DeleteCustomRuleSet(
+				custom.DeleteCustomRuleSetParams{
+					AccountNumber:   accountNumber,
+					CustomRuleSetID: r.CustomRuleID,
+				})
+		},
+		func() error {
+			return svc.Managed.DeleteManagedRule(
+				managed.DeleteManagedRuleParams{
+					AccountNumber: accountNumber,
+					ManagedRuleID: r.ManagedRuleID,
+				})
+		},
+	}
+
+	for _, del := range deletes {
+		if err := ignoreNotFound(del()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func createWAFRateRule(svc *waf.WafService, accountNumber string) (id string) {
 	params := rate.AddRateRuleParams{
 		AccountNumber: accountNumber,
@@ -293,17 +427,18 @@ func createWAFScopes(
 	customRuleID,
 	botRuleID string,
 	accountNumber string,
-) (id string) {
+) (id, name string) {
 	trueVar := true
 	encodedMessage := base64.StdEncoding.EncodeToString([]byte("hello!"))
 	status404 := 404
 	redirectURL := "https://www.devenblment.com/redirected"
 
+	name = internal.Unique("-scope")
 	params := scopes.Scopes{
 		CustomerID: accountNumber,
 		Scopes: []scopes.Scope{
 			{
-				Name: internal.Unique("-scope"),
+				Name: name,
 				Host: scopes.MatchCondition{
 					Type:              "EM",
 					IsCaseInsensitive: &trueVar,
@@ -354,5 +489,5 @@ func createWAFScopes(
 		},
 	}
 
-	return internal.Check(svc.Scopes.ModifyAllScopes(params)).ID
+	return internal.Check(svc.Scopes.ModifyAllScopes(params)).ID, name
 }
@@ -12,6 +12,7 @@ import (
 
 	"github.com/EdgeCast/ec-sdk-go/edgecast"
 	"github.com/EdgeCast/ec-sdk-go/edgecast/waf"
+	"github.com/EdgeCast/ec-sdk-go/edgecast/waf/rules/bot"
 	"github.com/EdgeCast/ec-sdk-go/edgecast/waf_bot_manager"
 )
 
@@ -22,25 +23,79 @@ const (
 
 func createWAFBotManagerData(cfg config.Config) BotManagerResult {
 	wafSvc := internal.Check(waf.New(cfg.SDKConfig))
+	wbmSvc := newBotManagerService(cfg)
 
-	// bug work around - need to clear out IDS credentials
-	sdkCfgNoIDS := cfg.SDKConfig
-	sdkCfgNoIDS.IDSCredentials = edgecast.IDSCredentials{}
-	wbmSvc := internal.Check(waf_bot_manager.New(sdkCfgNoIDS))
-
-	id := createBotManager(wafSvc, wbmSvc, cfg.AccountNumber)
+	id, botRuleID := createBotManager(wafSvc, wbmSvc, cfg.AccountNumber)
 
 	return BotManagerResult{
 		BotManagerID: id,
+		BotRuleID:    botRuleID,
+	}
+}
+
+func wafBotManagerDataExists(
+	cfg config.Config,
+	r BotManagerResult,
+) (bool, error) {
+	wafSvc := internal.Check(waf.New(cfg.SDKConfig))
+	wbmSvc := newBotManagerService(cfg)
+
+	return allFound(
+		func() error {
+			params := waf_bot_manager.NewGetBotManagerParams()
+			params.CustId = cfg.AccountNumber
+			params.BotManagerId = r.BotManagerID
+
+			_, err := wbmSvc.BotManagers.GetBotManager(params)
+			return err
+		},
+		func() error {
+			_, err := wafSvc.Bot.GetBotRuleSet(bot.GetBotRuleSetParams{
+				AccountNumber: cfg.AccountNumber,
+				BotRuleSetID:  r.BotRuleID,
+			})
+			return err
+		},
+	)
+}
+
+// deleteWAFBotManagerData deletes the bot manager before the bot rule set
+// that it references.
+func deleteWAFBotManagerData(cfg config.Config, r BotManagerResult) error {
+	wafSvc := internal.Check(waf.New(cfg.SDKConfig))
+	wbmSvc := newBotManagerService(cfg)
+
+	params := waf_bot_manager.NewDeleteBotManagerParams()
+	params.CustId = cfg.AccountNumber
+	params.BotManagerId = r.BotManagerID
+
+	err := ignoreNotFound(wbmSvc.BotManagers.DeleteBotManager(params))
+	if err != nil || len(r.BotRuleID) == 0 {
+		return err
 	}
+
+	err = wafSvc.Bot.DeleteBotRuleSet(bot.DeleteBotRuleSetParams{
+		AccountNumber: cfg.AccountNumber,
+		BotRuleSetID:  r.BotRuleID,
+	})
+
+	return ignoreNotFound(err)
+}
+
+func newBotManagerService(cfg config.Config) *waf_bot_manager.Service {
+	// bug work around - need to clear out IDS credentials
+	sdkCfgNoIDS := cfg.SDKConfig
+	sdkCfgNoIDS.IDSCredentials = edgecast.IDSCredentials{}
+
+	return internal.Check(waf_bot_manager.New(sdkCfgNoIDS))
 }
 
 func createBotManager(
 	wafSvc *waf.WafService,
 	wbmSvc *waf_bot_manager.Service,
 	accountNumber string,
-) string {
-	botRuleID := createBotRule(wafSvc, accountNumber)
+) (id, botRuleID string) {
+	botRuleID = createBotRule(wafSvc, accountNumber)
 
 	customerID := accountNumber
 	base64Body := "PCFET0NUWVBFIGh0bWw+CjxodG1sIGxhbmc9ImVuIj4KICA8aGVhZD4KICAgIDxtZXRhIGNoYXJzZXQ9InV0Zi04Ii8+CiAgICA8dGl0bGU+NDAzIHVuYXV0aG9yaXplZDwvdGl0bGU+CiAgICA8bWV0YSBjb250ZW50PSI0MDMgdW5hdXRob3JpemVkIiBwcm9wZXJ0eT0ib2c6dGl0bGUiLz4KICAgIDxtZXRhIGNvbnRlbnQ9IndpZHRoPWRldmljZS13aWR0aCwgaW5pdGlhbC1zY2FsZT0xIiBuYW1lPSJ2aWV3cG9ydCIvPgogICAgPHN0eWxlPgogICAgICBib2R5IHsKICAgICAgICBmb250LWZhbWlseTogc2Fucy1zZXJpZjsKICAgICAgICBsaW5lLWhlaWdodDogMS4yOwogICAgICAgIGZvbnQtc2l6ZTogMThweDsKICAgICAgfQogICAgICBzZWN0aW9uIHsKICAgICAgICBtYXJnaW46IDAgYXV0bzsKICAgICAgICBtYXJnaW4tdG9wOiAxMzBweDsKICAgICAgICB3aWR0aDogNzUlOwogICAgICB9CiAgICAgIGgxIHsKICAgICAgICBmb250LXNpemU6IDUwcHg7CiAgICAgICAgbGluZS1oZWlnaHQ6IDQ1cHg7CiAgICAgICAgZm9udC13ZWlnaHQ6IDcwMDsKICAgICAgICBtYXJnaW4tYm90dG9tOiA3NXB4OwogICAgICAgIHdoaXRlLXNwYWNlOiBub3dyYXA7CiAgICAgIH0KICAgICAgcCB7CiAgICAgICAgbWFyZ2luLWJvdHRvbTogMTBweDsKICAgICAgfQogICAgICBzbWFsbCB7CiAgICAgICAgZm9udC1zaXplOiA4MCU7CiAgICAgICAgY29sb3I6ICMzMzM7CiAgICAgIH0KICAgICAgZm9vdGVyIHsKICAgICAgICBwb3NpdGlvbjogZml4ZWQ7CiAgICAgICAgYm90dG9tOiAwOwogICAgICAgIGxlZnQ6IDA7CiAgICAgICAgcGFkZGluZzogLjdyZW0gMCAuN3JlbSA0cmVtOwogICAgICAgIHdpZHRoOiAxMDAlOwogICAgICAgIGJhY2tncm91bmQ6ICBJbmRpZ287CiAgICAgIH0KICAgICAgZm9vdGVyIGEgewogICAgICAgIGNvbG9yOiB3aGl0ZTsKICAgICAgICBtYXJnaW4tbGVmdDogNDBweDsKICAgICAgICB0ZXh0LWRlY29yYXRpb246IG5vbmU7CiAgICAgIH0KICAgICAgLmQtbm9uZSB7CiAgICAgICAgZGlzcGxheTogbm9uZSAhaW1wb3J0YW50OwogICAgICB9CiAgICAgIC5zZWN0aW9uLWVycm9yIHsKICAgICAgICBjb2xvcjogI2JkMjQyNjsKICAgICAgfQogICAgICAubG9hZGluZyB7CiAgICAgICAgYW5pbWF0aW9uOiAzcyBpbmZpbml0ZSBzbGlkZWluOwogICAgICB9CiAgICAgIEBrZXlmcmFtZXMgc2xpZGVpbiB7CiAgICAgICAgZnJvbSB7CiAgICAgICAgICBtYXJnaW4tbGVmdDogMTAlOwogICAgICAgICAgY29sb3I6IHJnYigxMzQsIDUxLCAyNTUpOwogICAgICAgIH0KCiAgICAgICAgMzAlIHsKICAgICAgICAgIGNvbG9yOiByZ2IoMTM0LCA1MSwgMjU1KTsKICAgICAgICB9CgogICAgICAgIHRvIHsKICAgICAgICAgIG1hcmdpbi1sZWZ0OiAwJTsKICAgICAgICAgIGNvbG9yOiBibGFjazsKICAgICAgICB9CiAgICAgIH0KICAgIDwvc3R5bGU+CiAgPC9oZWFkPgoKICA8Ym9keSBvbmxvYWQ9Im9ubG9hZENvb2tpZUNoZWNrKCkiPgogICAge3tCT1RfSlN9fQogICAgPHNlY3Rpb24+CiAgICAgIDxoMT4KICAgICAgICBWYWxpZGF0aW5nIHlvdXIgYnJvd3NlciAtIGN1c3RvbSBjaGFsbGVuZ2UgcGFnZQogICAgICAgIDxzcGFuIGNsYXNzPSJsb2FkaW5nIj4uPC9zcGFuPjxzcGFuIGNsYXNzPSJsb2FkaW5nIj4uPC9zcGFuPjxzcGFuIGNsYXNzPSJsb2FkaW5nIj4uPC9zcGFuPgogICAgICA8L2gxPgoKICAgICAgPG5vc2NyaXB0PgogICAgICAgIDxoNCBjbGFzcz0ic2VjdGlvbi1lcnJvciI+UGxlYXNlIHR1cm4gSmF2YVNjcmlwdCBvbiBhbmQgcmVsb2FkIHRoZSBwYWdlLjwvaDQ+CiAgICAgIDwvbm9zY3JpcHQ+CgogICAgICA8ZGl2IGlkPSJjb29raWUtZXJyb3IiIGNsYXNzPSJkLW5vbmUiPgogICAgICAgIDxoNCBjbGFzcz0ic2VjdGlvbi1lcnJvciI+UGxlYXNlIGVuYWJsZSBjb29raWVzIGFuZCByZWxvYWQgdGhlIHBhZ2UuPC9oND4KICAgICAgPC9kaXY+CgogICAgICA8cD5UaGlzIG1heSB0YWtlIHVwIHRvIDUgc2Vjb25kczwvcD4KCiAgICAgIDxzbWFsbD5FdmVudCBJRDoge3tFVkVOVF9JRH19PC9zbWFsbD4KICAgIDwvc2VjdGlvbj4KCiAgICA8Zm9vdGVyPgogICAgICA8cD4KICAgICAgICA8YSBocmVmPSJodHRwczovL3d3dy5lZGdlY2FzdC5jb20vc2VjdXJpdHkvIj5Qb3dlcmVkIGJ5IEVkZ2lvPC9hPgogICAgICA8L3A+CiAgICA8L2Zvb3Rlcj4KICA8L2JvZHk+CiAgPHNjcmlwdD4KICAgIGZ1bmN0aW9uIG9ubG9hZENvb2tpZUNoZWNrKCkgewogICAgICBpZiAoIW5hdmlnYXRvci5jb29raWVFbmFibGVkKSB7CiAgICAgICAgZG9jdW1lbnQuZ2V0RWxlbWVudEJ5SWQoJ2Nvb2tpZS1lcnJvcicpLmNsYXNzTGlzdC5yZW1vdmUoJ2Qtbm9uZScpOwogICAgICB9CiAgICB9CiAgPC9zY3JpcHQ+CjwvaHRtbD4="
@@ -121,8 +176,6 @@ func createBotManager(
 
 	// Need to wait for the bot rule processing - retry until completeion.
 
-	var id string
-
 	for i := 1; i <= ruleProcessingMaxRetries; i++ {
 		resp, err := wbmSvc.BotManagers.CreateBotManager(params)
 		if err == nil {
@@ -141,5 +194,5 @@ func createBotManager(
 		time.Sleep(rulewWaitTimeSeconds * time.Second)
 	}
 
-	return id
+	return id, botRuleID
 }
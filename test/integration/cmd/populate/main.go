@@ -15,5 +15,5 @@ func main() {
 		Config: config.NewConfig(),
 	}
 
-	dp.Populate()
+	dp.Run()
 }
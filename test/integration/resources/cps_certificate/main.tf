@@ -29,6 +29,11 @@ variable "account_number" {
   type = string
 }
 
+variable "import_ids" {
+  type    = map(string)
+  default = {}
+}
+
 variable "MCC_ACCOUNT_EMAIL" {
   type = string
   default = "" # This prevents being prompted during integration test run
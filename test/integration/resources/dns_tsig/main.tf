@@ -31,6 +31,11 @@ variable "account_number" {
   type = string
 }
 
+variable "import_ids" {
+  type    = map(string)
+  default = {}
+}
+
 ##########################################
 # Providers
 ##########################################